@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/genai"
+)
+
+// LiveRequest is a request sent to the model during a live run.
+//
+// Only one of the fields should be set.
+type LiveRequest struct {
+	// Content is sent to the model as a complete turn.
+	Content *genai.Content `json:"content,omitempty"`
+	// Blob is a chunk of realtime audio or video input.
+	Blob *genai.Blob `json:"blob,omitempty"`
+	// ActivityStart signals the start of the user activity, e.g. speech.
+	// Only used when the automatic activity detection is disabled.
+	ActivityStart bool `json:"activityStart,omitempty"`
+	// ActivityEnd signals the end of the user activity.
+	ActivityEnd bool `json:"activityEnd,omitempty"`
+	// Close ends the live run.
+	Close bool `json:"close,omitempty"`
+}
+
+// LiveRequestQueue is the queue of requests sent to the model during a live
+// run. The requests are sent in order. Thread-safe.
+//
+// The queue is unbounded, sending never blocks.
+type LiveRequestQueue struct {
+	mu       sync.Mutex
+	requests []*LiveRequest
+	// ready is signaled when a request is added.
+	ready chan struct{}
+}
+
+// NewLiveRequestQueue creates an empty [LiveRequestQueue].
+func NewLiveRequestQueue() *LiveRequestQueue {
+	return &LiveRequestQueue{ready: make(chan struct{}, 1)}
+}
+
+// Send adds the request to the queue.
+func (q *LiveRequestQueue) Send(req *LiveRequest) {
+	q.mu.Lock()
+	q.requests = append(q.requests, req)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// SendContent sends the content to the model as a complete turn.
+func (q *LiveRequestQueue) SendContent(content *genai.Content) {
+	q.Send(&LiveRequest{Content: content})
+}
+
+// SendRealtime sends a chunk of realtime audio or video input.
+func (q *LiveRequestQueue) SendRealtime(blob *genai.Blob) {
+	q.Send(&LiveRequest{Blob: blob})
+}
+
+// SendActivityStart signals the start of the user activity.
+func (q *LiveRequestQueue) SendActivityStart() {
+	q.Send(&LiveRequest{ActivityStart: true})
+}
+
+// SendActivityEnd signals the end of the user activity.
+func (q *LiveRequestQueue) SendActivityEnd() {
+	q.Send(&LiveRequest{ActivityEnd: true})
+}
+
+// Close ends the live run once the requests sent before are processed.
+func (q *LiveRequestQueue) Close() {
+	q.Send(&LiveRequest{Close: true})
+}
+
+// Receive removes and returns the oldest request of the queue, waiting for
+// one to be sent if the queue is empty. It returns the context error if the
+// context is done first.
+func (q *LiveRequestQueue) Receive(ctx context.Context) (*LiveRequest, error) {
+	for {
+		q.mu.Lock()
+		if len(q.requests) > 0 {
+			req := q.requests[0]
+			q.requests[0] = nil
+			q.requests = q.requests[1:]
+			more := len(q.requests) > 0
+			q.mu.Unlock()
+			if more {
+				// Keep the signal for the next receiver.
+				select {
+				case q.ready <- struct{}{}:
+				default:
+				}
+			}
+			return req, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.ready:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+)
+
+func TestLiveRequestQueue(t *testing.T) {
+	q := NewLiveRequestQueue()
+	blob := &genai.Blob{MIMEType: "audio/pcm", Data: []byte{1}}
+	q.SendActivityStart()
+	q.SendRealtime(blob)
+	q.SendActivityEnd()
+	q.SendContent(genai.NewContentFromText("hi", genai.RoleUser))
+	q.Close()
+
+	want := []*LiveRequest{
+		{ActivityStart: true},
+		{Blob: blob},
+		{ActivityEnd: true},
+		{Content: genai.NewContentFromText("hi", genai.RoleUser)},
+		{Close: true},
+	}
+	var got []*LiveRequest
+	for range want {
+		req, err := q.Receive(t.Context())
+		if err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+		got = append(got, req)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Receive() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLiveRequestQueue_ReceiveWaits(t *testing.T) {
+	q := NewLiveRequestQueue()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Receive() on empty queue error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	go q.Close()
+	req, err := q.Receive(t.Context())
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if !req.Close {
+		t.Errorf("Receive() = %+v, want close request", req)
+	}
+}
@@ -22,16 +22,24 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/codeexecutor"
 	agentinternal "google.golang.org/adk/internal/agent"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/llminternal"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 )
 
 // New is a constructor for LLMAgent.
 func New(cfg Config) (agent.Agent, error) {
+	switch cfg.TruncatedResponse {
+	case "", TruncatedResponseReturn, TruncatedResponseContinue, TruncatedResponseError:
+	default:
+		return nil, fmt.Errorf("invalid TruncatedResponse %q", cfg.TruncatedResponse)
+	}
+
 	beforeModelCallbacks := make([]llminternal.BeforeModelCallback, 0, len(cfg.BeforeModelCallbacks))
 	for _, c := range cfg.BeforeModelCallbacks {
 		beforeModelCallbacks = append(beforeModelCallbacks, llminternal.BeforeModelCallback(c))
@@ -70,6 +78,11 @@ func New(cfg Config) (agent.Agent, error) {
 		beforeToolCallbacks:   beforeToolCallbacks,
 		afterToolCallbacks:    afterToolCallbacks,
 		onToolErrorCallbacks:  onToolErrorCallback,
+		retryPolicy:           cfg.RetryPolicy,
+		rateLimiter:           cfg.RateLimiter,
+		truncatedResponse:     string(cfg.TruncatedResponse),
+		maxContinuations:      cfg.MaxContinuations,
+		continuationPrompt:    cfg.ContinuationPrompt,
 		instruction:           cfg.Instruction,
 		inputSchema:           cfg.InputSchema,
 		outputSchema:          cfg.OutputSchema,
@@ -90,6 +103,8 @@ func New(cfg Config) (agent.Agent, error) {
 			GlobalInstruction:         cfg.GlobalInstruction,
 			GlobalInstructionProvider: llminternal.InstructionProvider(cfg.GlobalInstructionProvider),
 			OutputKey:                 cfg.OutputKey,
+			CodeExecutor:              cfg.CodeExecutor,
+			Planner:                   cfg.Planner,
 		},
 	}
 
@@ -172,6 +187,31 @@ type Config struct {
 
 	OnModelErrorCallbacks []OnModelErrorCallback
 
+	// RetryPolicy retries the model calls failed with transient errors, e.g.
+	// rate limits or server errors, before the OnModelErrorCallbacks are
+	// called.
+	//
+	// It takes over the retry policy of the runner if both are set.
+	RetryPolicy *model.RetryPolicy
+	// RateLimiter limits the rate of the model calls. Share it between the
+	// agents calling the same models.
+	//
+	// It takes over the rate limiter of the runner if both are set.
+	RateLimiter *model.RateLimiter
+
+	// TruncatedResponse controls the handling of the model responses
+	// truncated, e.g. because they reached the max output tokens of the
+	// GenerateContentConfig, or because the stream ended with partial
+	// responses. Defaults to TruncatedResponseReturn.
+	TruncatedResponse TruncatedResponseHandling
+	// MaxContinuations is the maximum number of times a response is
+	// continued with TruncatedResponseContinue. Defaults to 3.
+	MaxContinuations int
+	// ContinuationPrompt is the user message asking the model to continue a
+	// truncated response with TruncatedResponseContinue. It is not saved in
+	// the session. A default prompt is used if empty.
+	ContinuationPrompt string
+
 	// Instruction is set for the LLM model guiding the agent's behavior.
 	//
 	// The string is treated as a template:
@@ -266,6 +306,21 @@ type Config struct {
 	// - Extracts agent reply for later use, such as in tools, callbacks, etc.
 	// - Connects agents to coordinate with each other.
 	OutputKey string
+
+	// CodeExecutor allows the agent to execute code blocks from model
+	// responses.
+	//
+	// For example, use codeexecutor.BuiltIn to let Gemini models execute the
+	// code on the model side, or localexecutor.New to run the code in a local
+	// subprocess. The execution results are sent back to the model.
+	CodeExecutor codeexecutor.CodeExecutor
+
+	// Planner instructs the agent to make a plan and execute it step by step.
+	//
+	// Use planner.NewBuiltInPlanner to rely on the model's built-in thinking,
+	// or planner.NewPlanReActPlanner to prompt the model to plan, reason and
+	// act explicitly.
+	Planner planner.Planner
 }
 
 // BeforeModelCallback that is called before sending a request to the model.
@@ -313,6 +368,23 @@ type AfterToolCallback func(ctx tool.Context, tool tool.Tool, args, result map[s
 // is replaced with the returned response/error.
 type OnToolErrorCallback func(ctx tool.Context, tool tool.Tool, args map[string]any, err error) (map[string]any, error)
 
+// TruncatedResponseHandling controls the handling of the truncated model
+// responses by llmagent.
+type TruncatedResponseHandling string
+
+const (
+	// TruncatedResponseReturn returns the truncated response as the final
+	// response of the agent.
+	TruncatedResponseReturn TruncatedResponseHandling = llminternal.TruncatedResponseReturn
+	// TruncatedResponseContinue asks the model to continue the truncated
+	// response, up to MaxContinuations times. The continued response is
+	// returned as a separate event.
+	TruncatedResponseContinue TruncatedResponseHandling = llminternal.TruncatedResponseContinue
+	// TruncatedResponseError fails the agent run with a
+	// [model.TruncatedResponseError].
+	TruncatedResponseError TruncatedResponseHandling = llminternal.TruncatedResponseError
+)
+
 // IncludeContents controls what parts of prior conversation history is received by llmagent.
 type IncludeContents string
 
@@ -333,6 +405,11 @@ type llmAgent struct {
 	afterModelCallbacks   []llminternal.AfterModelCallback
 	instruction           string
 	onModelErrorCallbacks []llminternal.OnModelErrorCallback
+	retryPolicy           *model.RetryPolicy
+	rateLimiter           *model.RateLimiter
+	truncatedResponse     string
+	maxContinuations      int
+	continuationPrompt    string
 
 	beforeToolCallbacks  []llminternal.BeforeToolCallback
 	afterToolCallbacks   []llminternal.AfterToolCallback
@@ -364,9 +441,14 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 		BeforeModelCallbacks:  a.beforeModelCallbacks,
 		AfterModelCallbacks:   a.afterModelCallbacks,
 		OnModelErrorCallbacks: a.onModelErrorCallbacks,
+		RetryPolicy:           a.retryPolicy,
+		RateLimiter:           a.rateLimiter,
 		BeforeToolCallbacks:   a.beforeToolCallbacks,
 		AfterToolCallbacks:    a.afterToolCallbacks,
 		OnToolErrorCallbacks:  a.onToolErrorCallbacks,
+		TruncatedResponse:     a.truncatedResponse,
+		MaxContinuations:      a.maxContinuations,
+		ContinuationPrompt:    a.continuationPrompt,
 	}
 
 	return func(yield func(*session.Event, error) bool) {
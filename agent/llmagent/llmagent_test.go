@@ -15,6 +15,7 @@
 package llmagent_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"iter"
@@ -28,9 +29,13 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/codeexecutor"
 	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/fakellm"
 	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/planner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
@@ -1074,6 +1079,115 @@ func TestAgentTransfer(t *testing.T) {
 	//   - test_auto_to_loop
 }
 
+type fakeCodeExecutor struct {
+	codes []string
+}
+
+func (e *fakeCodeExecutor) Config() codeexecutor.Config {
+	return codeexecutor.DefaultConfig()
+}
+
+func (e *fakeCodeExecutor) Execute(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+	e.codes = append(e.codes, in.Code)
+	return &codeexecutor.Result{Stdout: "3"}, nil
+}
+
+func TestCodeExecutor(t *testing.T) {
+	executor := &fakeCodeExecutor{}
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromText("Let me compute it.\n```python\nprint(1 + 2)\n```\nThe result is 4.", genai.RoleModel),
+			genai.NewContentFromText("The result is 3.", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:         "agent",
+		Model:        mockModel,
+		CodeExecutor: executor,
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+
+	runner := testutil.NewTestAgentRunner(t, a)
+	parts, err := testutil.CollectParts(runner.Run(t, "session1", "what is 1 + 2?"))
+	if err != nil {
+		t.Fatalf("agent returned error: %v", err)
+	}
+
+	wantParts := []*genai.Part{
+		genai.NewPartFromText("Let me compute it.\n"),
+		genai.NewPartFromExecutableCode("print(1 + 2)", genai.LanguagePython),
+		genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "Code execution result:\n3\n"),
+		genai.NewPartFromText("The result is 3."),
+	}
+	if diff := cmp.Diff(wantParts, parts); diff != "" {
+		t.Errorf("unexpected parts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"print(1 + 2)"}, executor.codes); diff != "" {
+		t.Errorf("unexpected executed code (-want +got):\n%s", diff)
+	}
+
+	if len(mockModel.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(mockModel.Requests))
+	}
+	wantContents := []*genai.Content{
+		genai.NewContentFromText("what is 1 + 2?", genai.RoleUser),
+		{Role: genai.RoleModel, Parts: []*genai.Part{
+			genai.NewPartFromText("Let me compute it.\n"),
+			genai.NewPartFromText("```tool_code\nprint(1 + 2)\n```"),
+		}},
+		genai.NewContentFromText("```tool_output\nCode execution result:\n3\n\n```", genai.RoleUser),
+	}
+	if diff := cmp.Diff(wantContents, mockModel.Requests[1].Contents); diff != "" {
+		t.Errorf("unexpected second request contents (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlanner(t *testing.T) {
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromText(planner.PlanningTag+" 1. answer "+planner.FinalAnswerTag+" 42", genai.RoleModel),
+			genai.NewContentFromText("43", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:    "agent",
+		Model:   mockModel,
+		Planner: planner.NewPlanReActPlanner(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+
+	runner := testutil.NewTestAgentRunner(t, a)
+	for _, msg := range []string{"question", "another question"} {
+		if _, err := testutil.CollectParts(runner.Run(t, "session1", msg)); err != nil {
+			t.Fatalf("agent returned error: %v", err)
+		}
+	}
+
+	if len(mockModel.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(mockModel.Requests))
+	}
+	instruction := strings.Join(utils.TextParts(mockModel.Requests[0].Config.SystemInstruction), "\n")
+	if !strings.Contains(instruction, planner.PlanningTag) {
+		t.Errorf("system instruction %q does not contain the planning instruction", instruction)
+	}
+	// Thoughts of the previous planning response are sent back to the model.
+	wantContents := []*genai.Content{
+		genai.NewContentFromText("question", genai.RoleUser),
+		{Role: genai.RoleModel, Parts: []*genai.Part{
+			genai.NewPartFromText(planner.PlanningTag + " 1. answer " + planner.FinalAnswerTag),
+			genai.NewPartFromText(" 42"),
+		}},
+		genai.NewContentFromText("another question", genai.RoleUser),
+	}
+	if diff := cmp.Diff(wantContents, mockModel.Requests[1].Contents); diff != "" {
+		t.Errorf("unexpected second request contents (-want +got):\n%s", diff)
+	}
+}
+
 func newGeminiModel(t *testing.T, modelName string, transport http.RoundTripper) model.LLM {
 	cfg := &genai.ClientConfig{
 		HTTPClient: &http.Client{Transport: transport},
@@ -1096,3 +1210,128 @@ type roundTripperFunc func(*http.Request) (*http.Response, error)
 func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return fn(req)
 }
+
+func TestTruncatedResponse(t *testing.T) {
+	partial := func(text string) *model.LLMResponse {
+		return &model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleModel), Partial: true}
+	}
+	maxTokens := func(text string) []*model.LLMResponse {
+		return []*model.LLMResponse{{Content: genai.NewContentFromText(text, genai.RoleModel), FinishReason: genai.FinishReasonMaxTokens}}
+	}
+	continued := func(text string) []fakellm.Matcher {
+		return []fakellm.Matcher{fakellm.Contents(
+			genai.NewContentFromText("write", genai.RoleUser),
+			genai.NewContentFromText(text, genai.RoleModel),
+			genai.NewContentFromText("go on", genai.RoleUser),
+		)}
+	}
+
+	tests := []struct {
+		name       string
+		handling   llmagent.TruncatedResponseHandling
+		maxCont    int
+		turns      []fakellm.Turn
+		want       []string
+		wantReason genai.FinishReason
+		wantErr    bool
+	}{
+		{
+			name:  "stream ended with partial responses",
+			turns: []fakellm.Turn{{Responses: []*model.LLMResponse{partial("Hel"), partial("lo")}}},
+			want:  []string{"Hel (partial)", "lo (partial)", "Hello"},
+		},
+		{
+			name:  "max tokens",
+			turns: []fakellm.Turn{{Responses: maxTokens("Hel")}},
+			want:  []string{"Hel"},
+		},
+		{
+			name:       "error on max tokens",
+			handling:   llmagent.TruncatedResponseError,
+			turns:      []fakellm.Turn{{Responses: maxTokens("Hel")}},
+			want:       []string{"Hel"},
+			wantReason: genai.FinishReasonMaxTokens,
+			wantErr:    true,
+		},
+		{
+			name:     "error on partial responses",
+			handling: llmagent.TruncatedResponseError,
+			turns:    []fakellm.Turn{{Responses: []*model.LLMResponse{partial("Hel")}}},
+			want:     []string{"Hel (partial)", "Hel"},
+			wantErr:  true,
+		},
+		{
+			name:     "continue on max tokens",
+			handling: llmagent.TruncatedResponseContinue,
+			turns: []fakellm.Turn{
+				{Responses: maxTokens("Hel")},
+				{Expect: continued("Hel"), Responses: fakellm.Text("lo")},
+			},
+			want: []string{"Hel", "lo"},
+		},
+		{
+			name:     "continue on partial responses",
+			handling: llmagent.TruncatedResponseContinue,
+			turns: []fakellm.Turn{
+				{Responses: []*model.LLMResponse{partial("Hel")}},
+				{Expect: continued("Hel"), Responses: fakellm.Text("lo")},
+			},
+			want: []string{"Hel (partial)", "Hel", "lo"},
+		},
+		{
+			name:     "max continuations",
+			handling: llmagent.TruncatedResponseContinue,
+			maxCont:  1,
+			turns: []fakellm.Turn{
+				{Responses: maxTokens("Hel")},
+				{Expect: continued("Hel"), Responses: maxTokens("lo")},
+			},
+			want: []string{"Hel", "lo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := llmagent.New(llmagent.Config{
+				Name:               "writer",
+				Model:              fakellm.New(t, tt.turns...),
+				TruncatedResponse:  tt.handling,
+				MaxContinuations:   tt.maxCont,
+				ContinuationPrompt: "go on",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			runner := testutil.NewTestAgentRunner(t, a)
+			var got []string
+			var gotErr error
+			for ev, err := range runner.RunContentWithConfig(t, "session", genai.NewContentFromText("write", genai.RoleUser), agent.RunConfig{StreamingMode: agent.StreamingModeSSE}) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				s := ev.Content.Parts[0].Text
+				if ev.Partial {
+					s += " (partial)"
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+			}
+			var truncatedErr *model.TruncatedResponseError
+			if errors.As(gotErr, &truncatedErr) != tt.wantErr {
+				t.Fatalf("Run() error = %v, want truncated response error %v", gotErr, tt.wantErr)
+			}
+			if tt.wantErr && truncatedErr.FinishReason != tt.wantReason {
+				t.Errorf("Run() error finish reason = %q, want %q", truncatedErr.FinishReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidTruncatedResponse(t *testing.T) {
+	if _, err := llmagent.New(llmagent.Config{Name: "writer", TruncatedResponse: "ignore"}); err == nil {
+		t.Error("New() succeeded, want error")
+	}
+}
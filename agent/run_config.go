@@ -14,6 +14,8 @@
 
 package agent
 
+import "google.golang.org/genai"
+
 // StreamingMode defines the streaming mode for agent execution.
 type StreamingMode string
 
@@ -23,6 +25,10 @@ const (
 	// StreamingModeSSE enables server-sent events streaming, one-way, where
 	// LLM response parts are streamed immediately as they are generated.
 	StreamingModeSSE StreamingMode = "sse"
+	// StreamingModeBidi enables live bidirectional streaming, where the user
+	// input (e.g. audio or video) is streamed to the LLM while it responds.
+	// It is used by runner.Runner.RunLive.
+	StreamingModeBidi StreamingMode = "bidi"
 )
 
 // RunConfig controls runtime behavior of an agent.
@@ -32,4 +38,80 @@ type RunConfig struct {
 	// If true, ADK runner will save each part of the user input that is a blob
 	// (e.g., images, files) as an artifact.
 	SaveInputBlobsAsArtifacts bool
+	// Budget limits the tokens and the cost of the model calls of the
+	// invocation. It is not enforced for the live runs.
+	Budget *Budget
+	// MaxLLMCalls limits the number of model calls of the invocation,
+	// across all the agents of the tree and the agents run as tools.
+	// Defaults to [DefaultMaxLLMCalls], a negative value disables the limit.
+	// It is not enforced for the live runs.
+	MaxLLMCalls int
+	// MaxToolIterations limits the number of model responses calling tools
+	// in the invocation, like MaxLLMCalls. Zero means no limit.
+	MaxToolIterations int
+
+	// The fields below only apply to the live (bidi streaming) runs.
+
+	// ResponseModalities are the output modalities of the model, e.g.
+	// audio. The model default is used if empty.
+	ResponseModalities []genai.Modality
+	// SpeechConfig configures the speech generated by the model.
+	SpeechConfig *genai.SpeechConfig
+	// InputAudioTranscription enables the transcription of the user audio.
+	InputAudioTranscription *genai.AudioTranscriptionConfig
+	// OutputAudioTranscription enables the transcription of the model audio.
+	OutputAudioTranscription *genai.AudioTranscriptionConfig
+	// RealtimeInputConfig configures the handling of the realtime input,
+	// e.g. the voice activity detection.
+	RealtimeInputConfig *genai.RealtimeInputConfig
+}
+
+// DefaultMaxLLMCalls is the default limit of model calls of an invocation,
+// see [RunConfig.MaxLLMCalls].
+const DefaultMaxLLMCalls = 500
+
+// The error codes of the events ending the invocations which exceeded their
+// limits.
+const (
+	// ErrorCodeBudgetExceeded is set when the [Budget] is exceeded.
+	ErrorCodeBudgetExceeded = "BUDGET_EXCEEDED"
+	// ErrorCodeMaxLLMCallsExceeded is set when [RunConfig.MaxLLMCalls] is
+	// exceeded.
+	ErrorCodeMaxLLMCallsExceeded = "MAX_LLM_CALLS_EXCEEDED"
+	// ErrorCodeMaxToolIterationsExceeded is set when
+	// [RunConfig.MaxToolIterations] is exceeded.
+	ErrorCodeMaxToolIterationsExceeded = "MAX_TOOL_ITERATIONS_EXCEEDED"
+)
+
+// Budget limits the tokens and the cost of the model calls of an
+// invocation, across all the agents of the tree.
+//
+// It is checked before each model call, with the usage metadata of the
+// previous responses plus the prompt token count of the request estimated
+// locally, and after each response, with its usage metadata. Once the
+// budget is exceeded, the invocation ends with an event holding the
+// [ErrorCodeBudgetExceeded] error code.
+type Budget struct {
+	// MaxTokens is the maximum total token count of the model calls. Zero
+	// means no limit.
+	MaxTokens int64
+	// MaxCost is the maximum cost of the model calls, computed with the
+	// Prices. Zero means no limit.
+	MaxCost float64
+	// Prices of the tokens by model name. The tokens of the models without
+	// a price are free.
+	Prices map[string]TokenPrice
+	// CountTokens counts the prompt tokens of the requests with the models
+	// implementing model.TokenCounter, instead of estimating them. It is
+	// exact but, e.g. with Gemini, costs an additional API call per model
+	// call.
+	CountTokens bool
+}
+
+// TokenPrice is the price of a model per million tokens.
+type TokenPrice struct {
+	// Input is the price of the prompt tokens.
+	Input float64
+	// Output is the price of the response tokens, including the thoughts.
+	Output float64
 }
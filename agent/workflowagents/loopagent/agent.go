@@ -19,9 +19,11 @@ package loopagent
 import (
 	"fmt"
 	"iter"
+	"slices"
 
 	"google.golang.org/adk/agent"
 	agentinternal "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/internal/agent/checkpoint"
 	"google.golang.org/adk/session"
 )
 
@@ -72,13 +74,38 @@ type loopAgent struct {
 	maxIterations uint
 }
 
-func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
-	count := a.maxIterations
+// Keys of the checkpoint state of the LoopAgent in the resumable invocations.
+const (
+	currentSubAgentKey = "current_sub_agent"
+	timesLoopedKey     = "times_looped"
+)
 
+func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
-		for {
-			shouldExit := false
-			for _, subAgent := range ctx.Agent().SubAgents() {
+		subAgents := ctx.Agent().SubAgents()
+		checkpoints := checkpoint.FromContext(ctx)
+
+		startIndex, timesLooped, resuming := 0, uint(0), false
+		if checkpoints != nil {
+			startIndex, timesLooped, resuming = resumePoint(checkpoints.State(ctx.Agent().Name()), subAgents)
+		}
+
+		for a.maxIterations == 0 || timesLooped < a.maxIterations {
+			for _, subAgent := range subAgents[startIndex:] {
+				if checkpoints != nil && !resuming {
+					state := map[string]any{currentSubAgentKey: subAgent.Name(), timesLoopedKey: timesLooped}
+					if !yield(checkpoints.Checkpoint(ctx, state), nil) {
+						return
+					}
+				}
+				// The sub-agent finished before the invocation stopped.
+				skip := resuming && checkpoints.Ended(subAgent.Name())
+				resuming = false
+				if skip {
+					continue
+				}
+
+				shouldExit, shouldPause := false, false
 				for event, err := range subAgent.Run(ctx) {
 					// TODO: ensure consistency -- if there's an error, return and close iterator, verify everywhere in ADK.
 					if !yield(event, err) {
@@ -88,18 +115,51 @@ func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, e
 					if event != nil && event.Actions.Escalate {
 						shouldExit = true
 					}
+					// The resumable invocations pause on the long-running
+					// tools, they are resumed once the tools respond.
+					if checkpoints != nil && event != nil && len(event.LongRunningToolIDs) > 0 {
+						shouldPause = true
+					}
 				}
-				if shouldExit {
+				if shouldPause {
 					return
 				}
-			}
-
-			if count > 0 {
-				count--
-				if count == 0 {
+				if shouldExit {
+					if checkpoints != nil {
+						yield(checkpoints.End(ctx), nil)
+					}
 					return
 				}
 			}
+			startIndex = 0
+			timesLooped++
+		}
+
+		if checkpoints != nil {
+			yield(checkpoints.End(ctx), nil)
 		}
 	}
 }
+
+// resumePoint returns the index of the sub-agent and the iteration to resume
+// the LoopAgent from its checkpoint state. It reports whether the LoopAgent
+// resumes a run.
+func resumePoint(state map[string]any, subAgents []agent.Agent) (int, uint, bool) {
+	name, ok := state[currentSubAgentKey].(string)
+	if !ok {
+		return 0, 0, false
+	}
+	index := slices.IndexFunc(subAgents, func(a agent.Agent) bool { return a.Name() == name })
+	if index < 0 {
+		return 0, 0, false
+	}
+	var timesLooped uint
+	// The state restored from the session services holds JSON numbers.
+	switch v := state[timesLoopedKey].(type) {
+	case uint:
+		timesLooped = v
+	case float64:
+		timesLooped = uint(v)
+	}
+	return index, timesLooped, true
+}
@@ -16,8 +16,13 @@
 package parallelagent
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"iter"
+	"log"
+	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -31,8 +36,41 @@ import (
 type Config struct {
 	// Basic agent setup.
 	AgentConfig agent.Config
+
+	// MaxConcurrency limits the number of sub-agents running at once. The
+	// other sub-agents wait for a running sub-agent to finish.
+	// If MaxConcurrency == 0, all the sub-agents run at once.
+	MaxConcurrency int
+	// SubAgentTimeout limits the run time of each sub-agent. The sub-agents
+	// running out of time fail with context.DeadlineExceeded.
+	// If SubAgentTimeout == 0, the sub-agents have no time limit.
+	SubAgentTimeout time.Duration
+	// FailurePolicy decides how the failures of the sub-agents affect the
+	// run. Defaults to FailFast.
+	FailurePolicy FailurePolicy
+	// RequiredSuccesses is the number of sub-agents which must succeed with
+	// the RequireN failure policy.
+	RequiredSuccesses int
 }
 
+// FailurePolicy decides how the ParallelAgent handles the failures of its
+// sub-agents.
+type FailurePolicy int
+
+const (
+	// FailFast stops the other sub-agents on the first failure. The errors
+	// are yielded as they happen.
+	FailFast FailurePolicy = iota
+	// CollectAll lets the other sub-agents run to completion on failures.
+	// The errors are yielded joined, after all the sub-agents finished.
+	CollectAll
+	// RequireN lets the other sub-agents run to completion on failures, as
+	// long as Config.RequiredSuccesses sub-agents can still succeed. The run
+	// fails with the joined errors if less sub-agents succeeded, otherwise
+	// the failures are only logged.
+	RequireN
+)
+
 // New creates a ParallelAgent.
 //
 // Parallel agent runs its sub-agents in parallel in isolated manner.
@@ -45,8 +83,29 @@ func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Run != nil {
 		return nil, fmt.Errorf("ParallelAgent doesn't allow custom Run implementations")
 	}
+	if cfg.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("MaxConcurrency must not be negative, got %d", cfg.MaxConcurrency)
+	}
+	if cfg.SubAgentTimeout < 0 {
+		return nil, fmt.Errorf("SubAgentTimeout must not be negative, got %v", cfg.SubAgentTimeout)
+	}
+	switch cfg.FailurePolicy {
+	case FailFast, CollectAll:
+	case RequireN:
+		if n := len(cfg.AgentConfig.SubAgents); cfg.RequiredSuccesses < 1 || cfg.RequiredSuccesses > n {
+			return nil, fmt.Errorf("RequiredSuccesses must be between 1 and the number of sub-agents %d, got %d", n, cfg.RequiredSuccesses)
+		}
+	default:
+		return nil, fmt.Errorf("unknown failure policy %d", cfg.FailurePolicy)
+	}
 
-	cfg.AgentConfig.Run = run
+	parallelAgentImpl := &parallelAgent{
+		maxConcurrency:    cfg.MaxConcurrency,
+		subAgentTimeout:   cfg.SubAgentTimeout,
+		failurePolicy:     cfg.FailurePolicy,
+		requiredSuccesses: cfg.RequiredSuccesses,
+	}
+	cfg.AgentConfig.Run = parallelAgentImpl.run
 
 	parallelAgent, err := agent.New(cfg.AgentConfig)
 	if err != nil {
@@ -64,70 +123,126 @@ func New(cfg Config) (agent.Agent, error) {
 	return parallelAgent, nil
 }
 
-func run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
-	curAgent := ctx.Agent()
+type parallelAgent struct {
+	maxConcurrency    int
+	subAgentTimeout   time.Duration
+	failurePolicy     FailurePolicy
+	requiredSuccesses int
+}
 
-	var (
-		errGroup, errGroupCtx = errgroup.WithContext(ctx)
-		doneChan              = make(chan bool)
-		resultsChan           = make(chan result)
-	)
+func (a *parallelAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
 
-	for _, sa := range ctx.Agent().SubAgents() {
-		branch := fmt.Sprintf("%s.%s", curAgent.Name(), sa.Name())
-		if ctx.Branch() != "" {
-			branch = fmt.Sprintf("%s.%s", ctx.Branch(), branch)
-		}
-		subAgent := sa
-		errGroup.Go(func() error {
-			subCtx := icontext.NewInvocationContext(errGroupCtx, icontext.InvocationContextParams{
-				Artifacts:    ctx.Artifacts(),
-				Memory:       ctx.Memory(),
-				Session:      ctx.Session(),
-				Branch:       branch,
-				Agent:        subAgent,
-				UserContent:  ctx.UserContent(),
-				RunConfig:    ctx.RunConfig(),
-				InvocationID: ctx.InvocationID(),
-			})
-
-			if err := runSubAgent(subCtx, subAgent, resultsChan, doneChan); err != nil {
-				return fmt.Errorf("failed to run sub-agent %q: %w", subAgent.Name(), err)
-			}
+		var (
+			errGroup    errgroup.Group
+			doneChan    = make(chan bool)
+			resultsChan = make(chan result)
 
-			return nil
-		})
-	}
+			mu        sync.Mutex
+			successes int
+			failures  []error
+		)
+		defer close(doneChan)
 
-	go func() {
-		_ = errGroup.Wait() // this error is already sent to the user via iterator
-		close(resultsChan)
-	}()
+		subAgents := ctx.Agent().SubAgents()
+		if a.maxConcurrency > 0 {
+			errGroup.SetLimit(a.maxConcurrency)
+		}
 
-	return func(yield func(*session.Event, error) bool) {
-		defer close(doneChan)
+		go func() {
+			for _, subAgent := range subAgents {
+				// Go blocks while MaxConcurrency sub-agents are running.
+				if runCtx.Err() != nil {
+					break
+				}
+				errGroup.Go(func() error {
+					err := a.runSubAgent(runCtx, ctx, subAgent, resultsChan, doneChan)
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err == nil {
+						successes++
+						return nil
+					}
+					failures = append(failures, fmt.Errorf("failed to run sub-agent %q: %w", subAgent.Name(), err))
+					if a.failurePolicy == FailFast || (a.failurePolicy == RequireN && len(subAgents)-len(failures) < a.requiredSuccesses) {
+						cancel()
+					}
+					return nil
+				})
+			}
+			_ = errGroup.Wait() // the failures are collected
+			close(resultsChan)
+		}()
 
 		for res := range resultsChan {
 			if !yield(res.event, res.err) {
-				break
+				return
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case len(failures) == 0 || a.failurePolicy == FailFast:
+			// The errors of FailFast are already yielded.
+		case a.failurePolicy == CollectAll:
+			yield(nil, errors.Join(failures...))
+		case successes < a.requiredSuccesses:
+			yield(nil, fmt.Errorf("%d of %d sub-agents succeeded, %d required: %w", successes, len(subAgents), a.requiredSuccesses, errors.Join(failures...)))
+		default:
+			for _, err := range failures {
+				log.Printf("Parallel agent %s tolerated failure: %v", ctx.Agent().Name(), err)
 			}
 		}
 	}
 }
 
-func runSubAgent(ctx agent.InvocationContext, agent agent.Agent, results chan<- result, done <-chan bool) error {
-	for event, err := range agent.Run(ctx) {
+// runSubAgent runs the sub-agent, sending its events to results until done is
+// closed. The errors are only sent with the FailFast policy, the other
+// policies report them once all the sub-agents finished.
+func (a *parallelAgent) runSubAgent(runCtx context.Context, ctx agent.InvocationContext, subAgent agent.Agent, results chan<- result, done <-chan bool) error {
+	if a.subAgentTimeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, a.subAgentTimeout)
+		defer cancel()
+	}
+
+	branch := fmt.Sprintf("%s.%s", ctx.Agent().Name(), subAgent.Name())
+	if ctx.Branch() != "" {
+		branch = fmt.Sprintf("%s.%s", ctx.Branch(), branch)
+	}
+	subCtx := icontext.NewInvocationContext(runCtx, icontext.InvocationContextParams{
+		Artifacts:    ctx.Artifacts(),
+		Memory:       ctx.Memory(),
+		Session:      ctx.Session(),
+		Branch:       branch,
+		Agent:        subAgent,
+		UserContent:  ctx.UserContent(),
+		RunConfig:    ctx.RunConfig(),
+		InvocationID: ctx.InvocationID(),
+	})
+
+	streamErrors := a.failurePolicy == FailFast
+	for event, err := range subAgent.Run(subCtx) {
+		if err != nil && !streamErrors {
+			return err
+		}
 		select {
 		case <-done:
 			return nil
-		case <-ctx.Done():
-			select {
-			case <-done:
-			case results <- result{
-				err: ctx.Err(),
-			}:
+		case <-subCtx.Done():
+			if streamErrors {
+				select {
+				case <-done:
+				case results <- result{
+					err: subCtx.Err(),
+				}:
+				}
 			}
-			return ctx.Err()
+			return subCtx.Err()
 		case results <- result{
 			event: event,
 			err:   err,
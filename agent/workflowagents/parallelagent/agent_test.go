@@ -16,10 +16,12 @@ package parallelagent_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
 	rand "math/rand/v2"
 	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -224,3 +226,242 @@ func customRun(id int, agentErr error) func(agent.InvocationContext) iter.Seq2[*
 		}
 	}
 }
+
+func TestParallelAgent_FailurePolicy(t *testing.T) {
+	agentErr := errors.New("agent error")
+	tests := []struct {
+		name              string
+		policy            parallelagent.FailurePolicy
+		requiredSuccesses int
+		failing           int
+		wantAuthors       []string
+		wantErr           bool
+	}{
+		{
+			name:        "collect all",
+			policy:      parallelagent.CollectAll,
+			failing:     1,
+			wantAuthors: []string{"ok_1", "ok_2", "ok_3"},
+			wantErr:     true,
+		},
+		{
+			name:        "collect all without failures",
+			policy:      parallelagent.CollectAll,
+			wantAuthors: []string{"ok_1", "ok_2", "ok_3"},
+		},
+		{
+			name:              "require n met",
+			policy:            parallelagent.RequireN,
+			requiredSuccesses: 3,
+			failing:           2,
+			wantAuthors:       []string{"ok_1", "ok_2", "ok_3"},
+		},
+		{
+			name:              "require n not met",
+			policy:            parallelagent.RequireN,
+			requiredSuccesses: 4,
+			failing:           2,
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subAgents []agent.Agent
+			for i := 1; i <= 3; i++ {
+				subAgents = append(subAgents, must(agent.New(agent.Config{
+					Name: fmt.Sprintf("ok_%d", i),
+					Run:  customRun(i, nil),
+				})))
+			}
+			for i := 1; i <= tt.failing; i++ {
+				subAgents = append(subAgents, must(agent.New(agent.Config{
+					Name: fmt.Sprintf("failing_%d", i),
+					Run:  customRun(-i, agentErr),
+				})))
+			}
+			parallelAgent, err := parallelagent.New(parallelagent.Config{
+				AgentConfig:       agent.Config{Name: "test_agent", SubAgents: subAgents},
+				FailurePolicy:     tt.policy,
+				RequiredSuccesses: tt.requiredSuccesses,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotAuthors []string
+			var gotErrs []error
+			for event, err := range runAgent(t, parallelAgent) {
+				if err != nil {
+					gotErrs = append(gotErrs, err)
+					continue
+				}
+				gotAuthors = append(gotAuthors, event.Author)
+			}
+			slices.Sort(gotAuthors)
+			if !tt.wantErr && len(gotErrs) > 0 {
+				t.Fatalf("Run() errors = %v, want none", gotErrs)
+			}
+			if tt.wantErr {
+				// The failures are reported at once, in a single error.
+				if len(gotErrs) != 1 || !errors.Is(gotErrs[0], agentErr) {
+					t.Fatalf("Run() errors = %v, want a single error wrapping %v", gotErrs, agentErr)
+				}
+			}
+			if tt.policy == parallelagent.RequireN && tt.wantErr {
+				// The run stops once the required successes can't be met.
+				return
+			}
+			if diff := cmp.Diff(tt.wantAuthors, gotAuthors); diff != "" {
+				t.Errorf("Run() authors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParallelAgent_MaxConcurrency(t *testing.T) {
+	const maxConcurrency = 2
+	var running, maxRunning atomic.Int32
+
+	var subAgents []agent.Agent
+	for i := 1; i <= 6; i++ {
+		subAgents = append(subAgents, must(agent.New(agent.Config{
+			Name: fmt.Sprintf("sub%d", i),
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					yield(&session.Event{
+						LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello", genai.RoleModel)},
+					}, nil)
+				}
+			},
+		})))
+	}
+	parallelAgent, err := parallelagent.New(parallelagent.Config{
+		AgentConfig:    agent.Config{Name: "test_agent", SubAgents: subAgents},
+		MaxConcurrency: maxConcurrency,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for _, err := range runAgent(t, parallelAgent) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		count++
+	}
+	if count != len(subAgents) {
+		t.Errorf("Run() yielded %d events, want %d", count, len(subAgents))
+	}
+	if got := maxRunning.Load(); got > maxConcurrency {
+		t.Errorf("%d sub-agents ran at once, want at most %d", got, maxConcurrency)
+	}
+}
+
+func TestParallelAgent_SubAgentTimeout(t *testing.T) {
+	slow := must(agent.New(agent.Config{
+		Name: "slow",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				<-ctx.Done()
+				yield(nil, ctx.Err())
+			}
+		},
+	}))
+	fast := must(agent.New(agent.Config{Name: "fast", Run: customRun(1, nil)}))
+	parallelAgent, err := parallelagent.New(parallelagent.Config{
+		AgentConfig:     agent.Config{Name: "test_agent", SubAgents: []agent.Agent{slow, fast}},
+		SubAgentTimeout: 50 * time.Millisecond,
+		FailurePolicy:   parallelagent.CollectAll,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotAuthors []string
+	var gotErr error
+	for event, err := range runAgent(t, parallelAgent) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		gotAuthors = append(gotAuthors, event.Author)
+	}
+	if !errors.Is(gotErr, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want %v", gotErr, context.DeadlineExceeded)
+	}
+	if diff := cmp.Diff([]string{"fast"}, gotAuthors); diff != "" {
+		t.Errorf("Run() authors mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewParallelAgent_InvalidConfig(t *testing.T) {
+	subAgents := []agent.Agent{
+		must(agent.New(agent.Config{Name: "sub1"})),
+		must(agent.New(agent.Config{Name: "sub2"})),
+	}
+	tests := []struct {
+		name string
+		cfg  parallelagent.Config
+	}{
+		{
+			name: "negative max concurrency",
+			cfg:  parallelagent.Config{MaxConcurrency: -1},
+		},
+		{
+			name: "negative timeout",
+			cfg:  parallelagent.Config{SubAgentTimeout: -time.Second},
+		},
+		{
+			name: "require n without successes",
+			cfg:  parallelagent.Config{FailurePolicy: parallelagent.RequireN},
+		},
+		{
+			name: "require n above sub-agents",
+			cfg:  parallelagent.Config{FailurePolicy: parallelagent.RequireN, RequiredSuccesses: 3},
+		},
+		{
+			name: "unknown policy",
+			cfg:  parallelagent.Config{FailurePolicy: 42},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.AgentConfig = agent.Config{Name: "test_agent", SubAgents: subAgents}
+			if _, err := parallelagent.New(tt.cfg); err == nil {
+				t.Error("New() succeeded, want error")
+			}
+		})
+	}
+}
+
+func runAgent(t *testing.T, a agent.Agent) iter.Seq2[*session.Event, error] {
+	t.Helper()
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	agentRunner, err := runner.New(runner.Config{
+		AppName:        "test_app",
+		Agent:          a,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   "test_app",
+		UserID:    "user_id",
+		SessionID: "session_id",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return agentRunner.Run(ctx, "user_id", "session_id", genai.NewContentFromText("user input", genai.RoleUser), agent.RunConfig{})
+}
@@ -0,0 +1,385 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fsartifact provides a local filesystem [artifact.Service].
+//
+// Artifacts are stored under a root directory, laid out as
+//
+//	<root>/<app>/<user>/<session>/<file>/<version>/data
+//	<root>/<app>/<user>/<session>/<file>/<version>/metadata.json
+//
+// where the user scoped files ("user:" prefix) use "%user" as the session.
+// The path segments are escaped, so any name is safe to use, and no session
+// shares the directory of the user scoped files. The metadata
+// sidecar holds the MIME type of the artifact.
+//
+// The writes are atomic: a version becomes visible once its metadata is
+// renamed into place, after its data. The version numbers are reserved by
+// creating the version directories, so concurrent writers, including other
+// processes sharing the root directory, never get the same version.
+package fsartifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+const (
+	dataFileName     = "data"
+	metadataFileName = "metadata.json"
+
+	// userScopedDir is the session directory of the user scoped files. The
+	// escaped names never contain a "%" which is not followed by two hex
+	// digits, so it does not clash with any session.
+	userScopedDir = "%user"
+
+	dirPerm = 0o750
+)
+
+// fsService is a local filesystem implementation of the Service.
+type fsService struct {
+	root string
+}
+
+// NewService creates a filesystem artifact service storing the artifacts
+// under the root directory, which is created if needed.
+func NewService(root string) (artifact.Service, error) {
+	if root == "" {
+		return nil, fmt.Errorf("root directory is required")
+	}
+	if err := os.MkdirAll(root, dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create root directory: %w", err)
+	}
+	return &fsService{root: root}, nil
+}
+
+// metadata is the content of the metadata sidecar of a version.
+type metadata struct {
+	MIMEType string `json:"mimeType"`
+	// Text is set if the artifact was saved as a text part.
+	Text bool `json:"text,omitempty"`
+}
+
+// fileHasUserNamespace checks if a filename indicates a user scoped artifact.
+func fileHasUserNamespace(filename string) bool {
+	return strings.HasPrefix(filename, "user:")
+}
+
+// escape returns name as a single path segment, which is neither "." nor
+// "..", and contains no separator or character invalid on common
+// filesystems.
+func escape(name string) string {
+	s := url.PathEscape(name)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	if s == "." || s == ".." {
+		s = strings.ReplaceAll(s, ".", "%2E")
+	}
+	return s
+}
+
+func (s *fsService) sessionDir(appName, userID, sessionID string) string {
+	return filepath.Join(s.root, escape(appName), escape(userID), escape(sessionID))
+}
+
+func (s *fsService) userDir(appName, userID string) string {
+	return filepath.Join(s.root, escape(appName), escape(userID), userScopedDir)
+}
+
+func (s *fsService) fileDir(appName, userID, sessionID, fileName string) string {
+	if fileHasUserNamespace(fileName) {
+		return filepath.Join(s.userDir(appName, userID), escape(fileName))
+	}
+	return filepath.Join(s.sessionDir(appName, userID, sessionID), escape(fileName))
+}
+
+// Save implements [artifact.Service]
+func (s *fsService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	meta := metadata{MIMEType: "text/plain", Text: true}
+	data := []byte(req.Part.Text)
+	if req.Part.InlineData != nil {
+		meta = metadata{MIMEType: req.Part.InlineData.MIMEType}
+		data = req.Part.InlineData.Data
+	}
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact metadata: %w", err)
+	}
+
+	fileDir := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+	version, versionDir, err := reserveVersion(fileDir)
+	if err != nil {
+		return nil, err
+	}
+	// The metadata is written last, it makes the version visible.
+	if err := writeFileAtomic(versionDir, dataFileName, data); err != nil {
+		os.RemoveAll(versionDir)
+		return nil, fmt.Errorf("failed to write artifact: %w", err)
+	}
+	if err := writeFileAtomic(versionDir, metadataFileName, metaData); err != nil {
+		os.RemoveAll(versionDir)
+		return nil, fmt.Errorf("failed to write artifact metadata: %w", err)
+	}
+	return &artifact.SaveResponse{Version: version}, nil
+}
+
+// reserveVersion creates the directory of the next version of the file.
+// Creating a directory fails if it already exists, so each concurrent writer
+// gets its own version.
+func reserveVersion(fileDir string) (int64, string, error) {
+	for {
+		// The file directory may be removed by a concurrent delete.
+		if err := os.MkdirAll(fileDir, dirPerm); err != nil {
+			return 0, "", fmt.Errorf("failed to create artifact directory: %w", err)
+		}
+		versions, err := listVersions(fileDir, false)
+		if err != nil {
+			return 0, "", err
+		}
+		next := int64(1)
+		if len(versions) > 0 {
+			next = slices.Max(versions) + 1
+		}
+		versionDir := filepath.Join(fileDir, strconv.FormatInt(next, 10))
+		err = os.Mkdir(versionDir, dirPerm)
+		if err == nil {
+			return next, versionDir, nil
+		}
+		if !errors.Is(err, fs.ErrExist) && !errors.Is(err, fs.ErrNotExist) {
+			return 0, "", fmt.Errorf("failed to create artifact version directory: %w", err)
+		}
+	}
+}
+
+// writeFileAtomic writes the file through a temporary file renamed into
+// place, so readers never see a partial file.
+func writeFileAtomic(dir, name string, data []byte) (err error) {
+	f, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(dir, name))
+}
+
+// Delete implements [artifact.Service]
+func (s *fsService) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	fileDir := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+
+	if req.Version != 0 {
+		return deleteVersion(fileDir, req.Version)
+	}
+
+	versions, err := listVersions(fileDir, true)
+	if err != nil {
+		return fmt.Errorf("failed to fetch versions on delete artifact: %w", err)
+	}
+	for _, version := range versions {
+		if err := deleteVersion(fileDir, version); err != nil {
+			return err
+		}
+	}
+	// Fails if a concurrent writer added a version meanwhile.
+	_ = os.Remove(fileDir)
+	return nil
+}
+
+// deleteVersion removes the metadata of the version first, to hide it from
+// the readers, then its directory.
+func deleteVersion(fileDir string, version int64) error {
+	versionDir := filepath.Join(fileDir, strconv.FormatInt(version, 10))
+	if err := os.Remove(filepath.Join(versionDir, metadataFileName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete artifact version %d: %w", version, err)
+	}
+	if err := os.RemoveAll(versionDir); err != nil {
+		return fmt.Errorf("failed to delete artifact version %d: %w", version, err)
+	}
+	return nil
+}
+
+// Load implements [artifact.Service]
+func (s *fsService) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	fileDir := s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName)
+
+	version := req.Version
+	if version == 0 {
+		versions, err := listVersions(fileDir, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		version = slices.Max(versions)
+	}
+
+	versionDir := filepath.Join(fileDir, strconv.FormatInt(version, 10))
+	metaData, err := os.ReadFile(filepath.Join(versionDir, metadataFileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to read artifact metadata: %w", err)
+	}
+	var meta metadata
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal artifact metadata: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(versionDir, dataFileName))
+	if err != nil {
+		// Also fs.ErrNotExist if the version was deleted meanwhile.
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	if meta.Text {
+		return &artifact.LoadResponse{Part: genai.NewPartFromText(string(data))}, nil
+	}
+	return &artifact.LoadResponse{Part: genai.NewPartFromBytes(data, meta.MIMEType)}, nil
+}
+
+// List implements [artifact.Service]
+func (s *fsService) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+
+	sessionFiles, err := listFiles(s.sessionDir(req.AppName, req.UserID, req.SessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session artifacts: %w", err)
+	}
+	userFiles, err := listFiles(s.userDir(req.AppName, req.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user artifacts: %w", err)
+	}
+
+	filenames := append(sessionFiles, userFiles...)
+	slices.Sort(filenames)
+	return &artifact.ListResponse{FileNames: slices.Compact(filenames)}, nil
+}
+
+// listFiles returns the names of the files with at least one saved version
+// in the directory.
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var filenames []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			continue
+		}
+		versions, err := listVersions(filepath.Join(dir, entry.Name()), true)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) > 0 {
+			filenames = append(filenames, name)
+		}
+	}
+	return filenames, nil
+}
+
+// Versions implements [artifact.Service] and returns an error if no versions are found.
+func (s *fsService) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	versions, err := listVersions(s.fileDir(req.AppName, req.UserID, req.SessionID, req.FileName), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	return &artifact.VersionsResponse{Versions: versions}, nil
+}
+
+// listVersions returns the versions of the file in ascending order. Unless
+// saved is false, the versions still being written are left out.
+func listVersions(fileDir string, saved bool) ([]int64, error) {
+	entries, err := os.ReadDir(fileDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var versions []int64
+	for _, entry := range entries {
+		version, err := strconv.ParseInt(entry.Name(), 10, 64)
+		// Ignore the entries which are not versions.
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		if saved {
+			_, err := os.Stat(filepath.Join(fileDir, entry.Name(), metadataFileName))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions, nil
+}
+
+var _ artifact.Service = (*fsService)(nil)
@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fsartifact_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/artifact/fsartifact"
+	"google.golang.org/adk/internal/artifact/tests"
+)
+
+func TestFSArtifactService(t *testing.T) {
+	factory := func(t *testing.T) (artifact.Service, error) {
+		return fsartifact.NewService(t.TempDir())
+	}
+	tests.TestArtifactService(t, "FS", factory)
+}
+
+func TestFSArtifactService_Persistence(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	srv, err := fsartifact.NewService(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part := genai.NewPartFromBytes([]byte("data"), "image/png")
+	if _, err := srv.Save(ctx, &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "file", Part: part,
+	}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// A new service sharing the root directory sees the saved artifacts.
+	srv, err = fsartifact.NewService(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := srv.Load(ctx, &artifact.LoadRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "file",
+	})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if diff := cmp.Diff(part, got.Part); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFSArtifactService_EscapedNames(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	srv, err := fsartifact.NewService(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileNames := []string{"..", ".", "user:..", "a b%c", "user:profile.json"}
+	for _, fileName := range fileNames {
+		if _, err := srv.Save(ctx, &artifact.SaveRequest{
+			AppName: "..", UserID: "../..", SessionID: "session/..", FileName: fileName,
+			Part: genai.NewPartFromText(fileName),
+		}); err != nil {
+			t.Fatalf("Save(%q) failed: %v", fileName, err)
+		}
+	}
+
+	resp, err := srv.List(ctx, &artifact.ListRequest{AppName: "..", UserID: "../..", SessionID: "session/.."})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	want := slices.Sorted(slices.Values(fileNames))
+	if diff := cmp.Diff(want, resp.FileNames); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, fileName := range fileNames {
+		got, err := srv.Load(ctx, &artifact.LoadRequest{
+			AppName: "..", UserID: "../..", SessionID: "session/..", FileName: fileName,
+		})
+		if err != nil {
+			t.Fatalf("Load(%q) failed: %v", fileName, err)
+		}
+		if diff := cmp.Diff(genai.NewPartFromText(fileName), got.Part); diff != "" {
+			t.Errorf("Load(%q) mismatch (-want +got):\n%s", fileName, diff)
+		}
+	}
+
+	// Nothing is written outside of the root directory.
+	entries, err := os.ReadDir(filepath.Dir(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("found %d entries next to the root directory, want 1", len(entries))
+	}
+}
+
+func TestFSArtifactService_UserSession(t *testing.T) {
+	ctx := context.Background()
+
+	// The sessions named like the directory of the user scoped files do not
+	// share their files with the other sessions.
+	for _, sessionID := range []string{"user", "%user"} {
+		t.Run(sessionID, func(t *testing.T) {
+			srv, err := fsartifact.NewService(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, req := range []*artifact.SaveRequest{
+				{AppName: "app", UserID: "user", SessionID: sessionID, FileName: "file"},
+				{AppName: "app", UserID: "user", SessionID: "other", FileName: "user:profile"},
+			} {
+				req.Part = genai.NewPartFromText(req.FileName)
+				if _, err := srv.Save(ctx, req); err != nil {
+					t.Fatalf("Save(%q) failed: %v", req.FileName, err)
+				}
+			}
+
+			for _, tc := range []struct {
+				sessionID string
+				want      []string
+			}{
+				{sessionID: sessionID, want: []string{"file", "user:profile"}},
+				{sessionID: "other", want: []string{"user:profile"}},
+			} {
+				resp, err := srv.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "user", SessionID: tc.sessionID})
+				if err != nil {
+					t.Fatalf("List(%q) failed: %v", tc.sessionID, err)
+				}
+				if diff := cmp.Diff(tc.want, resp.FileNames); diff != "" {
+					t.Errorf("List(%q) mismatch (-want +got):\n%s", tc.sessionID, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestFSArtifactService_ConcurrentSave(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	const writers = 20
+	var wg sync.WaitGroup
+	versions := make([]int64, writers)
+	errs := make([]error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each writer has its own service, as separate processes would.
+			srv, err := fsartifact.NewService(root)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			resp, err := srv.Save(ctx, &artifact.SaveRequest{
+				AppName: "app", UserID: "user", SessionID: "session", FileName: "file",
+				Part: genai.NewPartFromText(fmt.Sprint(i)),
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			versions[i] = resp.Version
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Save() #%d failed: %v", i, err)
+		}
+	}
+
+	var want []int64
+	for i := range writers {
+		want = append(want, int64(i+1))
+	}
+	slices.Sort(versions)
+	if diff := cmp.Diff(want, versions); diff != "" {
+		t.Errorf("Save() versions mismatch (-want +got):\n%s", diff)
+	}
+
+	srv, err := fsartifact.NewService(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Versions(ctx, &artifact.VersionsRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "file",
+	})
+	if err != nil {
+		t.Fatalf("Versions() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, resp.Versions); diff != "" {
+		t.Errorf("Versions() mismatch (-want +got):\n%s", diff)
+	}
+}
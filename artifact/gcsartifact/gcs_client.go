@@ -39,6 +39,9 @@ type gcsObject interface {
 	newReader(ctx context.Context) (io.ReadCloser, error)
 	delete(ctx context.Context) error
 	attrs(ctx context.Context) (*storage.ObjectAttrs, error)
+	// ifDoesNotExist returns a handle to the object whose writes fail with a
+	// 412 Precondition Failed error if the object already exists.
+	ifDoesNotExist() gcsObject
 }
 
 // gcsObjectIterator
@@ -112,6 +115,11 @@ func (w *gcsObjectWrapper) attrs(ctx context.Context) (*storage.ObjectAttrs, err
 	return w.object.Attrs(ctx)
 }
 
+// IfDoesNotExist implements the gcsObject interface for gcsObjectWrapper.
+func (w *gcsObjectWrapper) ifDoesNotExist() gcsObject {
+	return &gcsObjectWrapper{object: w.object.If(storage.Conditions{DoesNotExist: true})}
+}
+
 // Create the wrapper for the real iterator.
 type gcsObjectIteratorWrapper struct {
 	iter *storage.ObjectIterator
@@ -19,12 +19,15 @@ import (
 	"context"
 	"io"
 	"io/fs"
+	"net/http"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 
 	"google.golang.org/adk/artifact"
@@ -94,9 +97,11 @@ func (f *fakeBucket) objects(ctx context.Context, q *storage.Query) gcsObjectIte
 		if q != nil && q.Prefix != "" && !strings.HasPrefix(name, q.Prefix) {
 			continue
 		}
-		if !obj.deleted {
+		obj.mu.Lock()
+		if obj.exists() {
 			matchingObjects = append(matchingObjects, obj)
 		}
+		obj.mu.Unlock()
 	}
 
 	// This is the key change. We return a custom type that has a `Next` method
@@ -116,20 +121,37 @@ type fakeObject struct {
 	contentType string
 }
 
+// exists reports whether the object was written and not deleted since.
+// f.mu must be held.
+func (f *fakeObject) exists() bool {
+	return !f.deleted && f.data != nil
+}
+
 // NewWriter returns a fake writer that stores data in memory.
 func (f *fakeObject) newWriter(ctx context.Context) gcsWriter {
-	f.mu.Lock()
-	defer f.mu.Unlock()
-	f.deleted = false // A write operation "undeletes" the object
-	f.data = nil      // Clear existing data
 	return &fakeWriter{obj: f, buffer: &bytes.Buffer{}}
 }
 
+// IfDoesNotExist returns a handle whose writers fail if the object exists.
+func (f *fakeObject) ifDoesNotExist() gcsObject {
+	return &fakeConditionalObject{fakeObject: f}
+}
+
+// fakeConditionalObject is a handle to a fakeObject with a DoesNotExist precondition.
+type fakeConditionalObject struct {
+	*fakeObject
+}
+
+// NewWriter returns a fake writer that fails on close if the object exists.
+func (f *fakeConditionalObject) newWriter(ctx context.Context) gcsWriter {
+	return &fakeWriter{obj: f.fakeObject, buffer: &bytes.Buffer{}, ifDoesNotExist: true}
+}
+
 // Attrs returns fake attributes for the object.
 func (f *fakeObject) attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	if f.deleted || f.data == nil {
+	if !f.exists() {
 		return nil, storage.ErrObjectNotExist
 	}
 	return &storage.ObjectAttrs{Name: f.name, Created: time.Now(), ContentType: f.contentType}, nil
@@ -147,7 +169,7 @@ func (f *fakeObject) delete(ctx context.Context) error {
 func (f *fakeObject) newReader(ctx context.Context) (io.ReadCloser, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	if f.deleted || f.data == nil {
+	if !f.exists() {
 		return nil, fs.ErrNotExist
 	}
 	return io.NopCloser(bytes.NewReader(f.data)), nil
@@ -155,18 +177,25 @@ func (f *fakeObject) newReader(ctx context.Context) (io.ReadCloser, error) {
 
 // fakeWriter is a helper type to simulate an *storage.Writer
 type fakeWriter struct {
-	obj         *fakeObject
-	buffer      *bytes.Buffer
-	contentType string
+	obj            *fakeObject
+	buffer         *bytes.Buffer
+	contentType    string
+	ifDoesNotExist bool
 }
 
 func (w *fakeWriter) Write(p []byte) (n int, err error) {
+	// Yield as a network upload would, so concurrent saves interleave.
+	runtime.Gosched()
 	return w.buffer.Write(p)
 }
 
 func (w *fakeWriter) Close() error {
 	w.obj.mu.Lock()
 	defer w.obj.mu.Unlock()
+	if w.ifDoesNotExist && w.obj.exists() {
+		return &googleapi.Error{Code: http.StatusPreconditionFailed, Message: "conditionNotMet"}
+	}
+	w.obj.deleted = false // A write operation "undeletes" the object
 	w.obj.data = w.buffer.Bytes()
 	w.obj.contentType = w.contentType
 	return nil
@@ -192,6 +221,8 @@ func (i *fakeObjectIterator) next() (*storage.ObjectAttrs, error) {
 	}
 	obj := i.objects[i.index]
 	i.index++
+	obj.mu.Lock()
+	defer obj.mu.Unlock()
 	return &storage.ObjectAttrs{Name: obj.name, ContentType: obj.contentType}, nil
 }
 
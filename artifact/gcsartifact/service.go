@@ -21,10 +21,12 @@ package gcsartifact
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"maps"
+	"net/http"
 	"slices"
 	"sort"
 	"strconv"
@@ -32,6 +34,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	"google.golang.org/genai"
@@ -39,6 +42,11 @@ import (
 	"google.golang.org/adk/artifact"
 )
 
+// maxSaveAttempts is the number of versions a save tries to create before
+// giving up. Each failed attempt means that a concurrent save of the same
+// file created the version.
+const maxSaveAttempts = 10
+
 // gcsService is a google cloud storage implementation of the Service.
 type gcsService struct {
 	bucketName    string
@@ -91,49 +99,72 @@ func buildUserPrefix(appName, userID string) string {
 }
 
 // Save implements [artifact.Service]
-func (s *gcsService) Save(ctx context.Context, req *artifact.SaveRequest) (_ *artifact.SaveResponse, err error) {
-	err = req.Validate()
+func (s *gcsService) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
 	if err != nil {
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
 	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
-	newArtifact := req.Part
-
-	nextVersion := int64(1)
-
-	// TODO race condition, could use mutex but it's a remote resource so the issue would still occurs
-	// with multiple consumers, and gcs does not have transactions spanning several operations
-	response, err := s.versions(ctx, &artifact.VersionsRequest{
-		AppName: req.AppName, UserID: req.UserID, SessionID: req.SessionID, FileName: req.FileName,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
-	}
-	if len(response.Versions) > 0 {
-		nextVersion = slices.Max(response.Versions) + 1
-	}
 
-	blobName := buildBlobName(appName, userID, sessionID, fileName, nextVersion)
-	writer := s.bucket.object(blobName).newWriter(ctx)
-	defer func() {
-		if closeErr := writer.Close(); closeErr != nil && err == nil {
-			err = fmt.Errorf("failed to close blob writer: %w", closeErr)
+	// The blob of a version is only created if it does not exist, so if a
+	// concurrent save got the version first, try the next one after the
+	// versions listed again.
+	var nextVersion int64
+	var conflictErr error
+	for range maxSaveAttempts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		response, err := s.versions(ctx, &artifact.VersionsRequest{
+			AppName: req.AppName, UserID: req.UserID, SessionID: req.SessionID, FileName: req.FileName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		nextVersion++
+		if len(response.Versions) > 0 {
+			nextVersion = max(nextVersion, slices.Max(response.Versions)+1)
 		}
-	}()
 
-	if newArtifact.InlineData != nil {
-		writer.SetContentType(newArtifact.InlineData.MIMEType)
-		if _, err := writer.Write(newArtifact.InlineData.Data); err != nil {
-			return nil, fmt.Errorf("failed to write blob to GCS: %w", err)
+		blobName := buildBlobName(appName, userID, sessionID, fileName, nextVersion)
+		err = s.writeBlob(ctx, blobName, req.Part)
+		if err == nil {
+			return &artifact.SaveResponse{Version: nextVersion}, nil
 		}
-	} else {
-		writer.SetContentType("text/plain")
-		if _, err := writer.Write([]byte(newArtifact.Text)); err != nil {
-			return nil, fmt.Errorf("failed to write text to GCS: %w", err)
+		if !isPreconditionFailed(err) {
+			return nil, err
 		}
+		conflictErr = err
+	}
+	return nil, fmt.Errorf("failed to save artifact after %d conflicting writes: %w", maxSaveAttempts, conflictErr)
+}
+
+// writeBlob creates the blob with the content of the part, and fails if the
+// blob already exists.
+func (s *gcsService) writeBlob(ctx context.Context, blobName string, part *genai.Part) error {
+	writer := s.bucket.object(blobName).ifDoesNotExist().newWriter(ctx)
+
+	contentType, data := "text/plain", []byte(part.Text)
+	if part.InlineData != nil {
+		contentType, data = part.InlineData.MIMEType, part.InlineData.Data
+	}
+	writer.SetContentType(contentType)
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write blob to GCS: %w", err)
 	}
+	// The precondition is checked when the upload completes.
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close blob writer: %w", err)
+	}
+	return nil
+}
 
-	return &artifact.SaveResponse{Version: nextVersion}, nil
+// isPreconditionFailed reports whether err is returned for a write whose
+// precondition does not hold.
+func isPreconditionFailed(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed
 }
 
 // Delete implements [artifact.Service]
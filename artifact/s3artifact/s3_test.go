@@ -0,0 +1,251 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3artifact
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/artifact/tests"
+)
+
+// newS3ArtifactServiceForTesting creates an s3Service for the specified bucket backed by a fake in-process S3 server.
+func newS3ArtifactServiceForTesting(t *testing.T, bucketName string) (artifact.Service, error) {
+	t.Helper()
+	return newS3ArtifactServiceWithFake(t, newFakeS3(bucketName))
+}
+
+// newS3ArtifactServiceWithFake creates an s3Service for the bucket of the fake S3 server.
+func newS3ArtifactServiceWithFake(t *testing.T, fake *fakeS3) (artifact.Service, error) {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	bucketName := fake.bucketName
+
+	return NewService(t.Context(), bucketName, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(server.URL)
+		o.UsePathStyle = true
+		o.Region = "us-east-1"
+		o.Credentials = credentials.NewStaticCredentialsProvider("key", "secret", "")
+	})
+}
+
+func TestS3ArtifactService(t *testing.T) {
+	factory := func(t *testing.T) (artifact.Service, error) {
+		return newS3ArtifactServiceForTesting(t, "new")
+	}
+	tests.TestArtifactService(t, "S3", factory)
+}
+
+func TestS3ArtifactService_UserSession(t *testing.T) {
+	// The sessions named like the prefix of the user scoped files, or like
+	// its escaped form, do not share their files with the other sessions.
+	for _, sessionID := range []string{"user", "%user"} {
+		t.Run(sessionID, func(t *testing.T) {
+			srv, err := newS3ArtifactServiceForTesting(t, "bucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, req := range []*artifact.SaveRequest{
+				{AppName: "app", UserID: "user", SessionID: sessionID, FileName: "file"},
+				{AppName: "app", UserID: "user", SessionID: "%" + sessionID, FileName: "other_file"},
+				{AppName: "app", UserID: "user", SessionID: "other", FileName: "user:profile"},
+			} {
+				req.Part = genai.NewPartFromText(req.FileName)
+				if _, err := srv.Save(t.Context(), req); err != nil {
+					t.Fatalf("Save(%q) failed: %v", req.FileName, err)
+				}
+			}
+
+			for _, tc := range []struct {
+				sessionID string
+				want      []string
+			}{
+				{sessionID: sessionID, want: []string{"file", "user:profile"}},
+				{sessionID: "%" + sessionID, want: []string{"other_file", "user:profile"}},
+				{sessionID: "other", want: []string{"user:profile"}},
+			} {
+				resp, err := srv.List(t.Context(), &artifact.ListRequest{AppName: "app", UserID: "user", SessionID: tc.sessionID})
+				if err != nil {
+					t.Fatalf("List(%q) failed: %v", tc.sessionID, err)
+				}
+				if diff := cmp.Diff(tc.want, resp.FileNames); diff != "" {
+					t.Errorf("List(%q) mismatch (-want +got):\n%s", tc.sessionID, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestS3ArtifactService_SaveConflicts(t *testing.T) {
+	fake := newFakeS3("bucket")
+	fake.conflicts = true
+	srv, err := newS3ArtifactServiceWithFake(t, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = srv.Save(t.Context(), &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "file", Part: genai.NewPartFromText("data"),
+	})
+	if err == nil {
+		t.Fatal("Save() succeeded, want error")
+	}
+	if fake.puts != maxSaveAttempts {
+		t.Errorf("Save() made %d writes, want %d", fake.puts, maxSaveAttempts)
+	}
+}
+
+// ---------------------------------- Fake S3 server -----------------------------------
+// fakeS3 is an in-memory S3 server with path style addressing for a single
+// bucket. It implements the subset of the API used by the service.
+type fakeS3 struct {
+	bucketName string
+
+	// conflicts makes every conditional write fail as if a concurrent
+	// write of the object was in progress.
+	conflicts bool
+
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+	// puts counts the write requests.
+	puts int
+}
+
+type fakeS3Object struct {
+	contentType string
+	data        []byte
+}
+
+// fakeS3PageSize is the maximum number of keys listed per page, small to
+// exercise the pagination.
+const fakeS3PageSize = 2
+
+func newFakeS3(bucketName string) *fakeS3 {
+	return &fakeS3{
+		bucketName: bucketName,
+		objects:    make(map[string]fakeS3Object),
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName != f.bucketName {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		f.listObjects(w, r)
+	case r.Method == http.MethodPut && key != "":
+		f.puts++
+		if f.conflicts && r.Header.Get("If-None-Match") == "*" {
+			writeS3Error(w, http.StatusConflict, "ConditionalRequestConflict")
+			return
+		}
+		if _, ok := f.objects[key]; ok && r.Header.Get("If-None-Match") == "*" {
+			writeS3Error(w, http.StatusPreconditionFailed, "PreconditionFailed")
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = fakeS3Object{contentType: r.Header.Get("Content-Type"), data: data}
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodGet && key != "":
+		object, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		w.Write(object.data)
+	case r.Method == http.MethodDelete && key != "":
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string
+	Prefix                string
+	KeyCount              int
+	MaxKeys               int
+	IsTruncated           bool
+	NextContinuationToken string `xml:",omitempty"`
+	Contents              []listBucketObject
+}
+
+type listBucketObject struct {
+	Key  string
+	Size int
+}
+
+func (f *fakeS3) listObjects(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	token := r.URL.Query().Get("continuation-token")
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) && key > token {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	result := listBucketResult{Name: f.bucketName, Prefix: prefix, MaxKeys: fakeS3PageSize}
+	if len(keys) > fakeS3PageSize {
+		keys = keys[:fakeS3PageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+	result.KeyCount = len(keys)
+	for _, key := range keys {
+		result.Contents = append(result.Contents, listBucketObject{Key: key, Size: len(f.objects[key].data)})
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
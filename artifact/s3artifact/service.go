@@ -0,0 +1,391 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package s3artifact provides an Amazon S3 [artifact.Service].
+//
+// This package allows storing and retrieving artifacts in an S3 bucket, or
+// in a bucket of any S3 compatible storage such as MinIO or Ceph.
+// Artifacts are organized by application name, user ID, session ID, and filename,
+// with support for versioning.
+//
+// The objects are keyed "<app>/<user>/<session>/<file>/<version>", where the
+// user scoped files ("user:" prefix) use "user" as the session. The sessions
+// named "user" or starting with "%" get a "%" prepended, so they never share
+// their files with other sessions.
+//
+// The versions are created with conditional writes (If-None-Match), so
+// concurrent saves of the same file never overwrite each other.
+package s3artifact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// maxSaveAttempts is the number of versions a save tries to create before
+// giving up. Each failed attempt means that a concurrent save of the same
+// file created the version.
+const maxSaveAttempts = 10
+
+// s3Service is an Amazon S3 implementation of the Service.
+type s3Service struct {
+	bucketName string
+	client     *s3.Client
+}
+
+// NewService creates an Amazon S3 service for the specified bucket.
+//
+// The client is configured from the default AWS configuration, as loaded by
+// [config.LoadDefaultConfig], and then the optFns. For S3 compatible
+// storages, set the endpoint in the options, usually with path style
+// addressing:
+//
+//	s3artifact.NewService(ctx, "artifacts", func(o *s3.Options) {
+//		o.BaseEndpoint = aws.String("http://localhost:9000")
+//		o.UsePathStyle = true
+//	})
+func NewService(ctx context.Context, bucketName string, optFns ...func(*s3.Options)) (artifact.Service, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	// The checksums added by default by the sdk are not supported by all
+	// S3 compatible storages.
+	opts := append([]func(*s3.Options){func(o *s3.Options) {
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	}}, optFns...)
+	return &s3Service{
+		bucketName: bucketName,
+		client:     s3.NewFromConfig(cfg, opts...),
+	}, nil
+}
+
+// fileHasUserNamespace checks if a filename indicates a user-namespaced object.
+func fileHasUserNamespace(filename string) bool {
+	return strings.HasPrefix(filename, "user:")
+}
+
+// buildObjectKey constructs the object key in S3.
+func buildObjectKey(appName, userID, sessionID, fileName string, version int64) string {
+	return buildObjectKeyPrefix(appName, userID, sessionID, fileName) + strconv.FormatInt(version, 10)
+}
+
+func buildObjectKeyPrefix(appName, userID, sessionID, fileName string) string {
+	if fileHasUserNamespace(fileName) {
+		return fmt.Sprintf("%s/%s/user/%s/", appName, userID, fileName)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/", appName, userID, sessionSegment(sessionID), fileName)
+}
+
+func buildSessionPrefix(appName, userID, sessionID string) string {
+	return fmt.Sprintf("%s/%s/%s/", appName, userID, sessionSegment(sessionID))
+}
+
+// sessionSegment returns the key segment of the session. It prepends "%" to
+// the session "user", which would otherwise share the prefix of the user
+// scoped files, and to the sessions starting with "%", which would otherwise
+// share the prefix of another session.
+func sessionSegment(sessionID string) string {
+	if sessionID == "user" || strings.HasPrefix(sessionID, "%") {
+		return "%" + sessionID
+	}
+	return sessionID
+}
+
+func buildUserPrefix(appName, userID string) string {
+	return fmt.Sprintf("%s/%s/user/", appName, userID)
+}
+
+// isNotFound reports whether err is returned for a missing object.
+func isNotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.As(err, &noSuchKey) || errors.As(err, &notFound)
+}
+
+// isWriteConflict reports whether err is returned for a conditional write
+// of an existing object.
+func isWriteConflict(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	// ConditionalRequestConflict is returned by S3 if a concurrent write of
+	// the same object is in progress.
+	switch apiErr.ErrorCode() {
+	case "PreconditionFailed", "ConditionalRequestConflict":
+		return true
+	}
+	return false
+}
+
+// Save implements [artifact.Service]
+func (s *s3Service) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+
+	contentType := "text/plain"
+	data := []byte(req.Part.Text)
+	if req.Part.InlineData != nil {
+		contentType = req.Part.InlineData.MIMEType
+		data = req.Part.InlineData.Data
+	}
+
+	var nextVersion int64
+	var conflictErr error
+	for range maxSaveAttempts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Listed again after a conflict, as the concurrent saves may have
+		// created more versions meanwhile.
+		versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		nextVersion++
+		if len(versions) > 0 {
+			nextVersion = max(nextVersion, slices.Max(versions)+1)
+		}
+
+		_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(s.bucketName),
+			Key:         aws.String(buildObjectKey(appName, userID, sessionID, fileName, nextVersion)),
+			Body:        bytes.NewReader(data),
+			ContentType: aws.String(contentType),
+			// Fail if the version was created by a concurrent save.
+			IfNoneMatch: aws.String("*"),
+		})
+		if err == nil {
+			return &artifact.SaveResponse{Version: nextVersion}, nil
+		}
+		if !isWriteConflict(err) {
+			return nil, fmt.Errorf("failed to write object to S3: %w", err)
+		}
+		conflictErr = err
+	}
+	return nil, fmt.Errorf("failed to save artifact after %d conflicting writes: %w", maxSaveAttempts, conflictErr)
+}
+
+// Delete implements [artifact.Service]
+func (s *s3Service) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+
+	// Delete specific version
+	if req.Version != 0 {
+		if err := s.deleteObject(ctx, buildObjectKey(appName, userID, sessionID, fileName, req.Version)); err != nil {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+		return nil
+	}
+
+	// Delete all versions
+	versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch versions on delete artifact: %w", err)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	for _, version := range versions {
+		g.Go(func() error {
+			key := buildObjectKey(appName, userID, sessionID, fileName, version)
+			if err := s.deleteObject(gctx, key); err != nil {
+				return fmt.Errorf("failed to delete artifact %s: %w", key, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func (s *s3Service) deleteObject(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// Load implements [artifact.Service]
+func (s *s3Service) Load(ctx context.Context, req *artifact.LoadRequest) (_ *artifact.LoadResponse, err error) {
+	err = req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+	version := req.Version
+
+	if version == 0 {
+		versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		version = slices.Max(versions)
+	}
+
+	key := buildObjectKey(appName, userID, sessionID, fileName, version)
+	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("artifact '%s' not found: %w", key, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("could not get object '%s': %w", key, err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close object reader: %w", closeErr)
+		}
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read data from object '%s': %w", key, err)
+	}
+
+	part := genai.NewPartFromBytes(data, aws.ToString(resp.ContentType))
+	return &artifact.LoadResponse{Part: part}, nil
+}
+
+// listKeys returns the keys of all the objects with the prefix.
+func (s *s3Service) listKeys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing objects: %w", err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
+
+// fetchFilenamesFromPrefix adds the filenames of the objects with the prefix to filenamesSet.
+func (s *s3Service) fetchFilenamesFromPrefix(ctx context.Context, prefix string, filenamesSet map[string]bool) error {
+	keys, err := s.listKeys(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		// Extract filename from key: appName/userId/sessionId/filename/version or appName/userId/user/filename/version
+		// Note: filenames with path separators are rejected during validation (see service.go Validate methods)
+		segments := strings.Split(strings.TrimPrefix(key, prefix), "/")
+		if len(segments) != 2 {
+			continue
+		}
+		filenamesSet[segments[0]] = true
+	}
+	return nil
+}
+
+// List implements [artifact.Service]
+func (s *s3Service) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	filenamesSet := map[string]bool{}
+
+	// Fetch filenames for the session.
+	err = s.fetchFilenamesFromPrefix(ctx, buildSessionPrefix(appName, userID, sessionID), filenamesSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch session filenames: %w", err)
+	}
+
+	// Fetch filenames for the user.
+	err = s.fetchFilenamesFromPrefix(ctx, buildUserPrefix(appName, userID), filenamesSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user filenames: %w", err)
+	}
+
+	filenames := slices.Collect(maps.Keys(filenamesSet))
+	sort.Strings(filenames)
+	return &artifact.ListResponse{FileNames: filenames}, nil
+}
+
+// versions returns the versions of the file, and no error if there are none.
+func (s *s3Service) versions(ctx context.Context, appName, userID, sessionID, fileName string) ([]int64, error) {
+	prefix := buildObjectKeyPrefix(appName, userID, sessionID, fileName)
+	keys, err := s.listKeys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, 0, len(keys))
+	for _, key := range keys {
+		version, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 64)
+		// if the file version is not convertible to number, just ignore it
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// Versions implements [artifact.Service] and returns an error if no versions are found.
+func (s *s3Service) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	versions, err := s.versions(ctx, req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	return &artifact.VersionsResponse{Versions: versions}, nil
+}
+
+var _ artifact.Service = (*s3Service)(nil)
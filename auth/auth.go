@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides the types used by tools to authenticate against
+// external services, and the services to exchange and store credentials.
+//
+// A tool describes the credential it needs with an [AuthConfig]. When the
+// credential requires the end user's consent (e.g. OAuth2 authorization code
+// flow), the tool calls tool.Context.RequestCredential and the ADK emits a
+// FunctionCall named [FunctionCallName] to the client. Once the client posts
+// back the response, the tool call is resumed and the tool can read the
+// credential with tool.Context.AuthResponse.
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// FunctionCallName defines the specific name for the FunctionCall event
+// emitted by ADK when a tool requests end user credentials.
+//
+// The 'args' of this FunctionCall include:
+//   - "functionCallId": The ID of the original FunctionCall of the tool which requested the credential.
+//   - "authConfig": The AuthConfig describing the requested credential. For
+//     OAuth2, the ExchangedAuthCredential contains the AuthURI the user must visit.
+//
+// Client applications or frontends interacting with the ADK-powered agent must:
+// 1. Listen for events containing a FunctionCall with this name.
+// 2. Let the user authorize the access, e.g. by redirecting them to the AuthURI.
+// 3. Send a FunctionResponse message back to the ADK. This FunctionResponse MUST:
+//   - Have the same 'id' as the received "adk_request_credential" FunctionCall.
+//   - Have the name set to "adk_request_credential".
+//   - Include the AuthConfig as the response payload, with the ExchangedAuthCredential
+//     filled in. For OAuth2 it is enough to set the AuthResponseURI (the redirect URI
+//     with the authorization code), the ADK exchanges the code for the tokens.
+const FunctionCallName = "adk_request_credential"
+
+// SchemeType is the type of an [AuthScheme].
+type SchemeType string
+
+const (
+	SchemeTypeAPIKey SchemeType = "apiKey"
+	SchemeTypeHTTP   SchemeType = "http"
+	SchemeTypeOAuth2 SchemeType = "oauth2"
+)
+
+// AuthScheme describes how a service expects to be authenticated. It follows
+// the OpenAPI security scheme object.
+type AuthScheme struct {
+	Type SchemeType `json:"type"`
+	// Name of the header, query or cookie parameter carrying the API key.
+	Name string `json:"name,omitempty"`
+	// In is the location of the API key: "header", "query" or "cookie".
+	In string `json:"in,omitempty"`
+	// Scheme is the HTTP authorization scheme, e.g. "bearer" or "basic".
+	Scheme string `json:"scheme,omitempty"`
+	// Flows configures the supported OAuth2 flows.
+	Flows *OAuthFlows `json:"flows,omitempty"`
+}
+
+// OAuthFlows configures the supported OAuth2 flows.
+type OAuthFlows struct {
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+}
+
+// OAuthFlow configures a single OAuth2 flow.
+type OAuthFlow struct {
+	AuthorizationURL string `json:"authorizationUrl,omitempty"`
+	TokenURL         string `json:"tokenUrl,omitempty"`
+	// Scopes maps the scope names to their description.
+	Scopes map[string]string `json:"scopes,omitempty"`
+}
+
+// CredentialType is the type of an [AuthCredential].
+type CredentialType string
+
+const (
+	CredentialTypeAPIKey         CredentialType = "apiKey"
+	CredentialTypeHTTP           CredentialType = "http"
+	CredentialTypeOAuth2         CredentialType = "oauth2"
+	CredentialTypeServiceAccount CredentialType = "serviceAccount"
+)
+
+// AuthCredential holds the secrets used to authenticate. Only the field
+// matching AuthType is set.
+type AuthCredential struct {
+	AuthType CredentialType `json:"authType"`
+	// ResourceRef optionally references a credential managed outside of the
+	// ADK, e.g. in a secret manager.
+	ResourceRef    string          `json:"resourceRef,omitempty"`
+	APIKey         string          `json:"apiKey,omitempty"`
+	HTTP           *HTTPAuth       `json:"http,omitempty"`
+	OAuth2         *OAuth2Auth     `json:"oauth2,omitempty"`
+	ServiceAccount *ServiceAccount `json:"serviceAccount,omitempty"`
+}
+
+// HTTPAuth is a credential for the HTTP authorization schemes.
+type HTTPAuth struct {
+	// Scheme is the HTTP authorization scheme, e.g. "bearer" or "basic".
+	Scheme      string          `json:"scheme"`
+	Credentials HTTPCredentials `json:"credentials"`
+	// ExpiresAt is the expiry of the token in seconds since the Unix epoch,
+	// e.g. for the tokens exchanged from service accounts. Zero means the
+	// token does not expire.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// HTTPCredentials are the secrets of an [HTTPAuth] credential.
+type HTTPCredentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// OAuth2Auth is an OAuth2 credential. The client ID and secret are set by
+// the tool, the other fields are filled during the authorization flow.
+type OAuth2Auth struct {
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// AuthURI is the URI the user must visit to authorize the access.
+	AuthURI string `json:"authUri,omitempty"`
+	// State is the opaque value used to match the authorization response to
+	// the request.
+	State       string `json:"state,omitempty"`
+	RedirectURI string `json:"redirectUri,omitempty"`
+	// AuthResponseURI is the redirect URI, including the query parameters,
+	// the user was sent to after authorizing the access.
+	AuthResponseURI string `json:"authResponseUri,omitempty"`
+	AuthCode        string `json:"authCode,omitempty"`
+	AccessToken     string `json:"accessToken,omitempty"`
+	RefreshToken    string `json:"refreshToken,omitempty"`
+	// ExpiresAt is the expiry of the access token in seconds since the Unix
+	// epoch. Zero means the token does not expire.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// ServiceAccount is a Google Cloud service account credential.
+type ServiceAccount struct {
+	// CredentialsJSON is the content of the service account key file.
+	CredentialsJSON json.RawMessage `json:"serviceAccountCredential,omitempty"`
+	Scopes          []string        `json:"scopes,omitempty"`
+	// UseDefaultCredential uses the Application Default Credentials instead
+	// of CredentialsJSON.
+	UseDefaultCredential bool `json:"useDefaultCredential,omitempty"`
+}
+
+// AuthConfig describes the credential a tool needs.
+type AuthConfig struct {
+	AuthScheme *AuthScheme `json:"authScheme"`
+	// RawAuthCredential is the initial credential provided by the tool, e.g.
+	// the OAuth2 client ID and secret.
+	RawAuthCredential *AuthCredential `json:"rawAuthCredential,omitempty"`
+	// ExchangedAuthCredential is the credential ready to be used, e.g. an
+	// OAuth2 access token. It is filled by the ADK or by the client.
+	ExchangedAuthCredential *AuthCredential `json:"exchangedAuthCredential,omitempty"`
+	// CredentialKey identifies the credential in the CredentialService. If
+	// empty, a key derived from the AuthScheme and RawAuthCredential is used.
+	CredentialKey string `json:"credentialKey,omitempty"`
+}
+
+// Key returns the key identifying the credential described by the config.
+func (c *AuthConfig) Key() string {
+	if c.CredentialKey != "" {
+		return c.CredentialKey
+	}
+	raw := c.RawAuthCredential.clone()
+	if raw != nil && raw.OAuth2 != nil {
+		// Drop the fields filled during the authorization flow, they don't
+		// identify the credential.
+		raw.OAuth2 = &OAuth2Auth{
+			ClientID:     raw.OAuth2.ClientID,
+			ClientSecret: raw.OAuth2.ClientSecret,
+			RedirectURI:  raw.OAuth2.RedirectURI,
+		}
+	}
+	data, _ := json.Marshal(struct {
+		Scheme *AuthScheme
+		Raw    *AuthCredential
+	}{c.AuthScheme, raw})
+	sum := sha256.Sum256(data)
+	return "adk_" + hex.EncodeToString(sum[:16])
+}
+
+// RequiresUserAuthorization reports whether the credential can only be
+// obtained with the end user's consent, i.e. the tool must call
+// tool.Context.RequestCredential unless a credential was stored before.
+func (c *AuthConfig) RequiresUserAuthorization() bool {
+	if c.AuthScheme == nil || c.AuthScheme.Type != SchemeTypeOAuth2 || c.AuthScheme.Flows == nil {
+		return false
+	}
+	if c.RawAuthCredential == nil || c.RawAuthCredential.AuthType != CredentialTypeOAuth2 {
+		return false
+	}
+	return c.AuthScheme.Flows.AuthorizationCode != nil
+}
+
+func (c *AuthConfig) clone() *AuthConfig {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	clone.RawAuthCredential = c.RawAuthCredential.clone()
+	clone.ExchangedAuthCredential = c.ExchangedAuthCredential.clone()
+	return &clone
+}
+
+func (c *AuthCredential) clone() *AuthCredential {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	if c.HTTP != nil {
+		http := *c.HTTP
+		clone.HTTP = &http
+	}
+	if c.OAuth2 != nil {
+		oauth2 := *c.OAuth2
+		clone.OAuth2 = &oauth2
+	}
+	if c.ServiceAccount != nil {
+		sa := *c.ServiceAccount
+		clone.ServiceAccount = &sa
+	}
+	return &clone
+}
@@ -0,0 +1,319 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/auth"
+)
+
+func newOAuth2Config(flows auth.OAuthFlows) *auth.AuthConfig {
+	return &auth.AuthConfig{
+		AuthScheme: &auth.AuthScheme{Type: auth.SchemeTypeOAuth2, Flows: &flows},
+		RawAuthCredential: &auth.AuthCredential{
+			AuthType: auth.CredentialTypeOAuth2,
+			OAuth2: &auth.OAuth2Auth{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				RedirectURI:  "https://example.com/callback",
+			},
+		},
+	}
+}
+
+func TestAuthConfig_Key(t *testing.T) {
+	cfg := newOAuth2Config(auth.OAuthFlows{
+		AuthorizationCode: &auth.OAuthFlow{TokenURL: "https://example.com/token"},
+	})
+	key := cfg.Key()
+	if !strings.HasPrefix(key, "adk_") {
+		t.Errorf("Key() = %q, want prefix %q", key, "adk_")
+	}
+
+	withTokens := *cfg
+	withTokens.RawAuthCredential = &auth.AuthCredential{
+		AuthType: auth.CredentialTypeOAuth2,
+		OAuth2: &auth.OAuth2Auth{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			RedirectURI:  "https://example.com/callback",
+			State:        "state",
+			AccessToken:  "token",
+		},
+	}
+	if got := withTokens.Key(); got != key {
+		t.Errorf("Key() with tokens = %q, want %q", got, key)
+	}
+
+	otherClient := *cfg
+	otherClient.RawAuthCredential = &auth.AuthCredential{
+		AuthType: auth.CredentialTypeOAuth2,
+		OAuth2:   &auth.OAuth2Auth{ClientID: "other-client"},
+	}
+	if got := otherClient.Key(); got == key {
+		t.Errorf("Key() of another client = %q, want a different key", got)
+	}
+
+	explicit := *cfg
+	explicit.CredentialKey = "my_key"
+	if got := explicit.Key(); got != "my_key" {
+		t.Errorf("Key() = %q, want %q", got, "my_key")
+	}
+}
+
+func TestGenerateAuthRequest(t *testing.T) {
+	cfg := newOAuth2Config(auth.OAuthFlows{
+		AuthorizationCode: &auth.OAuthFlow{
+			AuthorizationURL: "https://example.com/authorize",
+			TokenURL:         "https://example.com/token",
+			Scopes:           map[string]string{"read": "read access"},
+		},
+	})
+
+	req, err := auth.GenerateAuthRequest(cfg)
+	if err != nil {
+		t.Fatalf("GenerateAuthRequest() failed: %v", err)
+	}
+	if req.CredentialKey != cfg.Key() {
+		t.Errorf("GenerateAuthRequest() CredentialKey = %q, want %q", req.CredentialKey, cfg.Key())
+	}
+	if cfg.ExchangedAuthCredential != nil {
+		t.Errorf("GenerateAuthRequest() modified the config")
+	}
+	oauth2 := req.ExchangedAuthCredential.OAuth2
+	authURI, err := url.Parse(oauth2.AuthURI)
+	if err != nil {
+		t.Fatalf("failed to parse AuthURI %q: %v", oauth2.AuthURI, err)
+	}
+	query := authURI.Query()
+	if diff := cmp.Diff(map[string]string{
+		"client_id":    "client-id",
+		"redirect_uri": "https://example.com/callback",
+		"scope":        "read",
+		"state":        oauth2.State,
+	}, map[string]string{
+		"client_id":    query.Get("client_id"),
+		"redirect_uri": query.Get("redirect_uri"),
+		"scope":        query.Get("scope"),
+		"state":        query.Get("state"),
+	}); diff != "" || oauth2.State == "" {
+		t.Errorf("GenerateAuthRequest() AuthURI query mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var token string
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			token = "code:" + r.Form.Get("code")
+		case "refresh_token":
+			token = "refresh:" + r.Form.Get("refresh_token")
+		case "client_credentials":
+			token = "client"
+		default:
+			http.Error(w, "unsupported grant type", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":%q,"token_type":"bearer","refresh_token":"new-refresh-token","expires_in":3600}`, token)
+	}))
+	defer tokenServer.Close()
+	tokenURL := tokenServer.URL + "/token"
+	authCodeFlows := auth.OAuthFlows{AuthorizationCode: &auth.OAuthFlow{TokenURL: tokenURL}}
+
+	withExchanged := func(cfg *auth.AuthConfig, oauth2 auth.OAuth2Auth) *auth.AuthConfig {
+		cfg.ExchangedAuthCredential = &auth.AuthCredential{AuthType: auth.CredentialTypeOAuth2, OAuth2: &oauth2}
+		return cfg
+	}
+	apiKey := &auth.AuthCredential{AuthType: auth.CredentialTypeAPIKey, APIKey: "key"}
+	expiresAt := time.Now().Add(time.Hour).Unix()
+
+	for _, tc := range []struct {
+		name            string
+		cfg             *auth.AuthConfig
+		wantAccessToken string
+		wantAPIKey      string
+		wantErr         bool
+	}{
+		{
+			name:       "api key",
+			cfg:        &auth.AuthConfig{RawAuthCredential: apiKey},
+			wantAPIKey: "key",
+		},
+		{
+			name: "auth response uri",
+			cfg: withExchanged(newOAuth2Config(authCodeFlows), auth.OAuth2Auth{
+				ClientID:        "client-id",
+				State:           "state",
+				AuthResponseURI: "https://example.com/callback?code=abc&state=state",
+			}),
+			wantAccessToken: "code:abc",
+		},
+		{
+			name: "auth response state mismatch",
+			cfg: withExchanged(newOAuth2Config(authCodeFlows), auth.OAuth2Auth{
+				ClientID:        "client-id",
+				State:           "state",
+				AuthResponseURI: "https://example.com/callback?code=abc&state=other",
+			}),
+			wantErr: true,
+		},
+		{
+			name: "valid access token",
+			cfg: withExchanged(newOAuth2Config(authCodeFlows), auth.OAuth2Auth{
+				AccessToken: "token",
+				ExpiresAt:   expiresAt,
+			}),
+			wantAccessToken: "token",
+		},
+		{
+			name: "expired access token",
+			cfg: withExchanged(newOAuth2Config(authCodeFlows), auth.OAuth2Auth{
+				AccessToken:  "token",
+				RefreshToken: "refresh-token",
+				ExpiresAt:    time.Now().Add(-time.Hour).Unix(),
+			}),
+			wantAccessToken: "refresh:refresh-token",
+		},
+		{
+			name:            "client credentials",
+			cfg:             newOAuth2Config(auth.OAuthFlows{ClientCredentials: &auth.OAuthFlow{TokenURL: tokenURL}}),
+			wantAccessToken: "client",
+		},
+		{
+			name:    "requires user authorization",
+			cfg:     newOAuth2Config(authCodeFlows),
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := auth.Exchange(t.Context(), tc.cfg)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Exchange() = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() failed: %v", err)
+			}
+			if got.APIKey != tc.wantAPIKey {
+				t.Errorf("Exchange() APIKey = %q, want %q", got.APIKey, tc.wantAPIKey)
+			}
+			if tc.wantAccessToken != "" && (got.OAuth2 == nil || got.OAuth2.AccessToken != tc.wantAccessToken) {
+				t.Errorf("Exchange() OAuth2 = %+v, want access token %q", got.OAuth2, tc.wantAccessToken)
+			}
+		})
+	}
+}
+
+func TestApplyCredential(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		scheme     *auth.AuthScheme
+		cred       *auth.AuthCredential
+		wantHeader http.Header
+		wantQuery  string
+	}{
+		{
+			name:       "api key header",
+			scheme:     &auth.AuthScheme{Type: auth.SchemeTypeAPIKey, Name: "X-Api-Key", In: "header"},
+			cred:       &auth.AuthCredential{AuthType: auth.CredentialTypeAPIKey, APIKey: "key"},
+			wantHeader: http.Header{"X-Api-Key": {"key"}},
+		},
+		{
+			name:       "api key query",
+			scheme:     &auth.AuthScheme{Type: auth.SchemeTypeAPIKey, Name: "key", In: "query"},
+			cred:       &auth.AuthCredential{AuthType: auth.CredentialTypeAPIKey, APIKey: "secret"},
+			wantHeader: http.Header{},
+			wantQuery:  "key=secret",
+		},
+		{
+			name: "http bearer",
+			cred: &auth.AuthCredential{
+				AuthType: auth.CredentialTypeHTTP,
+				HTTP:     &auth.HTTPAuth{Scheme: "bearer", Credentials: auth.HTTPCredentials{Token: "token"}},
+			},
+			wantHeader: http.Header{"Authorization": {"Bearer token"}},
+		},
+		{
+			name: "oauth2",
+			cred: &auth.AuthCredential{
+				AuthType: auth.CredentialTypeOAuth2,
+				OAuth2:   &auth.OAuth2Auth{AccessToken: "token"},
+			},
+			wantHeader: http.Header{"Authorization": {"Bearer token"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://example.com/api", nil)
+			if err := auth.ApplyCredential(req, tc.scheme, tc.cred); err != nil {
+				t.Fatalf("ApplyCredential() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantHeader, req.Header); diff != "" {
+				t.Errorf("ApplyCredential() header mismatch (-want +got):\n%s", diff)
+			}
+			if req.URL.RawQuery != tc.wantQuery {
+				t.Errorf("ApplyCredential() query = %q, want %q", req.URL.RawQuery, tc.wantQuery)
+			}
+		})
+	}
+}
+
+func TestInMemoryCredentialService(t *testing.T) {
+	ctx := t.Context()
+	svc := auth.InMemoryCredentialService()
+	cfg := &auth.AuthConfig{
+		AuthScheme:              &auth.AuthScheme{Type: auth.SchemeTypeAPIKey, Name: "key", In: "query"},
+		ExchangedAuthCredential: &auth.AuthCredential{AuthType: auth.CredentialTypeAPIKey, APIKey: "secret"},
+	}
+
+	if err := svc.Save(ctx, &auth.SaveRequest{AppName: "app", UserID: "user", AuthConfig: cfg}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		userID string
+		want   *auth.AuthCredential
+	}{
+		{name: "same user", userID: "user", want: cfg.ExchangedAuthCredential},
+		{name: "other user", userID: "other"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := svc.Load(ctx, &auth.LoadRequest{AppName: "app", UserID: tc.userID, AuthConfig: cfg})
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Credential); diff != "" {
+				t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// CredentialService stores the credentials obtained by tools, so the end
+// user does not need to authorize the access again in later invocations.
+//
+// Credentials are scoped to a user of an application.
+type CredentialService interface {
+	// Load returns the stored credential for the config. The returned
+	// credential is nil if none was stored.
+	Load(ctx context.Context, req *LoadRequest) (*LoadResponse, error)
+	// Save stores the ExchangedAuthCredential of the config.
+	Save(ctx context.Context, req *SaveRequest) error
+}
+
+// LoadRequest represents a request to load a credential.
+type LoadRequest struct {
+	AppName    string
+	UserID     string
+	AuthConfig *AuthConfig
+}
+
+// LoadResponse represents the response of loading a credential.
+type LoadResponse struct {
+	Credential *AuthCredential
+}
+
+// SaveRequest represents a request to save a credential.
+type SaveRequest struct {
+	AppName    string
+	UserID     string
+	AuthConfig *AuthConfig
+}
+
+// InMemoryCredentialService returns a new in-memory implementation of the
+// credential service. Thread-safe.
+//
+// It is meant for development and testing, the credentials are lost when
+// the process exits.
+func InMemoryCredentialService() CredentialService {
+	return &inMemoryCredentialService{
+		store: make(map[credentialKey]*AuthCredential),
+	}
+}
+
+type credentialKey struct {
+	appName, userID, key string
+}
+
+type inMemoryCredentialService struct {
+	mu    sync.RWMutex
+	store map[credentialKey]*AuthCredential
+}
+
+func (s *inMemoryCredentialService) Load(ctx context.Context, req *LoadRequest) (*LoadResponse, error) {
+	if req == nil || req.AuthConfig == nil {
+		return nil, fmt.Errorf("auth config is required")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cred := s.store[credentialKey{req.AppName, req.UserID, req.AuthConfig.Key()}]
+	return &LoadResponse{Credential: cred.clone()}, nil
+}
+
+func (s *inMemoryCredentialService) Save(ctx context.Context, req *SaveRequest) error {
+	if req == nil || req.AuthConfig == nil {
+		return fmt.Errorf("auth config is required")
+	}
+	if req.AuthConfig.ExchangedAuthCredential == nil {
+		return fmt.Errorf("auth config has no exchanged credential to save")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store[credentialKey{req.AppName, req.UserID, req.AuthConfig.Key()}] = req.AuthConfig.ExchangedAuthCredential.clone()
+	return nil
+}
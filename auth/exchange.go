@@ -0,0 +1,238 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/google"
+)
+
+// ErrUserAuthorizationRequired is returned by [Exchange] when the credential
+// can only be obtained with the end user's consent.
+var ErrUserAuthorizationRequired = errors.New("credential requires user authorization")
+
+// GenerateAuthRequest returns a copy of the config to send to the client
+// when requesting a credential. For the OAuth2 authorization code flow, the
+// ExchangedAuthCredential of the returned config holds the AuthURI the user
+// must visit and the State of the request.
+//
+// The CredentialKey of the returned config is always set, so the response
+// posted back by the client can be matched to the config.
+func GenerateAuthRequest(cfg *AuthConfig) (*AuthConfig, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("auth config is nil")
+	}
+	req := cfg.clone()
+	req.CredentialKey = cfg.Key()
+	if !cfg.RequiresUserAuthorization() {
+		return req, nil
+	}
+	if req.ExchangedAuthCredential != nil && req.ExchangedAuthCredential.OAuth2 != nil && req.ExchangedAuthCredential.OAuth2.AuthURI != "" {
+		return req, nil
+	}
+	raw := req.RawAuthCredential
+	if raw.OAuth2 == nil {
+		return nil, fmt.Errorf("auth config of type %q has no OAuth2 credential", req.AuthScheme.Type)
+	}
+	exchanged := raw.clone()
+	if raw.OAuth2.AuthURI == "" {
+		conf := oauth2Config(req.AuthScheme.Flows.AuthorizationCode, raw.OAuth2)
+		exchanged.OAuth2.State = rand.Text()
+		exchanged.OAuth2.AuthURI = conf.AuthCodeURL(exchanged.OAuth2.State, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+	}
+	req.ExchangedAuthCredential = exchanged
+	return req, nil
+}
+
+// Exchange returns a credential ready to be used to call the service.
+//
+// The ExchangedAuthCredential of the config is used if set, the
+// RawAuthCredential otherwise:
+//   - API key and HTTP credentials are returned as is.
+//   - Service accounts are exchanged for an HTTP bearer token.
+//   - OAuth2 credentials are exchanged for an access token, using the
+//     authorization code, the refresh token or the client credentials.
+//     A valid access token is returned as is.
+//
+// It returns ErrUserAuthorizationRequired if an OAuth2 credential needs the
+// end user's consent first.
+func Exchange(ctx context.Context, cfg *AuthConfig) (*AuthCredential, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("auth config is nil")
+	}
+	cred := cfg.ExchangedAuthCredential
+	if cred == nil {
+		cred = cfg.RawAuthCredential
+	}
+	if cred == nil {
+		return nil, fmt.Errorf("auth config has no credential")
+	}
+
+	switch cred.AuthType {
+	case CredentialTypeAPIKey, CredentialTypeHTTP:
+		return cred.clone(), nil
+	case CredentialTypeServiceAccount:
+		return exchangeServiceAccount(ctx, cred.ServiceAccount)
+	case CredentialTypeOAuth2:
+		return exchangeOAuth2(ctx, cfg, cred)
+	default:
+		return nil, fmt.Errorf("unsupported credential type %q", cred.AuthType)
+	}
+}
+
+func exchangeServiceAccount(ctx context.Context, sa *ServiceAccount) (*AuthCredential, error) {
+	if sa == nil {
+		return nil, fmt.Errorf("service account credential is nil")
+	}
+	var (
+		creds *google.Credentials
+		err   error
+	)
+	if sa.UseDefaultCredential {
+		creds, err = google.FindDefaultCredentials(ctx, sa.Scopes...)
+	} else {
+		creds, err = google.CredentialsFromJSON(ctx, sa.CredentialsJSON, sa.Scopes...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load service account credentials: %w", err)
+	}
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account token: %w", err)
+	}
+	cred := &AuthCredential{
+		AuthType: CredentialTypeHTTP,
+		HTTP: &HTTPAuth{
+			Scheme:      "bearer",
+			Credentials: HTTPCredentials{Token: token.AccessToken},
+		},
+	}
+	if !token.Expiry.IsZero() {
+		cred.HTTP.ExpiresAt = token.Expiry.Unix()
+	}
+	return cred, nil
+}
+
+func exchangeOAuth2(ctx context.Context, cfg *AuthConfig, cred *AuthCredential) (*AuthCredential, error) {
+	if cred.OAuth2 == nil {
+		return nil, fmt.Errorf("OAuth2 credential is nil")
+	}
+	if cfg.AuthScheme == nil || cfg.AuthScheme.Type != SchemeTypeOAuth2 || cfg.AuthScheme.Flows == nil {
+		if cred.OAuth2.AccessToken != "" {
+			// Nothing to exchange with, use the token as is.
+			return cred.clone(), nil
+		}
+		return nil, fmt.Errorf("OAuth2 credential requires an auth scheme with OAuth2 flows")
+	}
+	flows := cfg.AuthScheme.Flows
+
+	current := &oauth2.Token{
+		AccessToken:  cred.OAuth2.AccessToken,
+		RefreshToken: cred.OAuth2.RefreshToken,
+	}
+	if cred.OAuth2.ExpiresAt != 0 {
+		current.Expiry = time.Unix(cred.OAuth2.ExpiresAt, 0)
+	}
+
+	var (
+		token *oauth2.Token
+		err   error
+	)
+	switch {
+	case current.Valid():
+		return cred.clone(), nil
+	case flows.AuthorizationCode != nil && current.RefreshToken != "":
+		token, err = oauth2Config(flows.AuthorizationCode, cred.OAuth2).TokenSource(ctx, current).Token()
+	case flows.AuthorizationCode != nil && (cred.OAuth2.AuthCode != "" || cred.OAuth2.AuthResponseURI != ""):
+		var code string
+		code, err = authCode(cred.OAuth2)
+		if err != nil {
+			return nil, err
+		}
+		token, err = oauth2Config(flows.AuthorizationCode, cred.OAuth2).Exchange(ctx, code)
+	case flows.ClientCredentials != nil:
+		conf := &clientcredentials.Config{
+			ClientID:     cred.OAuth2.ClientID,
+			ClientSecret: cred.OAuth2.ClientSecret,
+			TokenURL:     flows.ClientCredentials.TokenURL,
+			Scopes:       slices.Sorted(maps.Keys(flows.ClientCredentials.Scopes)),
+		}
+		token, err = conf.Token(ctx)
+	default:
+		return nil, ErrUserAuthorizationRequired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange OAuth2 token: %w", err)
+	}
+
+	exchanged := cred.clone()
+	exchanged.OAuth2.AuthCode = ""
+	exchanged.OAuth2.AccessToken = token.AccessToken
+	if token.RefreshToken != "" {
+		exchanged.OAuth2.RefreshToken = token.RefreshToken
+	}
+	exchanged.OAuth2.ExpiresAt = 0
+	if !token.Expiry.IsZero() {
+		exchanged.OAuth2.ExpiresAt = token.Expiry.Unix()
+	}
+	return exchanged, nil
+}
+
+// authCode returns the authorization code of the credential, parsing it from
+// the AuthResponseURI if needed.
+func authCode(cred *OAuth2Auth) (string, error) {
+	if cred.AuthCode != "" {
+		return cred.AuthCode, nil
+	}
+	u, err := url.Parse(cred.AuthResponseURI)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse auth response URI: %w", err)
+	}
+	query := u.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		return "", fmt.Errorf("authorization failed: %s", errCode)
+	}
+	if cred.State != "" && query.Get("state") != cred.State {
+		return "", fmt.Errorf("auth response state does not match the request state")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("auth response URI has no authorization code")
+	}
+	return code, nil
+}
+
+func oauth2Config(flow *OAuthFlow, cred *OAuth2Auth) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     cred.ClientID,
+		ClientSecret: cred.ClientSecret,
+		RedirectURL:  cred.RedirectURI,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  flow.AuthorizationURL,
+			TokenURL: flow.TokenURL,
+		},
+		Scopes: slices.Sorted(maps.Keys(flow.Scopes)),
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ApplyCredential authenticates the HTTP request with the credential, as
+// described by the auth scheme. The credential must be ready to be used,
+// see [Exchange].
+func ApplyCredential(req *http.Request, scheme *AuthScheme, cred *AuthCredential) error {
+	if cred == nil {
+		return fmt.Errorf("credential is nil")
+	}
+	switch cred.AuthType {
+	case CredentialTypeAPIKey:
+		if scheme == nil || scheme.Type != SchemeTypeAPIKey || scheme.Name == "" {
+			return fmt.Errorf("API key credential requires an API key auth scheme with a parameter name")
+		}
+		switch scheme.In {
+		case "header":
+			req.Header.Set(scheme.Name, cred.APIKey)
+		case "query":
+			query := req.URL.Query()
+			query.Set(scheme.Name, cred.APIKey)
+			req.URL.RawQuery = query.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: scheme.Name, Value: cred.APIKey})
+		default:
+			return fmt.Errorf("unsupported API key location %q", scheme.In)
+		}
+	case CredentialTypeHTTP:
+		if cred.HTTP == nil {
+			return fmt.Errorf("HTTP credential is nil")
+		}
+		switch strings.ToLower(cred.HTTP.Scheme) {
+		case "basic":
+			req.SetBasicAuth(cred.HTTP.Credentials.Username, cred.HTTP.Credentials.Password)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+cred.HTTP.Credentials.Token)
+		default:
+			req.Header.Set("Authorization", cred.HTTP.Scheme+" "+cred.HTTP.Credentials.Token)
+		}
+	case CredentialTypeOAuth2:
+		if cred.OAuth2 == nil || cred.OAuth2.AccessToken == "" {
+			return fmt.Errorf("OAuth2 credential has no access token")
+		}
+		req.Header.Set("Authorization", "Bearer "+cred.OAuth2.AccessToken)
+	default:
+		return fmt.Errorf("credential of type %q cannot be applied to a request, exchange it first", cred.AuthType)
+	}
+	return nil
+}
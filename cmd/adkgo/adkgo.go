@@ -17,7 +17,9 @@ package main
 
 import (
 	_ "google.golang.org/adk/cmd/adkgo/internal/deploy/cloudrun"
+	_ "google.golang.org/adk/cmd/adkgo/internal/eval"
 	"google.golang.org/adk/cmd/adkgo/internal/root"
+	_ "google.golang.org/adk/cmd/adkgo/internal/run"
 )
 
 func main() {
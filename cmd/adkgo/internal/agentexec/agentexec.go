@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agentexec compiles an agent application and runs it with a
+// sublauncher of its launcher.
+package agentexec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"google.golang.org/adk/internal/cli/util"
+)
+
+// Run compiles the Go package of the agent application, i.e. a directory
+// or a main .go file, then runs it with the arguments, i.e. a sublauncher
+// keyword and its flags. The application must use a launcher providing the
+// sublauncher, like the full launcher.
+//
+// The output of the application is passed through. Run returns an error if
+// the application exits with a non-zero code.
+func Run(pkg string, args ...string) error {
+	tempDir, err := os.MkdirTemp("", "adkgo_*")
+	if err != nil {
+		return fmt.Errorf("cannot create a temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	execPath := filepath.Join(tempDir, "agent")
+	err = util.LogStartStop("Compiling agent",
+		func(p util.Printer) error {
+			p("Using", pkg, "as entry point")
+			return util.LogCommand(exec.Command("go", "build", "-o", execPath, pkg), p)
+		})
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(execPath, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("agent exited with error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval handles command line parameters and execution logic for
+// running eval sets against an agent.
+package eval
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"google.golang.org/adk/cmd/adkgo/internal/agentexec"
+	"google.golang.org/adk/cmd/adkgo/internal/root"
+)
+
+type evalFlags struct {
+	configFilePath       string
+	junitOutput          string
+	printDetailedResults bool
+}
+
+var flags evalFlags
+
+// evalCmd represents the eval command
+var evalCmd = &cobra.Command{
+	Use:   "eval <package> <eval set file>[:<eval ID>,...] ...",
+	Short: "Runs eval sets against an agent.",
+	Long: `Compiles the agent application and runs the eval sets against its root agent.
+	The application must use the full launcher, or a launcher including the eval sublauncher.
+	Exits with an error if any eval case fails.
+	`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return flags.runEval(args[0], args[1:])
+	},
+}
+
+// init creates flags and adds subcommand to parent
+func init() {
+	root.RootCmd.AddCommand(evalCmd)
+
+	evalCmd.PersistentFlags().StringVar(&flags.configFilePath, "config_file_path", "", "Path to a JSON file with the pass/fail thresholds of the metrics, defaults to test_config.json next to the eval set file")
+	evalCmd.PersistentFlags().StringVar(&flags.junitOutput, "junit_output", "", "Path of a JUnit XML report to write")
+	evalCmd.PersistentFlags().BoolVar(&flags.printDetailedResults, "print_detailed_results", false, "Prints the scores of each metric for each eval case")
+}
+
+// runEval runs the eval sublauncher of the agent application
+func (f *evalFlags) runEval(pkg string, evalSets []string) error {
+	args := []string{"eval", fmt.Sprintf("-print_detailed_results=%t", f.printDetailedResults)}
+	if f.configFilePath != "" {
+		args = append(args, "-config_file_path", f.configFilePath)
+	}
+	if f.junitOutput != "" {
+		args = append(args, "-junit_output", f.junitOutput)
+	}
+	for _, evalSet := range evalSets {
+		absp, err := filepath.Abs(evalSet)
+		if err != nil {
+			return fmt.Errorf("cannot make an absolute path from '%v': %w", evalSet, err)
+		}
+		args = append(args, absp)
+	}
+	return agentexec.Run(pkg, args...)
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package run handles command line parameters and execution logic for
+// replaying a recorded session against an agent.
+package run
+
+import (
+	"github.com/spf13/cobra"
+
+	"google.golang.org/adk/cmd/adkgo/internal/agentexec"
+	"google.golang.org/adk/cmd/adkgo/internal/root"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run <package> <session file>",
+	Short: "Replays a recorded session against an agent.",
+	Long: `Compiles the agent application and sends the user messages of the session file to its root agent, printing the responses.
+	The session file is either a session as returned by the REST API, or {"state": {...}, "queries": ["..."]}.
+	The application must use the full launcher, or a launcher including the replay sublauncher.
+	`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return agentexec.Run(args[0], "replay", args[1])
+	},
+}
+
+// init adds subcommand to parent
+func init() {
+	root.RootCmd.AddCommand(runCmd)
+}
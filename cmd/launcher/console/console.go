@@ -102,11 +102,19 @@ func (l *consoleLauncher) Run(ctx context.Context, config *launcher.Config) erro
 	session := resp.Session
 
 	r, err := runner.New(runner.Config{
-		AppName:         appName,
-		Agent:           rootAgent,
-		SessionService:  sessionService,
-		ArtifactService: config.ArtifactService,
-		PluginConfig:    config.PluginConfig,
+		AppName:            appName,
+		Agent:              rootAgent,
+		SessionService:     sessionService,
+		ArtifactService:    config.ArtifactService,
+		MemoryService:      config.MemoryService,
+		CredentialService:  config.CredentialService,
+		PluginConfig:       config.PluginConfig,
+		Compaction:         config.Compaction,
+		ContextCacheConfig: config.ContextCacheConfig,
+		Resumable:          config.Resumable,
+		RetryPolicy:        config.RetryPolicy,
+		RateLimiter:        config.RateLimiter,
+		AutoIngestMemory:   config.AutoIngestMemory,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create runner: %v", err)
@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval provides a sublauncher running eval sets against the agent,
+// e.g. in CI.
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/cmd/launcher"
+	adkeval "google.golang.org/adk/eval"
+	"google.golang.org/adk/internal/cli/util"
+)
+
+// evalConfig contains command-line params for eval launcher
+type evalConfig struct {
+	configFilePath       string
+	junitOutput          string
+	printDetailedResults bool
+	evalSetPaths         []string // positional arguments, optionally suffixed with :<eval ID>,<eval ID>
+}
+
+// evalLauncher runs eval sets against the root agent
+type evalLauncher struct {
+	flags  *flag.FlagSet
+	config *evalConfig
+}
+
+// NewLauncher creates new eval launcher
+func NewLauncher() launcher.SubLauncher {
+	config := &evalConfig{}
+
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fs.StringVar(&config.configFilePath, "config_file_path", "", `Path to a JSON file with the pass/fail thresholds of the metrics, i.e. {"criteria": {"tool_trajectory_avg_score": 1.0, "response_match_score": 0.8}}. Defaults to test_config.json next to the eval set file if it exists, otherwise to the default metrics.`)
+	fs.StringVar(&config.junitOutput, "junit_output", "", "Path of a JUnit XML report to write, i.e. for CI")
+	fs.BoolVar(&config.printDetailedResults, "print_detailed_results", false, "Prints the scores of each metric for each eval case")
+	return &evalLauncher{config: config, flags: fs}
+}
+
+// Keyword implements launcher.SubLauncher.
+func (l *evalLauncher) Keyword() string {
+	return "eval"
+}
+
+// Parse implements launcher.SubLauncher. The arguments after the flags are
+// the paths of the eval set files, each one optionally followed by
+// :<eval ID>,<eval ID> to run only these eval cases.
+func (l *evalLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse eval flags: %v", err)
+	}
+	l.config.evalSetPaths = l.flags.Args()
+	if len(l.config.evalSetPaths) == 0 {
+		return nil, fmt.Errorf("at least one eval set file is required")
+	}
+	return nil, nil
+}
+
+// CommandLineSyntax implements launcher.SubLauncher.
+func (l *evalLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags) + "  <eval set file>[:<eval ID>,...] ...\n"
+}
+
+// SimpleDescription implements launcher.SubLauncher.
+func (l *evalLauncher) SimpleDescription() string {
+	return "runs eval sets against the agent and reports the failing eval cases"
+}
+
+// Run implements launcher.SubLauncher. It returns an error if any eval case
+// failed.
+func (l *evalLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	rootAgent := config.AgentLoader.RootAgent()
+	var results []*adkeval.SetResult
+	total, failed := 0, 0
+	for _, arg := range l.config.evalSetPaths {
+		path, evalIDs := splitEvalSetArg(arg)
+		set, err := adkeval.LoadSet(path)
+		if err != nil {
+			return err
+		}
+		metrics, err := l.metrics(path)
+		if err != nil {
+			return err
+		}
+		result, err := adkeval.Run(ctx, adkeval.RunConfig{
+			AppName:         rootAgent.Name(),
+			Agent:           rootAgent,
+			Set:             set,
+			EvalIDs:         evalIDs,
+			Metrics:         metrics,
+			JudgeModel:      config.EvalJudgeModel,
+			ArtifactService: config.ArtifactService,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run eval set %q: %w", path, err)
+		}
+		if config.EvalSetResultsManager != nil {
+			if err := config.EvalSetResultsManager.Save(ctx, rootAgent.Name(), result); err != nil {
+				return fmt.Errorf("failed to save eval set result: %w", err)
+			}
+		}
+		results = append(results, result)
+
+		fmt.Printf("Eval set %s:\n", set.EvalSetID)
+		for _, c := range result.EvalCaseResults {
+			total++
+			if c.FinalEvalStatus == adkeval.StatusFailed {
+				failed++
+			}
+			fmt.Printf("  %s: %s\n", c.EvalID, c.FinalEvalStatus)
+			if l.config.printDetailedResults || c.FinalEvalStatus == adkeval.StatusFailed {
+				for _, m := range c.OverallEvalMetricResults {
+					fmt.Printf("    %s\n", formatMetricResult(m))
+				}
+			}
+		}
+	}
+	fmt.Printf("%d eval cases passed, %d failed\n", total-failed, failed)
+
+	if l.config.junitOutput != "" {
+		if err := writeJUnit(l.config.junitOutput, results); err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d eval cases failed", failed, total)
+	}
+	return nil
+}
+
+// splitEvalSetArg splits <path>:<eval ID>,<eval ID>. An existing file
+// path is returned as is, even if it contains a colon.
+func splitEvalSetArg(arg string) (string, []string) {
+	if _, err := os.Stat(arg); err == nil {
+		return arg, nil
+	}
+	i := strings.LastIndex(arg, ":")
+	if i < 0 {
+		return arg, nil
+	}
+	return arg[:i], strings.Split(arg[i+1:], ",")
+}
+
+// metrics returns the metrics of the config file, or of test_config.json
+// next to the eval set file, or nil for the default metrics.
+func (l *evalLauncher) metrics(evalSetPath string) ([]adkeval.Metric, error) {
+	path := l.config.configFilePath
+	if path == "" {
+		path = filepath.Join(filepath.Dir(evalSetPath), "test_config.json")
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var testConfig struct {
+		Criteria map[string]json.RawMessage `json:"criteria"`
+	}
+	if err := json.Unmarshal(data, &testConfig); err != nil {
+		return nil, fmt.Errorf("failed to decode eval config %q: %w", path, err)
+	}
+	var metrics []adkeval.Metric
+	// Sorted, for the results to be printed in a stable order.
+	for _, name := range slices.Sorted(maps.Keys(testConfig.Criteria)) {
+		raw := testConfig.Criteria[name]
+		metric := adkeval.Metric{MetricName: name}
+		// A criterion is either a threshold or an object with the threshold.
+		if err := json.Unmarshal(raw, &metric.Threshold); err != nil {
+			var criterion struct {
+				Threshold float64 `json:"threshold"`
+			}
+			if err := json.Unmarshal(raw, &criterion); err != nil {
+				return nil, fmt.Errorf("invalid criterion %q in eval config %q: %w", name, path, err)
+			}
+			metric.Threshold = criterion.Threshold
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics, nil
+}
+
+func formatMetricResult(m *adkeval.MetricResult) string {
+	score := "n/a"
+	if m.Score != nil {
+		score = fmt.Sprintf("%.2f", *m.Score)
+	}
+	return fmt.Sprintf("%s: %s (score %s, threshold %.2f)", m.MetricName, m.EvalStatus, score, m.Threshold)
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"encoding/xml"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/launcher"
+	adkeval "google.golang.org/adk/eval"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+const testEvalSet = `{
+  "eval_set_id": "greetings",
+  "eval_cases": [
+    {
+      "eval_id": "hello",
+      "conversation": [{
+        "user_content": {"role": "user", "parts": [{"text": "hi"}]},
+        "final_response": {"role": "model", "parts": [{"text": "hello there"}]}
+      }]
+    },
+    {
+      "eval_id": "goodbye",
+      "conversation": [{
+        "user_content": {"role": "user", "parts": [{"text": "bye"}]},
+        "final_response": {"role": "model", "parts": [{"text": "goodbye"}]}
+      }]
+    }
+  ]
+}`
+
+func TestEvalLauncher(t *testing.T) {
+	greeter, err := agent.New(agent.Config{
+		Name: "greeter",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Author = "greeter"
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hello there", genai.RoleModel)}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	evalSetPath := filepath.Join(dir, "greetings.evalset.json")
+	if err := os.WriteFile(evalSetPath, []byte(testEvalSet), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "test_config.json"), []byte(`{"criteria": {"response_match_score": {"threshold": 0.9}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := &launcher.Config{AgentLoader: agent.NewSingleLoader(greeter)}
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantErr      bool
+		wantFailures int
+		wantTests    int
+	}{
+		{
+			name:      "passing eval case",
+			args:      []string{evalSetPath + ":hello"},
+			wantTests: 1,
+		},
+		{
+			name:         "failing eval case",
+			args:         []string{evalSetPath},
+			wantErr:      true,
+			wantFailures: 1,
+			wantTests:    2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			junitPath := filepath.Join(t.TempDir(), "report.xml")
+			l := NewLauncher()
+			rest, err := l.Parse(append([]string{"-junit_output", junitPath}, tc.args...))
+			if err != nil || len(rest) > 0 {
+				t.Fatalf("Parse() = %v, %v, want no remaining arguments", rest, err)
+			}
+			if err := l.Run(context.Background(), config); (err != nil) != tc.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			data, err := os.ReadFile(junitPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var report junitTestSuites
+			if err := xml.Unmarshal(data, &report); err != nil {
+				t.Fatalf("failed to decode JUnit report: %v", err)
+			}
+			if report.Tests != tc.wantTests || report.Failures != tc.wantFailures {
+				t.Errorf("JUnit report tests, failures = %d, %d, want %d, %d", report.Tests, report.Failures, tc.wantTests, tc.wantFailures)
+			}
+			for _, c := range report.Suites[0].TestCases {
+				if !strings.Contains(c.SystemOut, "response_match_score") {
+					t.Errorf("JUnit test case %s output = %q, want the metric results", c.Name, c.SystemOut)
+				}
+			}
+		})
+	}
+}
+
+func TestEvalLauncher_Metrics(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"criteria": {"tool_trajectory_avg_score": 1.0, "response_match_score": {"threshold": 0.8}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	l := &evalLauncher{config: &evalConfig{configFilePath: configPath}}
+	got, err := l.metrics(filepath.Join(dir, "set.evalset.json"))
+	if err != nil {
+		t.Fatalf("metrics() error = %v", err)
+	}
+	// The metrics are sorted by name.
+	want := []adkeval.Metric{
+		{MetricName: adkeval.MetricResponseMatchScore, Threshold: 0.8},
+		{MetricName: adkeval.MetricToolTrajectoryAvgScore, Threshold: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("metrics() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Without config file nor test_config.json, the default metrics are used.
+	l = &evalLauncher{config: &evalConfig{}}
+	got, err = l.metrics(filepath.Join(dir, "set.evalset.json"))
+	if err != nil || got != nil {
+		t.Errorf("metrics() = %v, %v, want nil for the default metrics", got, err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	adkeval "google.golang.org/adk/eval"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes the eval set results as a JUnit XML report: a test
+// suite per eval set, a test case per eval case.
+func writeJUnit(path string, results []*adkeval.SetResult) error {
+	report := junitTestSuites{}
+	for _, result := range results {
+		suite := junitTestSuite{Name: result.EvalSetID}
+		for _, c := range result.EvalCaseResults {
+			var details []string
+			for _, m := range c.OverallEvalMetricResults {
+				details = append(details, formatMetricResult(m))
+			}
+			tc := junitTestCase{
+				Name:      c.EvalID,
+				ClassName: result.EvalSetID,
+				SystemOut: strings.Join(details, "\n"),
+			}
+			switch c.FinalEvalStatus {
+			case adkeval.StatusFailed:
+				suite.Failures++
+				tc.Failure = &junitMessage{
+					Message: fmt.Sprintf("eval case %s failed", c.EvalID),
+					Text:    tc.SystemOut,
+				}
+			case adkeval.StatusNotEvaluated:
+				suite.Skipped++
+				tc.Skipped = &junitMessage{Message: "no metric evaluated"}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+		suite.Tests = len(suite.TestCases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), data...), 0o644)
+}
@@ -22,7 +22,11 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/compaction"
+	"google.golang.org/adk/eval"
 	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/telemetry"
@@ -56,11 +60,38 @@ type SubLauncher interface {
 
 // Config contains parameters for web & console execution: sessions, artifacts, agents etc
 type Config struct {
-	SessionService   session.Service
-	ArtifactService  artifact.Service
-	MemoryService    memory.Service
-	AgentLoader      agent.Loader
-	A2AOptions       []a2asrv.RequestHandlerOption
-	PluginConfig     runner.PluginConfig
-	TelemetryOptions []telemetry.Option
+	SessionService  session.Service
+	ArtifactService artifact.Service
+	MemoryService   memory.Service
+	// CredentialService stores the credentials obtained by tools. Optional.
+	CredentialService auth.CredentialService
+	AgentLoader       agent.Loader
+	A2AOptions        []a2asrv.RequestHandlerOption
+	PluginConfig      runner.PluginConfig
+	TelemetryOptions  []telemetry.Option
+	// EvalSetManager stores the eval sets of the eval REST API. Optional.
+	EvalSetManager eval.SetManager
+	// EvalSetResultsManager stores the results of the eval runs. Optional.
+	EvalSetResultsManager eval.SetResultsManager
+	// EvalJudgeModel is the judge model of the LLM-as-judge eval metrics.
+	// Optional.
+	EvalJudgeModel model.LLM
+	// Compaction compacts the session events at the end of the invocations.
+	// Optional.
+	Compaction *compaction.Config
+	// ContextCacheConfig caches the stable prefix of the LLM requests with the
+	// models supporting it. Optional.
+	ContextCacheConfig *model.ContextCacheConfig
+	// Resumable records agent state checkpoints in the session events, so the
+	// invocations can be resumed. Optional.
+	Resumable bool
+	// RetryPolicy retries the failed model calls of the LLM agents which do not
+	// set their own retry policy. Optional.
+	RetryPolicy *model.RetryPolicy
+	// RateLimiter limits the rate of the model calls of the LLM agents which do
+	// not set their own rate limiter. Optional.
+	RateLimiter *model.RateLimiter
+	// AutoIngestMemory adds the sessions to the MemoryService at the end of
+	// each invocation. Optional.
+	AutoIngestMemory bool
 }
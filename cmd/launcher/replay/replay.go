@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay provides a sublauncher replaying the user messages of a
+// recorded session against the agent.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/cli/util"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// replayConfig contains command-line params for replay launcher
+type replayConfig struct {
+	sessionFile string // positional argument
+}
+
+// replayLauncher replays a recorded session against the root agent
+type replayLauncher struct {
+	flags  *flag.FlagSet
+	config *replayConfig
+	out    io.Writer
+}
+
+// NewLauncher creates new replay launcher
+func NewLauncher() launcher.SubLauncher {
+	config := &replayConfig{}
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	return &replayLauncher{config: config, flags: fs, out: os.Stdout}
+}
+
+// Keyword implements launcher.SubLauncher.
+func (l *replayLauncher) Keyword() string {
+	return "replay"
+}
+
+// Parse implements launcher.SubLauncher. The argument after the flags is the
+// path of the session file.
+func (l *replayLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse replay flags: %v", err)
+	}
+	if l.flags.NArg() == 0 {
+		return nil, fmt.Errorf("session file is required")
+	}
+	l.config.sessionFile = l.flags.Arg(0)
+	return l.flags.Args()[1:], nil
+}
+
+// CommandLineSyntax implements launcher.SubLauncher.
+func (l *replayLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(l.flags) + "  <session file>\n"
+}
+
+// SimpleDescription implements launcher.SubLauncher.
+func (l *replayLauncher) SimpleDescription() string {
+	return "replays the user messages of a recorded session file against the agent"
+}
+
+// sessionFile is a recorded session, as returned by the REST API, or a list
+// of queries with an initial state, as used by `adk run --replay` of the
+// Python ADK.
+type sessionFile struct {
+	State   map[string]any `json:"state"`
+	Queries []string       `json:"queries"`
+	Events  []struct {
+		Author  string         `json:"author"`
+		Content *genai.Content `json:"content"`
+	} `json:"events"`
+}
+
+// userContents returns the queries, or the contents of the user events
+// which are not function responses.
+func (f *sessionFile) userContents() []*genai.Content {
+	var contents []*genai.Content
+	for _, q := range f.Queries {
+		contents = append(contents, genai.NewContentFromText(q, genai.RoleUser))
+	}
+	for _, ev := range f.Events {
+		if ev.Author != "user" || ev.Content == nil {
+			continue
+		}
+		isFunctionResponse := false
+		for _, p := range ev.Content.Parts {
+			if p != nil && p.FunctionResponse != nil {
+				isFunctionResponse = true
+			}
+		}
+		if !isFunctionResponse {
+			contents = append(contents, ev.Content)
+		}
+	}
+	return contents
+}
+
+// Run implements launcher.SubLauncher. It prints the messages of the new
+// conversation.
+func (l *replayLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	data, err := os.ReadFile(l.config.sessionFile)
+	if err != nil {
+		return err
+	}
+	var file sessionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to decode session file %q: %w", l.config.sessionFile, err)
+	}
+
+	rootAgent := config.AgentLoader.RootAgent()
+	appName, userID := rootAgent.Name(), "replay_user"
+
+	sessionService := config.SessionService
+	if sessionService == nil {
+		sessionService = session.InMemoryService()
+	}
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName: appName,
+		UserID:  userID,
+		State:   file.State,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:            appName,
+		Agent:              rootAgent,
+		SessionService:     sessionService,
+		ArtifactService:    config.ArtifactService,
+		MemoryService:      config.MemoryService,
+		CredentialService:  config.CredentialService,
+		PluginConfig:       config.PluginConfig,
+		Compaction:         config.Compaction,
+		ContextCacheConfig: config.ContextCacheConfig,
+		Resumable:          config.Resumable,
+		RetryPolicy:        config.RetryPolicy,
+		RateLimiter:        config.RateLimiter,
+		AutoIngestMemory:   config.AutoIngestMemory,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create runner: %w", err)
+	}
+
+	for _, content := range file.userContents() {
+		fmt.Fprintf(l.out, "[user]: %s\n", text(content))
+		for event, err := range r.Run(ctx, userID, resp.Session.ID(), content, agent.RunConfig{}) {
+			if err != nil {
+				return fmt.Errorf("agent error: %w", err)
+			}
+			if t := text(event.Content); t != "" {
+				fmt.Fprintf(l.out, "[%s]: %s\n", event.Author, t)
+			}
+		}
+	}
+	return nil
+}
+
+func text(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range c.Parts {
+		if p != nil && !p.Thought {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
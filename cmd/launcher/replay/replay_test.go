@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestReplayLauncher(t *testing.T) {
+	// The agent echoes the user message, prefixed with the greeting of the
+	// session state if any.
+	echo, err := agent.New(agent.Config{
+		Name: "echo",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				greeting, err := ctx.Session().State().Get("greeting")
+				if err != nil {
+					greeting = "echo"
+				}
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Author = "echo"
+				ev.LLMResponse = model.LLMResponse{
+					Content: genai.NewContentFromText(fmt.Sprintf("%v: %s", greeting, ctx.UserContent().Parts[0].Text), genai.RoleModel),
+				}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &launcher.Config{AgentLoader: agent.NewSingleLoader(echo)}
+
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "recorded session",
+			file: `{
+  "id": "s1",
+  "appName": "echo",
+  "events": [
+    {"author": "user", "content": {"role": "user", "parts": [{"text": "hi"}]}},
+    {"author": "echo", "content": {"role": "model", "parts": [{"functionCall": {"name": "lookup", "args": {}}}]}},
+    {"author": "user", "content": {"role": "user", "parts": [{"functionResponse": {"name": "lookup", "response": {}}}]}},
+    {"author": "echo", "content": {"role": "model", "parts": [{"text": "recorded answer"}]}},
+    {"author": "user", "content": {"role": "user", "parts": [{"text": "bye"}]}}
+  ]
+}`,
+			want: "[user]: hi\n[echo]: echo: hi\n[user]: bye\n[echo]: echo: bye\n",
+		},
+		{
+			name: "queries with initial state",
+			file: `{"state": {"greeting": "hello"}, "queries": ["hi", "how are you?"]}`,
+			want: "[user]: hi\n[echo]: hello: hi\n[user]: how are you?\n[echo]: hello: how are you?\n",
+		},
+		{
+			name:    "invalid file",
+			file:    `{"queries": "hi"}`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.json")
+			if err := os.WriteFile(path, []byte(tc.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			l := NewLauncher().(*replayLauncher)
+			var out bytes.Buffer
+			l.out = &out
+			rest, err := l.Parse([]string{path})
+			if err != nil || len(rest) > 0 {
+				t.Fatalf("Parse() = %v, %v, want no remaining arguments", rest, err)
+			}
+			if err := l.Run(context.Background(), config); (err != nil) != tc.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, out.String()); diff != "" {
+				t.Errorf("Run() output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplayLauncher_Parse(t *testing.T) {
+	l := NewLauncher()
+	if _, err := l.Parse(nil); err == nil {
+		t.Error("Parse() without session file succeeded, want error")
+	}
+	rest, err := l.Parse([]string{"session.json", "web"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"web"}, rest); diff != "" {
+		t.Errorf("Parse() remaining arguments mismatch (-want +got):\n%s", diff)
+	}
+}
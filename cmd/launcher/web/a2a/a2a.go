@@ -101,11 +101,18 @@ func (a *a2aLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 	agent := config.AgentLoader.RootAgent()
 	executor := adka2a.NewExecutor(adka2a.ExecutorConfig{
 		RunnerConfig: runner.Config{
-			AppName:         agent.Name(),
-			Agent:           agent,
-			SessionService:  config.SessionService,
-			ArtifactService: config.ArtifactService,
-			PluginConfig:    config.PluginConfig,
+			AppName:            agent.Name(),
+			Agent:              agent,
+			SessionService:     config.SessionService,
+			ArtifactService:    config.ArtifactService,
+			MemoryService:      config.MemoryService,
+			PluginConfig:       config.PluginConfig,
+			Compaction:         config.Compaction,
+			ContextCacheConfig: config.ContextCacheConfig,
+			Resumable:          config.Resumable,
+			RetryPolicy:        config.RetryPolicy,
+			RateLimiter:        config.RateLimiter,
+			AutoIngestMemory:   config.AutoIngestMemory,
 		},
 	})
 	reqHandler := a2asrv.NewHandler(executor, config.A2AOptions...)
@@ -25,6 +25,7 @@ import (
 
 	"google.golang.org/adk/cmd/launcher"
 	weblauncher "google.golang.org/adk/cmd/launcher/web"
+	"google.golang.org/adk/eval"
 	"google.golang.org/adk/internal/cli/util"
 	"google.golang.org/adk/server/adkrest"
 )
@@ -33,6 +34,7 @@ import (
 type apiConfig struct {
 	frontendAddress string
 	sseWriteTimeout time.Duration
+	evalDir         string
 }
 
 // apiLauncher can launch ADK REST API
@@ -70,14 +72,23 @@ func (a *apiLauncher) UserMessage(webURL string, printer func(v ...any)) {
 
 // SetupSubrouters adds the API router to the parent router.
 func (a *apiLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	// The config is shared with the other sublaunchers.
+	apiConfig := *config
+	if apiConfig.EvalSetManager == nil {
+		apiConfig.EvalSetManager = eval.LocalSetManager(a.config.evalDir)
+	}
+	if apiConfig.EvalSetResultsManager == nil {
+		apiConfig.EvalSetResultsManager = eval.LocalSetResultsManager(a.config.evalDir)
+	}
+
 	// Create the ADK REST API handler
-	apiHandler := adkrest.NewHandler(config, a.config.sseWriteTimeout)
+	apiHandler := adkrest.NewHandler(&apiConfig, a.config.sseWriteTimeout)
 
 	// Wrap it with CORS middleware
 	corsHandler := corsWithArgs(a.config.frontendAddress)(apiHandler)
 
 	// Register it at the /api/ path
-	router.Methods("GET", "POST", "DELETE", "OPTIONS").PathPrefix("/api/").Handler(
+	router.Methods("GET", "POST", "PUT", "DELETE", "OPTIONS").PathPrefix("/api/").Handler(
 		http.StripPrefix("/api", corsHandler),
 	)
 
@@ -110,6 +121,7 @@ func NewLauncher() weblauncher.Sublauncher {
 
 	fs := flag.NewFlagSet("web", flag.ContinueOnError)
 	fs.StringVar(&config.frontendAddress, "webui_address", "localhost:8080", "ADK WebUI address as seen from the user browser. It's used to allow CORS requests. Please specify only hostname and (optionally) port.")
+	fs.StringVar(&config.evalDir, "eval_dir", ".", "Directory of the eval sets and results, stored as <eval_dir>/<app name>/<eval set ID>.evalset.json and <eval_dir>/<app name>/.adk/eval_history/ - unless the launcher config provides eval managers")
 	fs.DurationVar(&config.sseWriteTimeout, "sse-write-timeout", 120*time.Second, "SSE server write timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for writing the SSE response after reading the headers & body")
 
 	return &apiLauncher{
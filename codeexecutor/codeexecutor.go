@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codeexecutor defines the interface used by LLM agents to execute
+// code generated by the model.
+//
+// An executor is set on llmagent.Config.CodeExecutor. When the model replies
+// with a code block (either as an ExecutableCode part or as text wrapped in
+// one of the configured delimiters), the agent runs the code through the
+// executor and feeds the result back to the model as a CodeExecutionResult.
+package codeexecutor
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// CodeExecutor executes code blocks generated by the model.
+type CodeExecutor interface {
+	// Config returns the settings controlling how code blocks are extracted
+	// from the model responses and how the results are reported back.
+	Config() Config
+	// Execute runs the code in the input and returns the result.
+	//
+	// A non-nil error means the code could not be executed at all. Errors
+	// raised by the code itself should be reported in Result.Stderr, so the
+	// model has a chance to fix the code.
+	Execute(ctx context.Context, in *Input) (*Result, error)
+}
+
+// RequestProcessor is implemented by code executors which run the code on
+// the model side. Such executors only adjust the request sent to the model
+// and are never asked to execute code locally.
+type RequestProcessor interface {
+	ProcessRequest(req *model.LLMRequest) error
+}
+
+// Config holds the code block handling settings of a [CodeExecutor].
+type Config struct {
+	// OptimizeDataFile, if true, extracts and processes data files (e.g. CSV)
+	// from the model request and attaches them to the code executor so the
+	// model does not need to read the raw file contents.
+	OptimizeDataFile bool
+	// Stateful, if true, reuses the same execution ID across code executions
+	// of a session so the executor can keep variables between runs.
+	Stateful bool
+	// ErrorRetryAttempts is the number of consecutive failed code executions
+	// in an invocation after which the code execution is skipped. Zero means
+	// no limit.
+	ErrorRetryAttempts int
+	// CodeBlockDelimiters are used to find code blocks in the text parts of
+	// the model response. The first delimiter is used when code blocks are
+	// sent back to the model.
+	CodeBlockDelimiters []Delimiter
+	// ExecutionResultDelimiters wrap code execution results sent back to the
+	// model.
+	ExecutionResultDelimiters Delimiter
+}
+
+// Delimiter is a pair of leading and trailing markers around a block of text.
+type Delimiter struct {
+	Start string
+	End   string
+}
+
+// DefaultConfig returns the default code executor configuration.
+func DefaultConfig() Config {
+	return Config{
+		ErrorRetryAttempts: 2,
+		CodeBlockDelimiters: []Delimiter{
+			{Start: "```tool_code\n", End: "\n```"},
+			{Start: "```python\n", End: "\n```"},
+		},
+		ExecutionResultDelimiters: Delimiter{Start: "```tool_output\n", End: "\n```"},
+	}
+}
+
+// File is a file used as an input or produced as an output of a code
+// execution.
+type File struct {
+	// Name of the file, relative to the working directory of the execution.
+	Name string
+	// Content of the file.
+	Content []byte
+	// MIMEType of the file, e.g. "text/csv".
+	MIMEType string
+}
+
+// Input is the input of a code execution.
+type Input struct {
+	// Code to execute.
+	Code string
+	// InputFiles are made available to the code in its working directory.
+	InputFiles []File
+	// ExecutionID identifies a stateful execution session. It is empty for
+	// stateless executors.
+	ExecutionID string
+}
+
+// Result is the result of a code execution.
+type Result struct {
+	// Stdout is the standard output of the execution.
+	Stdout string
+	// Stderr is the standard error of the execution. A non-empty value means
+	// the execution failed.
+	Stderr string
+	// OutputFiles are the files produced by the execution. They are saved as
+	// artifacts.
+	OutputFiles []File
+}
+
+// BuiltIn is a code executor that uses the code execution tool built into
+// Gemini 2 and later models. The code is executed by the model itself.
+type BuiltIn struct{}
+
+// Config implements CodeExecutor.
+func (BuiltIn) Config() Config {
+	return Config{}
+}
+
+// Execute implements CodeExecutor. Code is executed by the model, so it is
+// never called.
+func (BuiltIn) Execute(ctx context.Context, in *Input) (*Result, error) {
+	return nil, fmt.Errorf("built-in code executor runs code on the model side")
+}
+
+// ProcessRequest adds the code execution tool to the request.
+func (BuiltIn) ProcessRequest(req *model.LLMRequest) error {
+	if req == nil {
+		return fmt.Errorf("llm request is nil")
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	req.Config.Tools = append(req.Config.Tools, &genai.Tool{
+		CodeExecution: &genai.ToolCodeExecution{},
+	})
+	return nil
+}
+
+var (
+	_ CodeExecutor     = BuiltIn{}
+	_ RequestProcessor = BuiltIn{}
+)
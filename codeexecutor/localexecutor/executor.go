@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package localexecutor provides a code executor which runs the model
+// generated code in a subprocess on the local machine.
+//
+// The executor only provides:
+//   - a fresh temporary working directory, home and temporary directory,
+//   - a minimal environment,
+//   - a timeout, after which the subprocess and, on unix systems, all the
+//     processes it started are killed.
+//
+// It is NOT a sandbox: the code runs with the privileges of the current
+// process and has access to its filesystem and network. Only use it with
+// trusted models and inputs, or inside an already isolated environment such
+// as a container.
+package localexecutor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/codeexecutor"
+)
+
+// waitDelay bounds the wait for the output of the processes started by the
+// code once the subprocess exited or was killed.
+const waitDelay = time.Second
+
+// Config is used to create a local code executor.
+type Config struct {
+	// Interpreter is the command used to run the code. The code is passed on
+	// the standard input. Defaults to "python3".
+	Interpreter string
+	// Args are the extra arguments passed to the interpreter. Defaults to
+	// "-" so python reads the program from the standard input.
+	Args []string
+	// Timeout of a single execution. Defaults to 30 seconds.
+	Timeout time.Duration
+	// Env is the environment of the subprocess. When nil, only PATH is
+	// inherited from the current process.
+	Env []string
+
+	// CodeExecutorConfig controls the code block handling. When nil,
+	// codeexecutor.DefaultConfig() is used.
+	CodeExecutorConfig *codeexecutor.Config
+}
+
+// New creates a code executor running code in a local subprocess.
+func New(cfg Config) (codeexecutor.CodeExecutor, error) {
+	if cfg.Interpreter == "" {
+		cfg.Interpreter = "python3"
+		if cfg.Args == nil {
+			cfg.Args = []string{"-"}
+		}
+	}
+	if _, err := exec.LookPath(cfg.Interpreter); err != nil {
+		return nil, fmt.Errorf("interpreter %q not found: %w", cfg.Interpreter, err)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.Env == nil {
+		cfg.Env = []string{"PATH=" + os.Getenv("PATH")}
+	}
+	execCfg := codeexecutor.DefaultConfig()
+	if cfg.CodeExecutorConfig != nil {
+		execCfg = *cfg.CodeExecutorConfig
+	}
+	if execCfg.Stateful {
+		return nil, fmt.Errorf("local executor does not support stateful execution")
+	}
+	return &executor{cfg: cfg, execCfg: execCfg}, nil
+}
+
+type executor struct {
+	cfg     Config
+	execCfg codeexecutor.Config
+}
+
+// Config implements codeexecutor.CodeExecutor.
+func (e *executor) Config() codeexecutor.Config {
+	return e.execCfg
+}
+
+// Execute implements codeexecutor.CodeExecutor.
+func (e *executor) Execute(ctx context.Context, in *codeexecutor.Input) (*codeexecutor.Result, error) {
+	root, err := os.MkdirTemp("", "adk-code-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create working directory: %w", err)
+	}
+	defer os.RemoveAll(root)
+	// The home and temporary directories are kept out of the working
+	// directory, so the caches written there are not collected as outputs.
+	dir, homeDir, tmpDir := filepath.Join(root, "work"), filepath.Join(root, "home"), filepath.Join(root, "tmp")
+	for _, d := range []string{dir, homeDir, tmpDir} {
+		if err := os.Mkdir(d, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create working directory: %w", err)
+		}
+	}
+
+	inputs := make(map[string]bool, len(in.InputFiles))
+	for _, f := range in.InputFiles {
+		path, err := safeJoin(dir, f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, fmt.Errorf("failed to write input file %q: %w", f.Name, err)
+		}
+		if err := os.WriteFile(path, f.Content, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write input file %q: %w", f.Name, err)
+		}
+		inputs[filepath.ToSlash(f.Name)] = true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.cfg.Interpreter, e.cfg.Args...)
+	cmd.Dir = dir
+	cmd.Env = slices.Concat(e.cfg.Env, []string{"HOME=" + homeDir, "TMPDIR=" + tmpDir})
+	cmd.Stdin = bytes.NewBufferString(in.Code)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Without a delay, Run waits for any background process still holding
+	// the output, even past the timeout.
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	err = cmd.Run()
+	// Kill the processes left running in the background.
+	killProcessGroup(cmd)
+	if err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			stderr.WriteString(fmt.Sprintf("\ncode execution timed out after %v", e.cfg.Timeout))
+		} else if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("failed to run %q: %w", e.cfg.Interpreter, err)
+		}
+		if stderr.Len() == 0 {
+			stderr.WriteString(err.Error())
+		}
+	}
+
+	outputs, err := collectOutputFiles(dir, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return &codeexecutor.Result{
+		Stdout:      stdout.String(),
+		Stderr:      stderr.String(),
+		OutputFiles: outputs,
+	}, nil
+}
+
+// collectOutputFiles returns the files created by the execution, skipping
+// the input files.
+func collectOutputFiles(dir string, inputs map[string]bool) ([]codeexecutor.File, error) {
+	var files []codeexecutor.File
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if inputs[rel] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		mimeType := mime.TypeByExtension(filepath.Ext(rel))
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+		files = append(files, codeexecutor.File{Name: rel, Content: content, MIMEType: mimeType})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect output files: %w", err)
+	}
+	return files, nil
+}
+
+// safeJoin joins name to dir, rejecting names escaping dir.
+func safeJoin(dir, name string) (string, error) {
+	if name == "" || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid input file name %q", name)
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localexecutor_test
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/codeexecutor"
+	"google.golang.org/adk/codeexecutor/localexecutor"
+)
+
+func newExecutor(t *testing.T, cfg localexecutor.Config) codeexecutor.CodeExecutor {
+	t.Helper()
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not available")
+	}
+	e, err := localexecutor.New(cfg)
+	if err != nil {
+		t.Fatalf("localexecutor.New() failed: %v", err)
+	}
+	return e
+}
+
+func TestExecute(t *testing.T) {
+	e := newExecutor(t, localexecutor.Config{})
+
+	for _, tc := range []struct {
+		name       string
+		input      *codeexecutor.Input
+		wantStdout string
+		wantStderr string
+		wantFiles  []codeexecutor.File
+	}{
+		{
+			name:       "stdout",
+			input:      &codeexecutor.Input{Code: "print(1 + 2)"},
+			wantStdout: "3\n",
+		},
+		{
+			name:       "error",
+			input:      &codeexecutor.Input{Code: "raise ValueError('boom')"},
+			wantStderr: "ValueError: boom",
+		},
+		{
+			name: "input and output files",
+			input: &codeexecutor.Input{
+				Code: "data = open('in.txt').read()\nopen('out.txt', 'w').write(data.upper())\nprint(len(data))",
+				InputFiles: []codeexecutor.File{
+					{Name: "in.txt", Content: []byte("hello"), MIMEType: "text/plain"},
+				},
+			},
+			wantStdout: "5\n",
+			wantFiles: []codeexecutor.File{
+				{Name: "out.txt", Content: []byte("HELLO"), MIMEType: "text/plain; charset=utf-8"},
+			},
+		},
+		{
+			name: "home and temporary files are not collected",
+			input: &codeexecutor.Input{
+				Code: "import os, tempfile\nopen(os.path.expanduser('~/.cache'), 'w').write('cache')\ntempfile.NamedTemporaryFile(delete=False).write(b'tmp')\nopen('out.txt', 'w').write('out')",
+			},
+			wantFiles: []codeexecutor.File{
+				{Name: "out.txt", Content: []byte("out"), MIMEType: "text/plain; charset=utf-8"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := e.Execute(t.Context(), tc.input)
+			if err != nil {
+				t.Fatalf("Execute() failed: %v", err)
+			}
+			if got.Stdout != tc.wantStdout {
+				t.Errorf("Execute() stdout = %q, want %q", got.Stdout, tc.wantStdout)
+			}
+			if !strings.Contains(got.Stderr, tc.wantStderr) || (tc.wantStderr == "" && got.Stderr != "") {
+				t.Errorf("Execute() stderr = %q, want containing %q", got.Stderr, tc.wantStderr)
+			}
+			if diff := cmp.Diff(tc.wantFiles, got.OutputFiles); diff != "" {
+				t.Errorf("Execute() output files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecute_Timeout(t *testing.T) {
+	e := newExecutor(t, localexecutor.Config{Timeout: 100 * time.Millisecond})
+
+	got, err := e.Execute(t.Context(), &codeexecutor.Input{Code: "import time\ntime.sleep(10)"})
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if !strings.Contains(got.Stderr, "timed out") {
+		t.Errorf("Execute() stderr = %q, want timeout error", got.Stderr)
+	}
+}
+
+func TestExecute_BackgroundProcess(t *testing.T) {
+	// The background process holds the output of the subprocess.
+	background := "import subprocess, sys\nsubprocess.Popen([sys.executable, '-c', 'import time; time.sleep(30)'])\n"
+
+	for _, tc := range []struct {
+		name       string
+		code       string
+		wantStderr string
+	}{
+		{
+			name: "exited",
+			code: background + "print('done')",
+		},
+		{
+			name:       "timed out",
+			code:       background + "import time\ntime.sleep(30)",
+			wantStderr: "timed out",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newExecutor(t, localexecutor.Config{Timeout: time.Second})
+
+			start := time.Now()
+			got, err := e.Execute(t.Context(), &codeexecutor.Input{Code: tc.code})
+			if err != nil {
+				t.Fatalf("Execute() failed: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("Execute() took %v, want it to not wait for the background process", elapsed)
+			}
+			if !strings.Contains(got.Stderr, tc.wantStderr) {
+				t.Errorf("Execute() stderr = %q, want containing %q", got.Stderr, tc.wantStderr)
+			}
+		})
+	}
+}
+
+func TestExecute_InvalidInputFile(t *testing.T) {
+	e := newExecutor(t, localexecutor.Config{})
+
+	_, err := e.Execute(t.Context(), &codeexecutor.Input{
+		Code:       "print(1)",
+		InputFiles: []codeexecutor.File{{Name: "../escape.txt"}},
+	})
+	if err == nil {
+		t.Error("Execute() succeeded, want error for a file outside of the working directory")
+	}
+}
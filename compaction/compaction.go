@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compaction provides the strategies to compact the events of long
+// sessions, so the LLM requests keep fitting in the model context window.
+//
+// A compaction summarizes a range of session events. The runner appends an
+// event holding the summary in its Actions.Compaction, and the summary
+// replaces the compacted events in the next LLM requests. The events stay in
+// the session.
+//
+// The compaction is configured with runner.Config.Compaction and runs at the
+// end of each invocation.
+package compaction
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/session"
+)
+
+// Config configures when and how the session events are compacted.
+//
+// Two triggers are supported, at least one must be enabled:
+//   - Sliding window: every Interval invocations, the new invocations are
+//     summarized together with the Overlap invocations preceding them.
+//   - Token threshold: once the prompt of the latest LLM request reaches
+//     TokenThreshold tokens, as reported by the UsageMetadata of its
+//     response, the whole conversation but the latest RetainedEvents events
+//     is summarized.
+type Config struct {
+	// Summarizer summarizes the compacted events. Required.
+	Summarizer Summarizer
+	// Interval is the number of new invocations triggering a sliding window
+	// compaction. Zero disables the sliding window compaction.
+	Interval int
+	// Overlap is the number of already compacted invocations included again
+	// in the next sliding window, so consecutive summaries share context.
+	Overlap int
+	// TokenThreshold is the number of prompt tokens triggering a compaction.
+	// Zero disables the token threshold compaction.
+	TokenThreshold int32
+	// RetainedEvents is the number of latest events left out of a compaction
+	// triggered by the TokenThreshold.
+	RetainedEvents int
+}
+
+// Summarizer summarizes session events.
+type Summarizer interface {
+	// Summarize returns the summary of the events. The events are in
+	// chronological order and may include the summary of a previous
+	// compaction, as an event holding the CompactedContent.
+	Summarize(ctx context.Context, events []*session.Event) (*genai.Content, error)
+}
+
+// Validate checks the config.
+func (c *Config) Validate() error {
+	if c.Summarizer == nil {
+		return fmt.Errorf("compaction summarizer is required")
+	}
+	if c.Interval < 0 || c.Overlap < 0 || c.TokenThreshold < 0 || c.RetainedEvents < 0 {
+		return fmt.Errorf("compaction interval, overlap, token threshold and retained events must not be negative")
+	}
+	if c.Interval == 0 && c.TokenThreshold == 0 {
+		return fmt.Errorf("compaction requires an interval or a token threshold")
+	}
+	return nil
+}
+
+// Compact returns the compaction of the events if one of the triggers of the
+// config is met, nil otherwise. The token threshold is checked first.
+func (c *Config) Compact(ctx context.Context, events session.Events) (*session.EventCompaction, error) {
+	all := make([]*session.Event, 0, events.Len())
+	for ev := range events.All() {
+		all = append(all, ev)
+	}
+	var toCompact []*session.Event
+	var compactedIDs []string
+	if c.TokenThreshold > 0 {
+		toCompact, compactedIDs = c.tokenThresholdWindow(all)
+	}
+	if len(toCompact) == 0 && c.Interval > 0 {
+		toCompact, compactedIDs = c.slidingWindow(all)
+	}
+	if len(toCompact) == 0 {
+		return nil, nil
+	}
+
+	summary, err := c.Summarizer.Summarize(ctx, toCompact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize events: %w", err)
+	}
+	if summary == nil {
+		return nil, nil
+	}
+	return &session.EventCompaction{
+		StartTime:        toCompact[0].Timestamp,
+		EndTime:          toCompact[len(toCompact)-1].Timestamp,
+		EventIDs:         compactedIDs,
+		CompactedContent: summary,
+	}, nil
+}
+
+// slidingWindow returns the events to compact, and their IDs, once Interval
+// invocations completed since the last compaction.
+func (c *Config) slidingWindow(events []*session.Event) ([]*session.Event, []string) {
+	// reference: adk-python src/google/adk/apps/compaction.py _run_compaction_for_sliding_window
+	compacted := lastCompactedIDs(events)
+
+	// The invocations in order of appearance, and those with events not
+	// compacted yet.
+	var invocationIDs []string
+	seen := make(map[string]bool)
+	isNew := make(map[string]bool)
+	for _, ev := range events {
+		if ev.Actions.Compaction != nil || ev.InvocationID == "" {
+			continue
+		}
+		if !seen[ev.InvocationID] {
+			seen[ev.InvocationID] = true
+			invocationIDs = append(invocationIDs, ev.InvocationID)
+		}
+		if !compacted[ev.ID] {
+			isNew[ev.InvocationID] = true
+		}
+	}
+	firstNew := slices.IndexFunc(invocationIDs, func(id string) bool { return isNew[id] })
+	if firstNew < 0 || len(invocationIDs)-firstNew < c.Interval {
+		return nil, nil
+	}
+
+	window := make(map[string]bool)
+	for _, id := range invocationIDs[max(0, firstNew-c.Overlap):] {
+		window[id] = true
+	}
+	var toCompact []*session.Event
+	var ids []string
+	for _, ev := range events {
+		if ev.Actions.Compaction == nil && window[ev.InvocationID] {
+			toCompact = append(toCompact, ev)
+			ids = append(ids, ev.ID)
+		}
+	}
+	return toCompact, ids
+}
+
+// tokenThresholdWindow returns the events to compact, and the IDs of the
+// compacted events, if the latest LLM request reached the TokenThreshold.
+// The summary of the last compaction is included, so the new compaction
+// supersedes it.
+func (c *Config) tokenThresholdWindow(events []*session.Event) ([]*session.Event, []string) {
+	last := lastCompaction(events)
+	var promptTokens int32
+	for i := len(events) - 1; i > last; i-- {
+		if usage := events[i].UsageMetadata; usage != nil {
+			promptTokens = usage.PromptTokenCount
+			break
+		}
+	}
+	if promptTokens < c.TokenThreshold {
+		return nil, nil
+	}
+
+	end := len(events) - c.RetainedEvents
+	var toCompact []*session.Event
+	var ids []string
+	if last >= 0 {
+		compaction := events[last].Actions.Compaction
+		summary := &session.Event{
+			ID:           events[last].ID,
+			Timestamp:    compaction.StartTime,
+			InvocationID: events[last].InvocationID,
+			Author:       events[last].Author,
+		}
+		summary.Content = compaction.CompactedContent
+		toCompact = append(toCompact, summary)
+		ids = slices.Clone(compaction.EventIDs)
+	}
+	compacted := lastCompactedIDs(events)
+	for _, ev := range events[:max(0, end)] {
+		if ev.Actions.Compaction == nil && !compacted[ev.ID] {
+			toCompact = append(toCompact, ev)
+			ids = append(ids, ev.ID)
+		}
+	}
+	if last >= 0 && len(toCompact) == 1 {
+		// Nothing new to compact.
+		return nil, nil
+	}
+	return toCompact, ids
+}
+
+// lastCompaction returns the index of the last compaction event, -1 if
+// there is none.
+func lastCompaction(events []*session.Event) int {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Actions.Compaction != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastCompactedIDs returns the IDs of the events compacted by the last
+// compaction.
+func lastCompactedIDs(events []*session.Event) map[string]bool {
+	ids := make(map[string]bool)
+	if last := lastCompaction(events); last >= 0 {
+		for _, id := range events[last].Actions.Compaction.EventIDs {
+			ids[id] = true
+		}
+	}
+	return ids
+}
@@ -0,0 +1,406 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/compaction"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// recordingSummarizer returns the texts of the summarized events, joined.
+type recordingSummarizer struct {
+	calls int
+}
+
+func (s *recordingSummarizer) Summarize(ctx context.Context, events []*session.Event) (*genai.Content, error) {
+	s.calls++
+	var texts []string
+	for _, ev := range events {
+		if ev.Content != nil {
+			texts = append(texts, ev.Content.Parts[0].Text)
+		}
+	}
+	return genai.NewContentFromText(strings.Join(texts, ","), genai.RoleModel), nil
+}
+
+type events []*session.Event
+
+func (e events) All() iter.Seq[*session.Event] { return slices.Values(e) }
+func (e events) Len() int                      { return len(e) }
+func (e events) At(i int) *session.Event       { return e[i] }
+
+var base = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func at(i int) time.Time { return base.Add(time.Duration(i) * time.Second) }
+
+func id(i int) string { return fmt.Sprintf("e%d", i) }
+
+func ids(is ...int) []string {
+	var ids []string
+	for _, i := range is {
+		ids = append(ids, id(i))
+	}
+	return ids
+}
+
+func textEvent(i int, invocationID, text string) *session.Event {
+	return &session.Event{
+		ID:           id(i),
+		Timestamp:    at(i),
+		InvocationID: invocationID,
+		LLMResponse:  model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+	}
+}
+
+func usageEvent(i int, invocationID, text string, promptTokens int32) *session.Event {
+	ev := textEvent(i, invocationID, text)
+	ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: promptTokens}
+	return ev
+}
+
+func compactionEvent(i, start, end int, compacted []string, summary string) *session.Event {
+	return &session.Event{
+		ID:        id(i),
+		Timestamp: at(i),
+		Actions: session.EventActions{Compaction: &session.EventCompaction{
+			StartTime:        at(start),
+			EndTime:          at(end),
+			EventIDs:         compacted,
+			CompactedContent: genai.NewContentFromText(summary, genai.RoleModel),
+		}},
+	}
+}
+
+// sameTimestamp sets the timestamps of the events to the same value, as in
+// the session stores with a low timestamp precision.
+func sameTimestamp(events events) events {
+	for _, ev := range events {
+		ev.Timestamp = base
+		if c := ev.Actions.Compaction; c != nil {
+			c.StartTime, c.EndTime = base, base
+		}
+	}
+	return events
+}
+
+func TestConfig_Compact(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		cfg    compaction.Config
+		events events
+		want   *session.EventCompaction
+	}{
+		{
+			name: "sliding window not reached",
+			cfg:  compaction.Config{Interval: 2},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv1", "b"),
+			},
+		},
+		{
+			name: "sliding window",
+			cfg:  compaction.Config{Interval: 2},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv1", "b"),
+				textEvent(3, "inv2", "c"),
+			},
+			want: &session.EventCompaction{
+				StartTime:        at(1),
+				EndTime:          at(3),
+				EventIDs:         ids(1, 2, 3),
+				CompactedContent: genai.NewContentFromText("a,b,c", genai.RoleModel),
+			},
+		},
+		{
+			name: "sliding window since last compaction with overlap",
+			cfg:  compaction.Config{Interval: 2, Overlap: 1},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv2", "b"),
+				compactionEvent(3, 1, 2, ids(1, 2), "a,b"),
+				textEvent(4, "inv3", "c"),
+				textEvent(5, "inv4", "d"),
+			},
+			want: &session.EventCompaction{
+				StartTime:        at(2),
+				EndTime:          at(5),
+				EventIDs:         ids(2, 4, 5),
+				CompactedContent: genai.NewContentFromText("b,c,d", genai.RoleModel),
+			},
+		},
+		{
+			name: "sliding window counts only new invocations",
+			cfg:  compaction.Config{Interval: 2},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv2", "b"),
+				compactionEvent(3, 1, 2, ids(1, 2), "a,b"),
+				textEvent(4, "inv3", "c"),
+			},
+		},
+		{
+			name: "token threshold not reached",
+			cfg:  compaction.Config{TokenThreshold: 100},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				usageEvent(2, "inv1", "b", 99),
+			},
+		},
+		{
+			name: "token threshold with retained events",
+			cfg:  compaction.Config{TokenThreshold: 100, RetainedEvents: 1},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv1", "b"),
+				usageEvent(3, "inv1", "c", 100),
+			},
+			want: &session.EventCompaction{
+				StartTime:        at(1),
+				EndTime:          at(2),
+				EventIDs:         ids(1, 2),
+				CompactedContent: genai.NewContentFromText("a,b", genai.RoleModel),
+			},
+		},
+		{
+			name: "token threshold supersedes last compaction",
+			cfg:  compaction.Config{TokenThreshold: 100},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv1", "b"),
+				compactionEvent(3, 1, 2, ids(1, 2), "a,b"),
+				usageEvent(4, "inv2", "c", 150),
+			},
+			want: &session.EventCompaction{
+				StartTime:        at(1),
+				EndTime:          at(4),
+				EventIDs:         ids(1, 2, 4),
+				CompactedContent: genai.NewContentFromText("a,b,c", genai.RoleModel),
+			},
+		},
+		{
+			name: "same timestamps",
+			cfg:  compaction.Config{Interval: 2},
+			events: sameTimestamp(events{
+				textEvent(1, "inv1", "a"),
+				textEvent(2, "inv2", "b"),
+				compactionEvent(3, 1, 2, ids(1, 2), "a,b"),
+				textEvent(4, "inv3", "c"),
+				textEvent(5, "inv4", "d"),
+			}),
+			want: &session.EventCompaction{
+				StartTime:        base,
+				EndTime:          base,
+				EventIDs:         ids(4, 5),
+				CompactedContent: genai.NewContentFromText("c,d", genai.RoleModel),
+			},
+		},
+		{
+			name: "token usage before last compaction is ignored",
+			cfg:  compaction.Config{TokenThreshold: 100},
+			events: events{
+				textEvent(1, "inv1", "a"),
+				usageEvent(2, "inv1", "b", 150),
+				compactionEvent(3, 1, 2, ids(1, 2), "a,b"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Summarizer = &recordingSummarizer{}
+			got, err := tc.cfg.Compact(t.Context(), tc.events)
+			if err != nil {
+				t.Fatalf("Compact() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Compact() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	summarizer := &recordingSummarizer{}
+	for _, tc := range []struct {
+		name    string
+		cfg     compaction.Config
+		wantErr bool
+	}{
+		{name: "sliding window", cfg: compaction.Config{Summarizer: summarizer, Interval: 3, Overlap: 1}},
+		{name: "token threshold", cfg: compaction.Config{Summarizer: summarizer, TokenThreshold: 1000}},
+		{name: "no summarizer", cfg: compaction.Config{Interval: 3}, wantErr: true},
+		{name: "no trigger", cfg: compaction.Config{Summarizer: summarizer}, wantErr: true},
+		{name: "negative overlap", cfg: compaction.Config{Summarizer: summarizer, Interval: 3, Overlap: -1}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.cfg.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLLMSummarizer(t *testing.T) {
+	llm := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("the summary", genai.RoleModel)}}
+	summarizer := compaction.NewLLMSummarizer(llm, "Summarize.")
+
+	call := &session.Event{Author: "agent", LLMResponse: model.LLMResponse{Content: &genai.Content{
+		Role:  genai.RoleModel,
+		Parts: []*genai.Part{genai.NewPartFromFunctionCall("weather", map[string]any{"city": "Paris"})},
+	}}}
+	response := &session.Event{Author: "agent", LLMResponse: model.LLMResponse{Content: &genai.Content{
+		Role:  genai.RoleUser,
+		Parts: []*genai.Part{genai.NewPartFromFunctionResponse("weather", map[string]any{"forecast": "sunny"})},
+	}}}
+	got, err := summarizer.Summarize(t.Context(), []*session.Event{
+		{Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("What is the weather?", genai.RoleUser)}},
+		call,
+		response,
+		{Author: "agent", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("It is sunny.", genai.RoleModel)}},
+	})
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if diff := cmp.Diff(genai.NewContentFromText("the summary", genai.RoleModel), got); diff != "" {
+		t.Errorf("Summarize() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantPrompt := `Summarize.
+
+user: What is the weather?
+agent: [called weather with {"city":"Paris"}]
+agent: [weather returned {"forecast":"sunny"}]
+agent: It is sunny.
+`
+	if len(llm.Requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(llm.Requests))
+	}
+	if diff := cmp.Diff(wantPrompt, llm.Requests[0].Contents[0].Parts[0].Text); diff != "" {
+		t.Errorf("summary prompt mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunnerCompaction(t *testing.T) {
+	ctx := t.Context()
+	const appName, userID, sessionID = "test_app", "user", "session"
+
+	agentModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("a1", genai.RoleModel),
+		genai.NewContentFromText("a2", genai.RoleModel),
+		genai.NewContentFromText("a3", genai.RoleModel),
+	}}
+	testAgent, err := llmagent.New(llmagent.Config{Name: "agent", Model: agentModel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	summaryModel := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("the summary", genai.RoleModel)}}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:        appName,
+		Agent:          testAgent,
+		SessionService: sessionService,
+		Compaction: &compaction.Config{
+			Summarizer: compaction.NewLLMSummarizer(summaryModel, ""),
+			Interval:   2,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{"q1", "q2", "q3"} {
+		for _, err := range r.Run(ctx, userID, sessionID, genai.NewContentFromText(msg, genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+		}
+	}
+
+	if len(summaryModel.Requests) != 1 {
+		t.Fatalf("got %d summary requests, want 1", len(summaryModel.Requests))
+	}
+	want := []*genai.Content{
+		genai.NewContentFromText("the summary", genai.RoleModel),
+		genai.NewContentFromText("q3", genai.RoleUser),
+	}
+	if diff := cmp.Diff(want, agentModel.Requests[2].Contents); diff != "" {
+		t.Errorf("contents of the request after compaction mismatch (-want +got):\n%s", diff)
+	}
+}
+
+type failingSummarizer struct{}
+
+func (failingSummarizer) Summarize(ctx context.Context, events []*session.Event) (*genai.Content, error) {
+	return nil, errors.New("summarizer unavailable")
+}
+
+func TestRunnerCompaction_FailureIsNotFatal(t *testing.T) {
+	ctx := t.Context()
+	const appName, userID, sessionID = "test_app", "user", "session"
+
+	testAgent, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("a1", genai.RoleModel)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:        appName,
+		Agent:          testAgent,
+		SessionService: sessionService,
+		Compaction:     &compaction.Config{Summarizer: failingSummarizer{}, Interval: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range r.Run(ctx, userID, sessionID, genai.NewContentFromText("q1", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v, want the compaction failure to be ignored", err)
+		}
+	}
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Session.Events().Len(); got != 2 {
+		t.Errorf("session has %d events, want the 2 events of the invocation", got)
+	}
+}
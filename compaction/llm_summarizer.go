@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// DefaultSummaryInstruction is the instruction of the LLM summarizer if none
+// is provided. The conversation is appended to it.
+const DefaultSummaryInstruction = "The following is a conversation history between a user and an AI agent. " +
+	"Summarize the conversation, focusing on the key information and the decisions made, " +
+	"as well as any unresolved questions or tasks. " +
+	"The summary should be concise and capture the essence of the interaction."
+
+// NewLLMSummarizer returns a [Summarizer] asking the LLM to summarize the
+// text, function calls and function responses of the events. An empty
+// instruction uses [DefaultSummaryInstruction].
+func NewLLMSummarizer(llm model.LLM, instruction string) Summarizer {
+	if instruction == "" {
+		instruction = DefaultSummaryInstruction
+	}
+	return &llmSummarizer{llm: llm, instruction: instruction}
+}
+
+type llmSummarizer struct {
+	llm         model.LLM
+	instruction string
+}
+
+func (s *llmSummarizer) Summarize(ctx context.Context, events []*session.Event) (*genai.Content, error) {
+	// reference: adk-python src/google/adk/apps/llm_event_summarizer.py
+	history := formatEvents(events)
+	if history == "" {
+		return nil, nil
+	}
+	req := &model.LLMRequest{
+		Model: s.llm.Name(),
+		Contents: []*genai.Content{
+			genai.NewContentFromText(s.instruction+"\n\n"+history, genai.RoleUser),
+		},
+		Config: &genai.GenerateContentConfig{},
+	}
+	var summary *genai.Content
+	for resp, err := range s.llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, err
+		}
+		if resp.ErrorCode != "" {
+			return nil, fmt.Errorf("model returned error %s: %s", resp.ErrorCode, resp.ErrorMessage)
+		}
+		if resp.Content != nil && !resp.Partial {
+			summary = resp.Content
+		}
+	}
+	if summary == nil {
+		return nil, fmt.Errorf("model returned no summary")
+	}
+	summary.Role = genai.RoleModel
+	return summary, nil
+}
+
+// formatEvents renders the events as one line per part, prefixed by the
+// event author.
+func formatEvents(events []*session.Event) string {
+	var sb strings.Builder
+	for _, ev := range events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			var line string
+			switch {
+			case part == nil || part.Thought:
+				continue
+			case part.Text != "":
+				line = part.Text
+			case part.FunctionCall != nil:
+				args, _ := json.Marshal(part.FunctionCall.Args)
+				line = fmt.Sprintf("[called %s with %s]", part.FunctionCall.Name, args)
+			case part.FunctionResponse != nil:
+				resp, _ := json.Marshal(part.FunctionResponse.Response)
+				line = fmt.Sprintf("[%s returned %s]", part.FunctionResponse.Name, resp)
+			default:
+				continue
+			}
+			fmt.Fprintf(&sb, "%s: %s\n", ev.Author, line)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eval evaluates agents against recorded conversations.
+//
+// An eval [Set] holds eval cases, each one a conversation between a user and
+// the agent. [Run] replays the user messages of the cases through the agent
+// and scores the actual conversation against the expected one with metrics,
+// e.g. the match of the tool calls or of the final responses.
+//
+// The eval sets are stored as .evalset.json files, compatible with the
+// Python ADK.
+package eval
+
+import (
+	"google.golang.org/genai"
+)
+
+// Set is a set of eval cases.
+type Set struct {
+	EvalSetID   string  `json:"evalSetId"`
+	Name        string  `json:"name,omitempty"`
+	Description string  `json:"description,omitempty"`
+	EvalCases   []*Case `json:"evalCases"`
+	// CreationTimestamp is in seconds since the Unix epoch.
+	CreationTimestamp float64 `json:"creationTimestamp,omitempty"`
+}
+
+// Case is a conversation used to evaluate the agent.
+type Case struct {
+	EvalID string `json:"evalId"`
+	// Conversation holds the expected invocations, in order.
+	Conversation []*Invocation `json:"conversation"`
+	// SessionInput initializes the session the case runs in.
+	SessionInput *SessionInput `json:"sessionInput,omitempty"`
+	// CreationTimestamp is in seconds since the Unix epoch.
+	CreationTimestamp float64 `json:"creationTimestamp,omitempty"`
+}
+
+// Invocation is a single turn of the conversation: the user message and
+// the agent response.
+type Invocation struct {
+	InvocationID string         `json:"invocationId,omitempty"`
+	UserContent  *genai.Content `json:"userContent"`
+	// FinalResponse is the final response of the agent.
+	FinalResponse *genai.Content `json:"finalResponse,omitempty"`
+	// IntermediateData holds the steps taken by the agent to reach the
+	// final response.
+	IntermediateData *IntermediateData `json:"intermediateData,omitempty"`
+	// CreationTimestamp is in seconds since the Unix epoch.
+	CreationTimestamp float64 `json:"creationTimestamp,omitempty"`
+}
+
+// IntermediateData holds the steps of an invocation.
+type IntermediateData struct {
+	// ToolUses are the tool calls, in order.
+	ToolUses []*genai.FunctionCall `json:"toolUses,omitempty"`
+	// ToolResponses are the tool responses, in order.
+	ToolResponses []*genai.FunctionResponse `json:"toolResponses,omitempty"`
+	// IntermediateResponses are the texts produced by the agents before the
+	// final response.
+	IntermediateResponses []*IntermediateResponse `json:"intermediateResponses,omitempty"`
+}
+
+// IntermediateResponse is a response of an agent before the final response.
+//
+// It is encoded as a JSON array [author, parts], as in the Python ADK.
+type IntermediateResponse struct {
+	Author string
+	Parts  []*genai.Part
+}
+
+// SessionInput initializes the session of an eval case.
+type SessionInput struct {
+	AppName string         `json:"appName,omitempty"`
+	UserID  string         `json:"userId,omitempty"`
+	State   map[string]any `json:"state,omitempty"`
+}
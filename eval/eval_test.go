@@ -0,0 +1,440 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/eval"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestLoadSet_Python(t *testing.T) {
+	// An eval set written by the Python ADK.
+	data := `{
+  "eval_set_id": "weather",
+  "name": "weather",
+  "eval_cases": [{
+    "eval_id": "case_1",
+    "conversation": [{
+      "invocation_id": "inv_1",
+      "user_content": {"parts": [{"text": "Weather in Paris?"}], "role": "user"},
+      "final_response": {"parts": [{"text": "It is sunny."}], "role": "model"},
+      "intermediate_data": {
+        "tool_uses": [{"name": "get_weather", "args": {"city_name": "Paris"}}],
+        "tool_responses": [],
+        "intermediate_responses": [["sub_agent", [{"text": "Checking."}]]]
+      },
+      "creation_timestamp": 1700000000.5
+    }],
+    "session_input": {"app_name": "weather_app", "user_id": "u1", "state": {"user_name": "Ann"}},
+    "creation_timestamp": 1700000000.0
+  }],
+  "creation_timestamp": 1700000000.0
+}`
+	path := filepath.Join(t.TempDir(), "weather.evalset.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := eval.LoadSet(path)
+	if err != nil {
+		t.Fatalf("LoadSet() error = %v", err)
+	}
+	want := &eval.Set{
+		EvalSetID: "weather",
+		Name:      "weather",
+		EvalCases: []*eval.Case{{
+			EvalID: "case_1",
+			Conversation: []*eval.Invocation{{
+				InvocationID:  "inv_1",
+				UserContent:   genai.NewContentFromText("Weather in Paris?", genai.RoleUser),
+				FinalResponse: genai.NewContentFromText("It is sunny.", genai.RoleModel),
+				IntermediateData: &eval.IntermediateData{
+					ToolUses:      []*genai.FunctionCall{{Name: "get_weather", Args: map[string]any{"city_name": "Paris"}}},
+					ToolResponses: []*genai.FunctionResponse{},
+					IntermediateResponses: []*eval.IntermediateResponse{
+						{Author: "sub_agent", Parts: []*genai.Part{{Text: "Checking."}}},
+					},
+				},
+				CreationTimestamp: 1700000000.5,
+			}},
+			SessionInput:      &eval.SessionInput{AppName: "weather_app", UserID: "u1", State: map[string]any{"user_name": "Ann"}},
+			CreationTimestamp: 1700000000,
+		}},
+		CreationTimestamp: 1700000000,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadSet() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLocalSetManager(t *testing.T) {
+	ctx := context.Background()
+	m := eval.LocalSetManager(t.TempDir())
+
+	if ids, err := m.List(ctx, "app"); err != nil || len(ids) != 0 {
+		t.Fatalf("List() = %v, %v, want no eval sets", ids, err)
+	}
+	if _, err := m.Create(ctx, "app", "set_1"); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := m.Create(ctx, "app", "set_1"); err == nil {
+		t.Error("Create() of an existing eval set succeeded, want error")
+	}
+	if _, err := m.Create(ctx, "app", "../set"); err == nil {
+		t.Error("Create() with an invalid ID succeeded, want error")
+	}
+
+	c := &eval.Case{
+		EvalID:       "case_1",
+		Conversation: []*eval.Invocation{{UserContent: genai.NewContentFromText("hi", genai.RoleUser)}},
+	}
+	if err := m.AddCase(ctx, "app", "set_1", c); err != nil {
+		t.Fatalf("AddCase() error = %v", err)
+	}
+	if err := m.AddCase(ctx, "app", "set_1", c); err == nil {
+		t.Error("AddCase() of an existing eval case succeeded, want error")
+	}
+	updated := &eval.Case{
+		EvalID:       "case_1",
+		Conversation: []*eval.Invocation{{UserContent: genai.NewContentFromText("hello", genai.RoleUser)}},
+	}
+	if err := m.UpdateCase(ctx, "app", "set_1", updated); err != nil {
+		t.Fatalf("UpdateCase() error = %v", err)
+	}
+	if err := m.UpdateCase(ctx, "app", "set_1", &eval.Case{EvalID: "../case_1"}); err == nil || errors.Is(err, eval.ErrNotFound) {
+		t.Errorf("UpdateCase() with an invalid ID error = %v, want invalid ID error", err)
+	}
+
+	ids, err := m.List(ctx, "app")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"set_1"}, ids); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+	set, err := m.Get(ctx, "app", "set_1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if diff := cmp.Diff([]*eval.Case{updated}, set.EvalCases); diff != "" {
+		t.Errorf("Get() eval cases mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := m.DeleteCase(ctx, "app", "set_1", "case_1"); err != nil {
+		t.Fatalf("DeleteCase() error = %v", err)
+	}
+	if err := m.DeleteCase(ctx, "app", "set_1", "case_1"); !errors.Is(err, eval.ErrNotFound) {
+		t.Errorf("DeleteCase() of a deleted eval case error = %v, want ErrNotFound", err)
+	}
+	if _, err := m.Get(ctx, "app", "set_2"); !errors.Is(err, eval.ErrNotFound) {
+		t.Errorf("Get() of a missing eval set error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalSetResultsManager(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	m := eval.LocalSetResultsManager(dir)
+
+	score := 1.0
+	result := &eval.SetResult{
+		EvalSetResultID: "app_set_1_1",
+		EvalSetID:       "set_1",
+		EvalCaseResults: []*eval.CaseResult{{
+			EvalSetID:       "set_1",
+			EvalID:          "case_1",
+			FinalEvalStatus: eval.StatusPassed,
+			OverallEvalMetricResults: []*eval.MetricResult{
+				{MetricName: eval.MetricResponseMatchScore, Threshold: 0.8, Score: &score, EvalStatus: eval.StatusPassed},
+			},
+			SessionID: "s1",
+		}},
+		CreationTimestamp: 1700000000,
+	}
+	if err := m.Save(ctx, "app", result); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := m.Get(ctx, "app", "app_set_1_1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if diff := cmp.Diff(result, got); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The Python ADK writes the results as JSON encoded strings.
+	encoded, err := json.Marshal(`{"eval_set_result_id": "app_set_1_1700000001.25", "eval_set_id": "set_1", "eval_case_results": [], "creation_timestamp": 1700000001}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app", ".adk", "eval_history", "app_set_1_1700000001.25.evalset_result.json"), encoded, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = m.Get(ctx, "app", "app_set_1_1700000001.25")
+	if err != nil {
+		t.Fatalf("Get() of a Python result error = %v", err)
+	}
+	want := &eval.SetResult{EvalSetResultID: "app_set_1_1700000001.25", EvalSetID: "set_1", EvalCaseResults: []*eval.CaseResult{}, CreationTimestamp: 1700000001}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Get() of a Python result mismatch (-want +got):\n%s", diff)
+	}
+
+	ids, err := m.List(ctx, "app")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"app_set_1_1", "app_set_1_1700000001.25"}, ids); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := m.Get(ctx, "app", "missing"); !errors.Is(err, eval.ErrNotFound) {
+		t.Errorf("Get() of a missing result error = %v, want ErrNotFound", err)
+	}
+	for _, id := range []string{"..", "../app_set_1_1", "a/b"} {
+		if _, err := m.Get(ctx, "app", id); err == nil || errors.Is(err, eval.ErrNotFound) {
+			t.Errorf("Get(%q) error = %v, want invalid ID error", id, err)
+		}
+	}
+}
+
+func invocation(userText, responseText string, toolUses ...*genai.FunctionCall) *eval.Invocation {
+	return &eval.Invocation{
+		UserContent:      genai.NewContentFromText(userText, genai.RoleUser),
+		FinalResponse:    genai.NewContentFromText(responseText, genai.RoleModel),
+		IntermediateData: &eval.IntermediateData{ToolUses: toolUses},
+	}
+}
+
+func TestNewEvaluator(t *testing.T) {
+	weather := func(city string) *genai.FunctionCall {
+		return &genai.FunctionCall{Name: "get_weather", Args: map[string]any{"city": city}}
+	}
+	tests := []struct {
+		name       string
+		metric     eval.Metric
+		judge      []*genai.Content
+		actual     []*eval.Invocation
+		expected   []*eval.Invocation
+		wantScores []float64
+		wantStatus eval.Status
+	}{
+		{
+			name:       "tool trajectory match",
+			metric:     eval.Metric{MetricName: eval.MetricToolTrajectoryAvgScore, Threshold: 1},
+			actual:     []*eval.Invocation{invocation("q", "a", weather("Paris"))},
+			expected:   []*eval.Invocation{invocation("q", "b", weather("Paris"))},
+			wantScores: []float64{1},
+			wantStatus: eval.StatusPassed,
+		},
+		{
+			name:   "tool trajectory mismatch",
+			metric: eval.Metric{MetricName: eval.MetricToolTrajectoryAvgScore, Threshold: 1},
+			actual: []*eval.Invocation{
+				invocation("q", "a", weather("Paris")),
+				invocation("q", "a", weather("Paris")),
+			},
+			expected: []*eval.Invocation{
+				invocation("q", "a", weather("Paris")),
+				invocation("q", "a", weather("Rome")),
+			},
+			wantScores: []float64{1, 0},
+			wantStatus: eval.StatusFailed,
+		},
+		{
+			name:       "response match",
+			metric:     eval.Metric{MetricName: eval.MetricResponseMatchScore, Threshold: 0.5},
+			actual:     []*eval.Invocation{invocation("q", "The weather is sunny!")},
+			expected:   []*eval.Invocation{invocation("q", "the weather is cloudy")},
+			wantScores: []float64{0.75},
+			wantStatus: eval.StatusPassed,
+		},
+		{
+			name:       "response match with stemming",
+			metric:     eval.Metric{MetricName: eval.MetricResponseMatchScore, Threshold: 0.5},
+			actual:     []*eval.Invocation{invocation("q", "Booked the flights")},
+			expected:   []*eval.Invocation{invocation("q", "booking a flight")},
+			wantScores: []float64{2.0 / 3},
+			wantStatus: eval.StatusPassed,
+		},
+		{
+			name:       "response mismatch",
+			metric:     eval.Metric{MetricName: eval.MetricResponseMatchScore, Threshold: 0.5},
+			actual:     []*eval.Invocation{invocation("q", "No idea.")},
+			expected:   []*eval.Invocation{invocation("q", "It is sunny.")},
+			wantScores: []float64{0},
+			wantStatus: eval.StatusFailed,
+		},
+		{
+			name: "judge majority valid",
+			metric: eval.Metric{
+				MetricName:        eval.MetricFinalResponseMatchV2,
+				Threshold:         0.5,
+				JudgeModelOptions: &eval.JudgeModelOptions{NumSamples: 3},
+			},
+			judge: []*genai.Content{
+				genai.NewContentFromText(`Same facts. "is_the_agent_response_valid": "valid"`, genai.RoleModel),
+				genai.NewContentFromText(`Wrong. "is_the_agent_response_valid": "invalid"`, genai.RoleModel),
+				genai.NewContentFromText(`"is_the_agent_response_valid": "valid"`, genai.RoleModel),
+			},
+			actual:     []*eval.Invocation{invocation("q", "Sunny.")},
+			expected:   []*eval.Invocation{invocation("q", "It is sunny.")},
+			wantScores: []float64{1},
+			wantStatus: eval.StatusPassed,
+		},
+		{
+			name: "judge no verdict",
+			metric: eval.Metric{
+				MetricName:        eval.MetricFinalResponseMatchV2,
+				Threshold:         0.5,
+				JudgeModelOptions: &eval.JudgeModelOptions{NumSamples: 1},
+			},
+			judge:      []*genai.Content{genai.NewContentFromText("I cannot tell.", genai.RoleModel)},
+			actual:     []*eval.Invocation{invocation("q", "Sunny.")},
+			expected:   []*eval.Invocation{invocation("q", "It is sunny.")},
+			wantScores: []float64{0},
+			wantStatus: eval.StatusFailed,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			judge := &testutil.MockModel{Responses: tc.judge}
+			ev, err := eval.NewEvaluator(tc.metric, judge)
+			if err != nil {
+				t.Fatalf("NewEvaluator() error = %v", err)
+			}
+			res, err := ev.Evaluate(context.Background(), tc.actual, tc.expected)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			var scores []float64
+			for _, inv := range res.PerInvocation {
+				scores = append(scores, *inv.Score)
+			}
+			if diff := cmp.Diff(tc.wantScores, scores, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("Evaluate() scores mismatch (-want +got):\n%s", diff)
+			}
+			if res.OverallStatus != tc.wantStatus {
+				t.Errorf("Evaluate() status = %v, want %v", res.OverallStatus, tc.wantStatus)
+			}
+		})
+	}
+
+	if _, err := eval.NewEvaluator(eval.Metric{MetricName: eval.MetricFinalResponseMatchV2}, nil); err == nil {
+		t.Error("NewEvaluator() of a judge metric without judge model succeeded, want error")
+	}
+	if _, err := eval.NewEvaluator(eval.Metric{MetricName: "unknown"}, nil); err == nil {
+		t.Error("NewEvaluator() of an unknown metric succeeded, want error")
+	}
+}
+
+func TestRun(t *testing.T) {
+	type Args struct {
+		City string `json:"city"`
+	}
+	type Result struct {
+		Weather string `json:"weather"`
+	}
+	weather, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "returns the weather of a city",
+	}, func(_ tool.Context, args Args) (Result, error) {
+		return Result{Weather: "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("get_weather", map[string]any{"city": "Paris"}, genai.RoleModel),
+		genai.NewContentFromText("It is sunny in Paris.", genai.RoleModel),
+		genai.NewContentFromText("Goodbye!", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "weather_agent",
+		Model: llm,
+		Tools: []tool.Tool{weather},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := &eval.Set{
+		EvalSetID: "weather",
+		EvalCases: []*eval.Case{{
+			EvalID: "paris",
+			Conversation: []*eval.Invocation{
+				invocation("Weather in Paris?", "It is sunny in Paris.",
+					&genai.FunctionCall{Name: "get_weather", Args: map[string]any{"city": "Paris"}}),
+				invocation("Thanks!", "See you!"),
+			},
+			SessionInput: &eval.SessionInput{UserID: "ann"},
+		}},
+	}
+	result, err := eval.Run(context.Background(), eval.RunConfig{
+		AppName: "weather_app",
+		Agent:   a,
+		Set:     set,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(result.EvalCaseResults) != 1 {
+		t.Fatalf("Run() returned %d eval case results, want 1", len(result.EvalCaseResults))
+	}
+	caseResult := result.EvalCaseResults[0]
+	if caseResult.UserID != "ann" || caseResult.SessionID == "" {
+		t.Errorf("Run() user ID, session ID = %q, %q, want ann and a session ID", caseResult.UserID, caseResult.SessionID)
+	}
+	// The first invocation matches, the second response does not.
+	trajectory, response := 1.0, 0.5
+	want := &eval.CaseResult{
+		EvalSetID:       "weather",
+		EvalID:          "paris",
+		FinalEvalStatus: eval.StatusFailed,
+		OverallEvalMetricResults: []*eval.MetricResult{
+			{MetricName: eval.MetricToolTrajectoryAvgScore, Threshold: 1, Score: &trajectory, EvalStatus: eval.StatusPassed},
+			{MetricName: eval.MetricResponseMatchScore, Threshold: 0.8, Score: &response, EvalStatus: eval.StatusFailed},
+		},
+		UserID: "ann",
+	}
+	ignore := cmpopts.IgnoreFields(eval.CaseResult{}, "SessionID", "EvalMetricResultPerInvocation")
+	if diff := cmp.Diff(want, caseResult, ignore); diff != "" {
+		t.Errorf("Run() eval case result mismatch (-want +got):\n%s", diff)
+	}
+
+	actual := caseResult.EvalMetricResultPerInvocation[0].ActualInvocation
+	if diff := cmp.Diff("get_weather", actual.IntermediateData.ToolUses[0].Name); diff != "" {
+		t.Errorf("Run() actual tool use mismatch (-want +got):\n%s", diff)
+	}
+	if len(actual.IntermediateData.ToolResponses) != 1 {
+		t.Errorf("Run() actual tool responses = %d, want 1", len(actual.IntermediateData.ToolResponses))
+	}
+	if got := actual.FinalResponse.Parts[0].Text; got != "It is sunny in Paris." {
+		t.Errorf("Run() actual final response = %q, want %q", got, "It is sunny in Paris.")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+func (r *IntermediateResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{r.Author, r.Parts})
+}
+
+func (r *IntermediateResponse) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("intermediate response must be an [author, parts] array, got %d elements", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &r.Author); err != nil {
+		return fmt.Errorf("failed to decode intermediate response author: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &r.Parts); err != nil {
+		return fmt.Errorf("failed to decode intermediate response parts: %w", err)
+	}
+	return nil
+}
+
+// userDataKeys are the keys whose values hold user data, the case of their
+// keys is preserved.
+var userDataKeys = map[string]bool{
+	"args":              true,
+	"response":          true,
+	"state":             true,
+	"finalSessionState": true,
+	"customMetadata":    true,
+}
+
+// unmarshal decodes the JSON data into v, accepting the snake_case keys
+// written by the Python ADK as well as the camelCase ones.
+func unmarshal(data []byte, v any) error {
+	var raw any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&raw); err != nil {
+		return err
+	}
+	normalized, err := json.Marshal(camelCaseKeys(raw))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(normalized, v)
+}
+
+func camelCaseKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			k = camelCase(k)
+			if userDataKeys[k] {
+				out[k] = val
+			} else {
+				out[k] = camelCaseKeys(val)
+			}
+		}
+		return out
+	case []any:
+		for i, val := range v {
+			v[i] = camelCaseKeys(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// camelCase converts a snake_case key to camelCase, e.g. eval_set_id to
+// evalSetId.
+func camelCase(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+	parts := strings.Split(key, "_")
+	var sb strings.Builder
+	sb.WriteString(parts[0])
+	for _, p := range parts[1:] {
+		if p == "" {
+			continue
+		}
+		sb.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	return sb.String()
+}
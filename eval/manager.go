@@ -0,0 +1,340 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when an eval set, eval case or eval set result
+// does not exist.
+var ErrNotFound = errors.New("not found")
+
+// SetManager stores the eval sets of the apps.
+type SetManager interface {
+	// Get returns the eval set, or ErrNotFound.
+	Get(ctx context.Context, appName, evalSetID string) (*Set, error)
+	// Create creates an empty eval set. It fails if the eval set exists.
+	Create(ctx context.Context, appName, evalSetID string) (*Set, error)
+	// List returns the IDs of the eval sets of the app.
+	List(ctx context.Context, appName string) ([]string, error)
+	// AddCase adds the eval case to the eval set. It fails if the eval set
+	// already has a case with the same ID.
+	AddCase(ctx context.Context, appName, evalSetID string, evalCase *Case) error
+	// UpdateCase replaces the eval case with the same ID, or returns
+	// ErrNotFound.
+	UpdateCase(ctx context.Context, appName, evalSetID string, evalCase *Case) error
+	// DeleteCase deletes the eval case, or returns ErrNotFound.
+	DeleteCase(ctx context.Context, appName, evalSetID, evalID string) error
+}
+
+// SetResultsManager stores the results of the runs of eval sets.
+type SetResultsManager interface {
+	// Save stores the eval set result.
+	Save(ctx context.Context, appName string, result *SetResult) error
+	// Get returns the eval set result, or ErrNotFound.
+	Get(ctx context.Context, appName, evalSetResultID string) (*SetResult, error)
+	// List returns the IDs of the eval set results of the app.
+	List(ctx context.Context, appName string) ([]string, error)
+}
+
+// Case returns the eval case with the given ID, or nil.
+func (s *Set) Case(evalID string) *Case {
+	for _, c := range s.EvalCases {
+		if c.EvalID == evalID {
+			return c
+		}
+	}
+	return nil
+}
+
+const (
+	setFileSuffix    = ".evalset.json"
+	resultFileSuffix = ".evalset_result.json"
+)
+
+var (
+	validID = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	// The Python ADK result IDs end with a fractional timestamp.
+	validResultID = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+)
+
+func validateID(kind, id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("invalid %s %q: only letters, digits and underscores are allowed", kind, id)
+	}
+	return nil
+}
+
+func validateResultID(id string) error {
+	if !validResultID.MatchString(id) || !filepath.IsLocal(id) {
+		return fmt.Errorf("invalid eval set result ID %q: only letters, digits, underscores, dots and dashes are allowed", id)
+	}
+	return nil
+}
+
+func validateAppName(appName string) error {
+	if appName == "" || strings.ContainsAny(appName, `/\`) || !filepath.IsLocal(appName) {
+		return fmt.Errorf("invalid app name %q", appName)
+	}
+	return nil
+}
+
+// LoadSet reads an eval set file, written by the Go or the Python ADK.
+func LoadSet(path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &Set{}
+	if err := unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to decode eval set %q: %w", path, err)
+	}
+	return set, nil
+}
+
+// LocalSetManager returns a SetManager storing the eval sets in dir, at
+// <dir>/<app name>/<eval set ID>.evalset.json.
+func LocalSetManager(dir string) SetManager {
+	return &localSetManager{dir: dir}
+}
+
+type localSetManager struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func (m *localSetManager) path(appName, evalSetID string) (string, error) {
+	if err := validateAppName(appName); err != nil {
+		return "", err
+	}
+	if err := validateID("eval set ID", evalSetID); err != nil {
+		return "", err
+	}
+	return filepath.Join(m.dir, appName, evalSetID+setFileSuffix), nil
+}
+
+func (m *localSetManager) Get(ctx context.Context, appName, evalSetID string) (*Set, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(appName, evalSetID)
+}
+
+func (m *localSetManager) get(appName, evalSetID string) (*Set, error) {
+	path, err := m.path(appName, evalSetID)
+	if err != nil {
+		return nil, err
+	}
+	set, err := LoadSet(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("eval set %q of app %q: %w", evalSetID, appName, ErrNotFound)
+	}
+	return set, err
+}
+
+func (m *localSetManager) Create(ctx context.Context, appName, evalSetID string) (*Set, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path, err := m.path(appName, evalSetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("eval set %q of app %q already exists", evalSetID, appName)
+	}
+	set := &Set{
+		EvalSetID:         evalSetID,
+		Name:              evalSetID,
+		EvalCases:         []*Case{},
+		CreationTimestamp: timestamp(time.Now()),
+	}
+	if err := writeJSON(path, set); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func (m *localSetManager) List(ctx context.Context, appName string) ([]string, error) {
+	if err := validateAppName(appName); err != nil {
+		return nil, err
+	}
+	return listIDs(filepath.Join(m.dir, appName), setFileSuffix)
+}
+
+func (m *localSetManager) AddCase(ctx context.Context, appName, evalSetID string, evalCase *Case) error {
+	if err := validateID("eval case ID", evalCase.EvalID); err != nil {
+		return err
+	}
+	return m.update(appName, evalSetID, func(set *Set) error {
+		if set.Case(evalCase.EvalID) != nil {
+			return fmt.Errorf("eval case %q already exists in eval set %q", evalCase.EvalID, evalSetID)
+		}
+		set.EvalCases = append(set.EvalCases, evalCase)
+		return nil
+	})
+}
+
+func (m *localSetManager) UpdateCase(ctx context.Context, appName, evalSetID string, evalCase *Case) error {
+	if err := validateID("eval case ID", evalCase.EvalID); err != nil {
+		return err
+	}
+	return m.update(appName, evalSetID, func(set *Set) error {
+		i := slices.IndexFunc(set.EvalCases, func(c *Case) bool { return c.EvalID == evalCase.EvalID })
+		if i < 0 {
+			return fmt.Errorf("eval case %q of eval set %q: %w", evalCase.EvalID, evalSetID, ErrNotFound)
+		}
+		set.EvalCases[i] = evalCase
+		return nil
+	})
+}
+
+func (m *localSetManager) DeleteCase(ctx context.Context, appName, evalSetID, evalID string) error {
+	return m.update(appName, evalSetID, func(set *Set) error {
+		i := slices.IndexFunc(set.EvalCases, func(c *Case) bool { return c.EvalID == evalID })
+		if i < 0 {
+			return fmt.Errorf("eval case %q of eval set %q: %w", evalID, evalSetID, ErrNotFound)
+		}
+		set.EvalCases = slices.Delete(set.EvalCases, i, i+1)
+		return nil
+	})
+}
+
+func (m *localSetManager) update(appName, evalSetID string, fn func(*Set) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	set, err := m.get(appName, evalSetID)
+	if err != nil {
+		return err
+	}
+	if err := fn(set); err != nil {
+		return err
+	}
+	path, err := m.path(appName, evalSetID)
+	if err != nil {
+		return err
+	}
+	return writeJSON(path, set)
+}
+
+// LocalSetResultsManager returns a SetResultsManager storing the eval set
+// results in dir, at
+// <dir>/<app name>/.adk/eval_history/<eval set result ID>.evalset_result.json.
+func LocalSetResultsManager(dir string) SetResultsManager {
+	return &localSetResultsManager{dir: dir}
+}
+
+type localSetResultsManager struct {
+	dir string
+}
+
+func (m *localSetResultsManager) historyDir(appName string) (string, error) {
+	if err := validateAppName(appName); err != nil {
+		return "", err
+	}
+	return filepath.Join(m.dir, appName, ".adk", "eval_history"), nil
+}
+
+func (m *localSetResultsManager) Save(ctx context.Context, appName string, result *SetResult) error {
+	dir, err := m.historyDir(appName)
+	if err != nil {
+		return err
+	}
+	if err := validateResultID(result.EvalSetResultID); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, result.EvalSetResultID+resultFileSuffix), result)
+}
+
+func (m *localSetResultsManager) Get(ctx context.Context, appName, evalSetResultID string) (*SetResult, error) {
+	dir, err := m.historyDir(appName)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateResultID(evalSetResultID); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, evalSetResultID+resultFileSuffix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("eval set result %q of app %q: %w", evalSetResultID, appName, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The Python ADK writes the result as a JSON encoded string.
+	var encoded string
+	if json.Unmarshal(data, &encoded) == nil {
+		data = []byte(encoded)
+	}
+	result := &SetResult{}
+	if err := unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("failed to decode eval set result %q: %w", evalSetResultID, err)
+	}
+	return result, nil
+}
+
+func (m *localSetResultsManager) List(ctx context.Context, appName string) ([]string, error) {
+	dir, err := m.historyDir(appName)
+	if err != nil {
+		return nil, err
+	}
+	return listIDs(dir, resultFileSuffix)
+}
+
+// listIDs returns the sorted names of the files of dir with the suffix,
+// without the suffix.
+func listIDs(dir, suffix string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), suffix) {
+			ids = append(ids, strings.TrimSuffix(e.Name(), suffix))
+		}
+	}
+	return ids, nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func timestamp(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
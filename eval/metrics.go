@@ -0,0 +1,282 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// Evaluator scores the actual invocations of an eval case against the
+// expected ones.
+type Evaluator interface {
+	// Evaluate scores the actual invocations against the expected
+	// invocations at the same index.
+	Evaluate(ctx context.Context, actual, expected []*Invocation) (*EvaluationResult, error)
+}
+
+// EvaluationResult is the result of an Evaluator.
+type EvaluationResult struct {
+	// OverallScore is the average score of the invocations, nil if none was
+	// evaluated.
+	OverallScore  *float64
+	OverallStatus Status
+	PerInvocation []*PerInvocationResult
+}
+
+// PerInvocationResult is the score of an invocation.
+type PerInvocationResult struct {
+	Actual   *Invocation
+	Expected *Invocation
+	Score    *float64
+	Status   Status
+}
+
+// NewEvaluator returns the Evaluator of a built-in metric. The judge model
+// is only used by the LLM-as-judge metrics, which fail without it.
+func NewEvaluator(metric Metric, judge model.LLM) (Evaluator, error) {
+	switch metric.MetricName {
+	case MetricToolTrajectoryAvgScore:
+		return &scoreEvaluator{threshold: metric.Threshold, score: toolTrajectoryScore}, nil
+	case MetricResponseMatchScore:
+		return &scoreEvaluator{threshold: metric.Threshold, score: responseMatchScore}, nil
+	case MetricFinalResponseMatchV2:
+		if judge == nil {
+			return nil, fmt.Errorf("metric %q requires a judge model", metric.MetricName)
+		}
+		numSamples := 5
+		if metric.JudgeModelOptions != nil && metric.JudgeModelOptions.NumSamples > 0 {
+			numSamples = metric.JudgeModelOptions.NumSamples
+		}
+		j := &responseJudge{llm: judge, numSamples: numSamples}
+		return &scoreEvaluator{threshold: metric.Threshold, score: j.score}, nil
+	default:
+		return nil, fmt.Errorf("unknown metric %q", metric.MetricName)
+	}
+}
+
+// scoreEvaluator scores each invocation with the score func and averages
+// the scores.
+type scoreEvaluator struct {
+	threshold float64
+	score     func(ctx context.Context, actual, expected *Invocation) (float64, error)
+}
+
+func (e *scoreEvaluator) Evaluate(ctx context.Context, actual, expected []*Invocation) (*EvaluationResult, error) {
+	if len(actual) != len(expected) {
+		return nil, fmt.Errorf("got %d actual invocations, want %d", len(actual), len(expected))
+	}
+	result := &EvaluationResult{OverallStatus: StatusNotEvaluated}
+	var total float64
+	for i := range actual {
+		score, err := e.score(ctx, actual[i], expected[i])
+		if err != nil {
+			return nil, err
+		}
+		total += score
+		result.PerInvocation = append(result.PerInvocation, &PerInvocationResult{
+			Actual:   actual[i],
+			Expected: expected[i],
+			Score:    &score,
+			Status:   e.status(score),
+		})
+	}
+	if len(actual) > 0 {
+		avg := total / float64(len(actual))
+		result.OverallScore = &avg
+		result.OverallStatus = e.status(avg)
+	}
+	return result, nil
+}
+
+func (e *scoreEvaluator) status(score float64) Status {
+	if score >= e.threshold {
+		return StatusPassed
+	}
+	return StatusFailed
+}
+
+// toolTrajectoryScore is 1 if the tool calls match exactly, 0 otherwise.
+func toolTrajectoryScore(_ context.Context, actual, expected *Invocation) (float64, error) {
+	got, want := toolUses(actual), toolUses(expected)
+	if len(got) != len(want) {
+		return 0, nil
+	}
+	for i := range got {
+		if got[i].Name != want[i].Name {
+			return 0, nil
+		}
+		// Compare the JSON encodings, the numbers of the expected args were
+		// decoded as float64.
+		gotArgs, err := json.Marshal(got[i].Args)
+		if err != nil {
+			return 0, err
+		}
+		wantArgs, err := json.Marshal(want[i].Args)
+		if err != nil {
+			return 0, err
+		}
+		if string(gotArgs) != string(wantArgs) {
+			return 0, nil
+		}
+	}
+	return 1, nil
+}
+
+func toolUses(inv *Invocation) []*genai.FunctionCall {
+	if inv.IntermediateData == nil {
+		return nil
+	}
+	return inv.IntermediateData.ToolUses
+}
+
+// responseMatchScore is the ROUGE-1 F-measure of the final responses.
+func responseMatchScore(_ context.Context, actual, expected *Invocation) (float64, error) {
+	return rouge1(contentText(expected.FinalResponse), contentText(actual.FinalResponse)), nil
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// rouge1 returns the ROUGE-1 F-measure of the candidate against the
+// reference. The texts are tokenized and stemmed as by the rouge-score
+// Python package with use_stemmer=True.
+func rouge1(reference, candidate string) float64 {
+	tokenize := func(s string) []string {
+		tokens := strings.Fields(nonAlphanumeric.ReplaceAllString(strings.ToLower(s), " "))
+		for i, t := range tokens {
+			if len(t) > 3 {
+				tokens[i] = stem(t)
+			}
+		}
+		return tokens
+	}
+	ref, cand := tokenize(reference), tokenize(candidate)
+	if len(ref) == 0 || len(cand) == 0 {
+		return 0
+	}
+	counts := make(map[string]int)
+	for _, t := range ref {
+		counts[t]++
+	}
+	overlap := 0
+	for _, t := range cand {
+		if counts[t] > 0 {
+			counts[t]--
+			overlap++
+		}
+	}
+	if overlap == 0 {
+		return 0
+	}
+	precision := float64(overlap) / float64(len(cand))
+	recall := float64(overlap) / float64(len(ref))
+	return 2 * precision * recall / (precision + recall)
+}
+
+// contentText returns the text of the content, ignoring the thoughts.
+func contentText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var texts []string
+	for _, p := range c.Parts {
+		if p != nil && p.Text != "" && !p.Thought {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+const judgePrompt = `You are an expert rater for an AI agent. You are given a user prompt, the response of the agent and a reference response.
+Decide whether the agent response is valid: it must answer the user prompt and agree with the reference response on all the facts. It may be phrased differently, be more detailed, or use a different format.
+
+User prompt:
+%s
+
+Agent response:
+%s
+
+Reference response:
+%s
+
+First explain your reasoning, then end your answer with the line:
+"is_the_agent_response_valid": "valid" or "is_the_agent_response_valid": "invalid"`
+
+var judgeLabel = regexp.MustCompile(`"?is_the_agent_response_valid"?\s*:\s*"?(valid|invalid)"?`)
+
+// responseJudge asks the judge model whether the final response is valid
+// given the expected one. The majority verdict of the samples scores 1 if
+// valid, 0 otherwise.
+type responseJudge struct {
+	llm        model.LLM
+	numSamples int
+}
+
+func (j *responseJudge) score(ctx context.Context, actual, expected *Invocation) (float64, error) {
+	// reference: adk-python src/google/adk/evaluation/final_response_match_v2.py
+	prompt := fmt.Sprintf(judgePrompt,
+		contentText(expected.UserContent), contentText(actual.FinalResponse), contentText(expected.FinalResponse))
+	valid, invalid := 0, 0
+	for range j.numSamples {
+		label, err := j.sample(ctx, prompt)
+		if err != nil {
+			return 0, err
+		}
+		switch label {
+		case "valid":
+			valid++
+		case "invalid":
+			invalid++
+		}
+	}
+	if valid > invalid {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// sample returns the label of a judge model response, or "" if the
+// response has none.
+func (j *responseJudge) sample(ctx context.Context, prompt string) (string, error) {
+	req := &model.LLMRequest{
+		Model:    j.llm.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{},
+	}
+	var text string
+	for resp, err := range j.llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", fmt.Errorf("failed to call judge model: %w", err)
+		}
+		if resp.ErrorCode != "" {
+			return "", fmt.Errorf("judge model returned error %s: %s", resp.ErrorCode, resp.ErrorMessage)
+		}
+		if resp.Content != nil && !resp.Partial {
+			text += contentText(resp.Content)
+		}
+	}
+	m := judgeLabel.FindAllStringSubmatch(strings.ToLower(text), -1)
+	if len(m) == 0 {
+		return "", nil
+	}
+	return m[len(m)-1][1], nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import "strings"
+
+// stem returns the stem of the lowercase word, using the original Porter
+// stemming algorithm as the rouge_score package used by the Python ADK.
+// See https://tartarus.org/martin/PorterStemmer/def.txt.
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	w := porterWord(word)
+	w = w.step1a().step1b().step1c().step2().step3().step4().step5()
+	return string(w)
+}
+
+type porterWord []byte
+
+// consonant reports whether the i-th letter is a consonant. A y is a
+// consonant unless it follows a consonant.
+func (w porterWord) consonant(i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !w.consonant(i-1)
+	default:
+		return true
+	}
+}
+
+// measure returns the number of vowel-consonant sequences of the word.
+func (w porterWord) measure() int {
+	m, i := 0, 0
+	for i < len(w) && w.consonant(i) {
+		i++
+	}
+	for i < len(w) {
+		for i < len(w) && !w.consonant(i) {
+			i++
+		}
+		if i == len(w) {
+			break
+		}
+		for i < len(w) && w.consonant(i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+func (w porterWord) hasVowel() bool {
+	for i := range w {
+		if !w.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w porterWord) endsWithDoubleConsonant() bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && w.consonant(n-1)
+}
+
+// endsWithCVC reports whether the word ends with consonant-vowel-consonant,
+// the last consonant not being w, x or y.
+func (w porterWord) endsWithCVC() bool {
+	n := len(w)
+	if n < 3 || !w.consonant(n-3) || w.consonant(n-2) || !w.consonant(n-1) {
+		return false
+	}
+	return !strings.ContainsRune("wxy", rune(w[n-1]))
+}
+
+// cut returns the word without the suffix, and whether it had the suffix.
+func (w porterWord) cut(suffix string) (porterWord, bool) {
+	s, ok := strings.CutSuffix(string(w), suffix)
+	return porterWord(s), ok
+}
+
+func (w porterWord) step1a() porterWord {
+	switch {
+	case strings.HasSuffix(string(w), "sses"), strings.HasSuffix(string(w), "ies"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(string(w), "ss"):
+		return w
+	case strings.HasSuffix(string(w), "s"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func (w porterWord) step1b() porterWord {
+	if s, ok := w.cut("eed"); ok {
+		if s.measure() > 0 {
+			return w[:len(w)-1]
+		}
+		return w
+	}
+	s, ok := w.cut("ed")
+	if !ok {
+		s, ok = w.cut("ing")
+	}
+	if !ok || !s.hasVowel() {
+		return w
+	}
+	switch {
+	case strings.HasSuffix(string(s), "at"), strings.HasSuffix(string(s), "bl"), strings.HasSuffix(string(s), "iz"):
+		return append(s, 'e')
+	case s.endsWithDoubleConsonant() && !strings.ContainsRune("lsz", rune(s[len(s)-1])):
+		return s[:len(s)-1]
+	case s.measure() == 1 && s.endsWithCVC():
+		return append(s, 'e')
+	}
+	return s
+}
+
+func (w porterWord) step1c() porterWord {
+	if s, ok := w.cut("y"); ok && s.hasVowel() {
+		return append(s, 'i')
+	}
+	return w
+}
+
+// replaceSuffix replaces the first of the suffixes the word ends with, if
+// the remaining stem has a measure greater than minMeasure.
+func (w porterWord) replaceSuffix(minMeasure int, suffixes [][2]string) porterWord {
+	for _, r := range suffixes {
+		if s, ok := w.cut(r[0]); ok {
+			if s.measure() > minMeasure {
+				return append(s, r[1]...)
+			}
+			return w
+		}
+	}
+	return w
+}
+
+// The longer suffixes come first when a suffix ends another one.
+var step2Suffixes = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"abli", "able"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+func (w porterWord) step2() porterWord {
+	return w.replaceSuffix(0, step2Suffixes)
+}
+
+var step3Suffixes = [][2]string{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+func (w porterWord) step3() porterWord {
+	return w.replaceSuffix(0, step3Suffixes)
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+func (w porterWord) step4() porterWord {
+	for _, suffix := range step4Suffixes {
+		s, ok := w.cut(suffix)
+		if !ok {
+			continue
+		}
+		if suffix == "ion" && (len(s) == 0 || (s[len(s)-1] != 's' && s[len(s)-1] != 't')) {
+			continue
+		}
+		if s.measure() > 1 {
+			return s
+		}
+		return w
+	}
+	return w
+}
+
+func (w porterWord) step5() porterWord {
+	if s, ok := w.cut("e"); ok {
+		if m := s.measure(); m > 1 || (m == 1 && !s.endsWithCVC()) {
+			w = s
+		}
+	}
+	if w.measure() > 1 && w.endsWithDoubleConsonant() && w[len(w)-1] == 'l' {
+		w = w[:len(w)-1]
+	}
+	return w
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+// Status is the outcome of an evaluation.
+type Status int
+
+const (
+	StatusPassed       Status = 1
+	StatusFailed       Status = 2
+	StatusNotEvaluated Status = 3
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case StatusPassed:
+		return "PASSED"
+	case StatusFailed:
+		return "FAILED"
+	default:
+		return "NOT_EVALUATED"
+	}
+}
+
+// Names of the built-in metrics.
+const (
+	// MetricToolTrajectoryAvgScore checks that the agent made the expected
+	// tool calls, with the expected arguments, in the expected order.
+	MetricToolTrajectoryAvgScore = "tool_trajectory_avg_score"
+	// MetricResponseMatchScore is the ROUGE-1 F-measure of the final
+	// response against the expected one.
+	MetricResponseMatchScore = "response_match_score"
+	// MetricFinalResponseMatchV2 asks a judge model whether the final
+	// response is valid given the expected one.
+	MetricFinalResponseMatchV2 = "final_response_match_v2"
+)
+
+// Metric is a metric to evaluate the eval cases with.
+type Metric struct {
+	MetricName string `json:"metricName"`
+	// Threshold is the minimum score for the metric to pass.
+	Threshold float64 `json:"threshold"`
+	// JudgeModelOptions configure the judge model of the LLM-as-judge
+	// metrics.
+	JudgeModelOptions *JudgeModelOptions `json:"judgeModelOptions,omitempty"`
+}
+
+// JudgeModelOptions configure the judge model of the LLM-as-judge metrics.
+type JudgeModelOptions struct {
+	// NumSamples is the number of times the judge model is asked per
+	// invocation, the majority verdict wins. Defaults to 5.
+	NumSamples int `json:"numSamples,omitempty"`
+}
+
+// DefaultMetrics are the metrics used when none are given: an exact tool
+// trajectory match and a 0.8 response match.
+func DefaultMetrics() []Metric {
+	return []Metric{
+		{MetricName: MetricToolTrajectoryAvgScore, Threshold: 1},
+		{MetricName: MetricResponseMatchScore, Threshold: 0.8},
+	}
+}
+
+// MetricResult is the result of a metric.
+type MetricResult struct {
+	MetricName string  `json:"metricName"`
+	Threshold  float64 `json:"threshold"`
+	// Score is nil when the metric was not evaluated.
+	Score      *float64 `json:"score,omitempty"`
+	EvalStatus Status   `json:"evalStatus"`
+}
+
+// MetricResultPerInvocation holds the metric results of an invocation.
+type MetricResultPerInvocation struct {
+	ActualInvocation   *Invocation     `json:"actualInvocation"`
+	ExpectedInvocation *Invocation     `json:"expectedInvocation"`
+	EvalMetricResults  []*MetricResult `json:"evalMetricResults"`
+}
+
+// CaseResult is the result of an eval case.
+type CaseResult struct {
+	EvalSetID       string `json:"evalSetId"`
+	EvalID          string `json:"evalId"`
+	FinalEvalStatus Status `json:"finalEvalStatus"`
+	// OverallEvalMetricResults are the metric results across all the
+	// invocations of the case.
+	OverallEvalMetricResults      []*MetricResult              `json:"overallEvalMetricResults"`
+	EvalMetricResultPerInvocation []*MetricResultPerInvocation `json:"evalMetricResultPerInvocation"`
+	// SessionID is the ID of the session the case ran in.
+	SessionID string `json:"sessionId"`
+	UserID    string `json:"userId,omitempty"`
+}
+
+// SetResult is the result of a run of an eval set.
+type SetResult struct {
+	EvalSetResultID   string        `json:"evalSetResultId"`
+	EvalSetResultName string        `json:"evalSetResultName,omitempty"`
+	EvalSetID         string        `json:"evalSetId"`
+	EvalCaseResults   []*CaseResult `json:"evalCaseResults"`
+	// CreationTimestamp is in seconds since the Unix epoch.
+	CreationTimestamp float64 `json:"creationTimestamp"`
+}
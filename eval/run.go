@@ -0,0 +1,285 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// RunConfig configures Run.
+type RunConfig struct {
+	AppName string
+	// Agent is the root agent of the app.
+	Agent agent.Agent
+	Set   *Set
+	// EvalIDs restricts the run to the eval cases with these IDs. All the
+	// cases run if empty.
+	EvalIDs []string
+	// Metrics default to DefaultMetrics.
+	Metrics []Metric
+	// JudgeModel is used by the LLM-as-judge metrics.
+	JudgeModel model.LLM
+
+	// SessionService stores the sessions the cases run in. Defaults to an
+	// in-memory service.
+	SessionService session.Service
+	// ArtifactService defaults to an in-memory service.
+	ArtifactService artifact.Service
+}
+
+// defaultUserID is the ID of the user of the cases without session input.
+const defaultUserID = "test_user_id"
+
+// Run replays the eval cases through the agent and scores them with the
+// metrics. Each case runs in a new session, where the user contents of its
+// conversation are sent one by one.
+func Run(ctx context.Context, cfg RunConfig) (*SetResult, error) {
+	// reference: adk-python src/google/adk/evaluation/local_eval_service.py
+	if cfg.Set == nil {
+		return nil, fmt.Errorf("eval set is required")
+	}
+	metrics := cfg.Metrics
+	if len(metrics) == 0 {
+		metrics = DefaultMetrics()
+	}
+	evaluators := make([]Evaluator, len(metrics))
+	for i, m := range metrics {
+		ev, err := NewEvaluator(m, cfg.JudgeModel)
+		if err != nil {
+			return nil, err
+		}
+		evaluators[i] = ev
+	}
+
+	cases := cfg.Set.EvalCases
+	if len(cfg.EvalIDs) > 0 {
+		cases = nil
+		for _, id := range cfg.EvalIDs {
+			c := cfg.Set.Case(id)
+			if c == nil {
+				return nil, fmt.Errorf("eval case %q of eval set %q: %w", id, cfg.Set.EvalSetID, ErrNotFound)
+			}
+			cases = append(cases, c)
+		}
+	}
+
+	sessionService := cfg.SessionService
+	if sessionService == nil {
+		sessionService = session.InMemoryService()
+	}
+	artifactService := cfg.ArtifactService
+	if artifactService == nil {
+		artifactService = artifact.InMemoryService()
+	}
+	r, err := runner.New(runner.Config{
+		AppName:         cfg.AppName,
+		Agent:           cfg.Agent,
+		SessionService:  sessionService,
+		ArtifactService: artifactService,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %w", err)
+	}
+
+	now := time.Now()
+	result := &SetResult{
+		EvalSetResultID:   resultID(cfg.AppName, cfg.Set.EvalSetID, now),
+		EvalSetID:         cfg.Set.EvalSetID,
+		EvalCaseResults:   []*CaseResult{},
+		CreationTimestamp: timestamp(now),
+	}
+	result.EvalSetResultName = result.EvalSetResultID
+	for _, c := range cases {
+		actual, userID, sessionID, err := replay(ctx, r, sessionService, cfg.AppName, c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run eval case %q: %w", c.EvalID, err)
+		}
+		caseResult, err := evaluate(ctx, metrics, evaluators, actual, c.Conversation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate eval case %q: %w", c.EvalID, err)
+		}
+		caseResult.EvalSetID = cfg.Set.EvalSetID
+		caseResult.EvalID = c.EvalID
+		caseResult.SessionID = sessionID
+		caseResult.UserID = userID
+		result.EvalCaseResults = append(result.EvalCaseResults, caseResult)
+	}
+	return result, nil
+}
+
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func resultID(appName, evalSetID string, t time.Time) string {
+	return invalidIDChars.ReplaceAllString(fmt.Sprintf("%s_%s_%d", appName, evalSetID, t.UnixMicro()), "_")
+}
+
+// replay runs the user contents of the case in a new session and returns
+// the actual invocations.
+func replay(ctx context.Context, r *runner.Runner, sessionService session.Service, appName string, c *Case) (invocations []*Invocation, userID, sessionID string, err error) {
+	userID = defaultUserID
+	var state map[string]any
+	if c.SessionInput != nil {
+		if c.SessionInput.UserID != "" {
+			userID = c.SessionInput.UserID
+		}
+		state = c.SessionInput.State
+	}
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: "___eval___session___" + uuid.NewString(),
+		State:     state,
+	})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to create session: %w", err)
+	}
+	sessionID = resp.Session.ID()
+
+	for _, expected := range c.Conversation {
+		var events []*session.Event
+		for ev, err := range r.Run(ctx, userID, sessionID, expected.UserContent, agent.RunConfig{}) {
+			if err != nil {
+				return nil, "", "", err
+			}
+			events = append(events, ev)
+		}
+		inv := invocation(events)
+		inv.UserContent = expected.UserContent
+		invocations = append(invocations, inv)
+	}
+	return invocations, userID, sessionID, nil
+}
+
+// evaluate scores the actual invocations with each metric.
+func evaluate(ctx context.Context, metrics []Metric, evaluators []Evaluator, actual, expected []*Invocation) (*CaseResult, error) {
+	result := &CaseResult{
+		FinalEvalStatus:          StatusNotEvaluated,
+		OverallEvalMetricResults: []*MetricResult{},
+	}
+	for i := range actual {
+		result.EvalMetricResultPerInvocation = append(result.EvalMetricResultPerInvocation, &MetricResultPerInvocation{
+			ActualInvocation:   actual[i],
+			ExpectedInvocation: expected[i],
+			EvalMetricResults:  []*MetricResult{},
+		})
+	}
+	for i, ev := range evaluators {
+		res, err := ev.Evaluate(ctx, actual, expected)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q: %w", metrics[i].MetricName, err)
+		}
+		result.OverallEvalMetricResults = append(result.OverallEvalMetricResults, &MetricResult{
+			MetricName: metrics[i].MetricName,
+			Threshold:  metrics[i].Threshold,
+			Score:      res.OverallScore,
+			EvalStatus: res.OverallStatus,
+		})
+		for j, inv := range res.PerInvocation {
+			perInv := result.EvalMetricResultPerInvocation[j]
+			perInv.EvalMetricResults = append(perInv.EvalMetricResults, &MetricResult{
+				MetricName: metrics[i].MetricName,
+				Threshold:  metrics[i].Threshold,
+				Score:      inv.Score,
+				EvalStatus: inv.Status,
+			})
+		}
+
+		switch {
+		case res.OverallStatus == StatusFailed:
+			result.FinalEvalStatus = StatusFailed
+		case res.OverallStatus == StatusPassed && result.FinalEvalStatus == StatusNotEvaluated:
+			result.FinalEvalStatus = StatusPassed
+		}
+	}
+	return result, nil
+}
+
+// InvocationsFromEvents converts the events of a session to invocations,
+// e.g. to create an eval case from a session.
+func InvocationsFromEvents(events []*session.Event) []*Invocation {
+	var invocations []*Invocation
+	byID := make(map[string]int)
+	var grouped [][]*session.Event
+	for _, ev := range events {
+		i, ok := byID[ev.InvocationID]
+		if !ok {
+			i = len(grouped)
+			byID[ev.InvocationID] = i
+			grouped = append(grouped, nil)
+		}
+		grouped[i] = append(grouped[i], ev)
+	}
+	for _, evs := range grouped {
+		inv := invocation(evs)
+		if inv.UserContent == nil {
+			continue
+		}
+		invocations = append(invocations, inv)
+	}
+	return invocations
+}
+
+// invocation converts the events of an invocation.
+func invocation(events []*session.Event) *Invocation {
+	inv := &Invocation{IntermediateData: &IntermediateData{}}
+	for _, ev := range events {
+		if inv.InvocationID == "" {
+			inv.InvocationID = ev.InvocationID
+			inv.CreationTimestamp = timestamp(ev.Timestamp)
+		}
+		if ev.Partial || ev.Content == nil {
+			continue
+		}
+		if ev.Author == "user" {
+			if inv.UserContent == nil {
+				inv.UserContent = ev.Content
+			}
+			continue
+		}
+		if ev.IsFinalResponse() {
+			inv.FinalResponse = ev.Content
+			continue
+		}
+		var texts []*genai.Part
+		for _, p := range ev.Content.Parts {
+			switch {
+			case p == nil:
+			case p.FunctionCall != nil:
+				inv.IntermediateData.ToolUses = append(inv.IntermediateData.ToolUses, p.FunctionCall)
+			case p.FunctionResponse != nil:
+				inv.IntermediateData.ToolResponses = append(inv.IntermediateData.ToolResponses, p.FunctionResponse)
+			case p.Text != "" && !p.Thought:
+				texts = append(texts, p)
+			}
+		}
+		if len(texts) > 0 {
+			inv.IntermediateData.IntermediateResponses = append(inv.IntermediateData.IntermediateResponses,
+				&IntermediateResponse{Author: ev.Author, Parts: texts})
+		}
+	}
+	return inv
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package budget enforces the token and cost budget of the invocations.
+package budget
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+)
+
+// Tracker accounts the tokens and the cost of the model calls of an
+// invocation against its budget. It is shared by the agents of the
+// invocation.
+type Tracker struct {
+	budget agent.Budget
+
+	mu     sync.Mutex
+	tokens int64
+	cost   float64
+}
+
+// New creates a Tracker of the budget.
+func New(budget agent.Budget) *Tracker {
+	return &Tracker{budget: budget}
+}
+
+// ExceededError describes an exceeded budget.
+type ExceededError struct {
+	// Tokens and Cost are the usage, including the request about to be sent
+	// to the model when checked before a call.
+	Tokens    int64
+	MaxTokens int64
+	Cost      float64
+	MaxCost   float64
+}
+
+func (e *ExceededError) Error() string {
+	if e.MaxTokens > 0 && e.Tokens > e.MaxTokens {
+		return fmt.Sprintf("token budget exceeded: %d tokens of %d", e.Tokens, e.MaxTokens)
+	}
+	return fmt.Sprintf("cost budget exceeded: %.4f of %.4f", e.Cost, e.MaxCost)
+}
+
+// CountTokens reports whether the prompt tokens of the requests are counted
+// by the models instead of estimated.
+func (t *Tracker) CountTokens() bool {
+	return t.budget.CountTokens
+}
+
+// Check returns an error if sending a request of promptTokens tokens to the
+// model would exceed the budget.
+func (t *Tracker) Check(modelName string, promptTokens int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exceeded(t.tokens+promptTokens, t.cost+t.price(modelName).Input*float64(promptTokens)/1e6)
+}
+
+// Record accounts the usage of a model response.
+func (t *Tracker) Record(modelName string, usage *genai.GenerateContentResponseUsageMetadata) {
+	if usage == nil {
+		return
+	}
+	input := int64(usage.PromptTokenCount) + int64(usage.ToolUsePromptTokenCount)
+	output := int64(usage.CandidatesTokenCount) + int64(usage.ThoughtsTokenCount)
+	price := t.price(modelName)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens += max(int64(usage.TotalTokenCount), input+output)
+	t.cost += (price.Input*float64(input) + price.Output*float64(output)) / 1e6
+}
+
+// Exceeded returns an error if the recorded usage exceeds the budget.
+func (t *Tracker) Exceeded() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exceeded(t.tokens, t.cost)
+}
+
+func (t *Tracker) exceeded(tokens int64, cost float64) error {
+	if (t.budget.MaxTokens > 0 && tokens > t.budget.MaxTokens) || (t.budget.MaxCost > 0 && cost > t.budget.MaxCost) {
+		return &ExceededError{Tokens: tokens, MaxTokens: t.budget.MaxTokens, Cost: cost, MaxCost: t.budget.MaxCost}
+	}
+	return nil
+}
+
+func (t *Tracker) price(modelName string) agent.TokenPrice {
+	return t.budget.Prices[modelName]
+}
+
+func ToContext(ctx context.Context, t *Tracker) context.Context {
+	return context.WithValue(ctx, trackerCtxKey, t)
+}
+
+func FromContext(ctx context.Context) *Tracker {
+	t, ok := ctx.Value(trackerCtxKey).(*Tracker)
+	if !ok {
+		return nil
+	}
+	return t
+}
+
+type ctxKey int
+
+const trackerCtxKey ctxKey = 0
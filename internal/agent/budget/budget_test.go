@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package budget
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+)
+
+func TestTracker(t *testing.T) {
+	prices := map[string]agent.TokenPrice{"pro": {Input: 1, Output: 10}}
+	usage := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     300_000,
+		CandidatesTokenCount: 50_000,
+		ThoughtsTokenCount:   50_000,
+		TotalTokenCount:      400_000,
+	}
+	tests := []struct {
+		name         string
+		budget       agent.Budget
+		model        string
+		promptTokens int64
+		wantCheck    bool
+		wantExceeded bool
+	}{
+		{
+			name:         "no limits",
+			budget:       agent.Budget{},
+			model:        "pro",
+			promptTokens: 1_000_000,
+		},
+		{
+			name:         "within token budget",
+			budget:       agent.Budget{MaxTokens: 1_000_000},
+			model:        "pro",
+			promptTokens: 600_000,
+		},
+		{
+			name:         "request exceeds token budget",
+			budget:       agent.Budget{MaxTokens: 1_000_000},
+			model:        "pro",
+			promptTokens: 600_001,
+			wantCheck:    true,
+		},
+		{
+			name:         "response exceeds token budget",
+			budget:       agent.Budget{MaxTokens: 300_000},
+			model:        "pro",
+			wantCheck:    true,
+			wantExceeded: true,
+		},
+		{
+			// 0.3 for the input, 1 for the output.
+			name:         "within cost budget",
+			budget:       agent.Budget{MaxCost: 1.5, Prices: prices},
+			model:        "pro",
+			promptTokens: 200_000,
+		},
+		{
+			name:         "request exceeds cost budget",
+			budget:       agent.Budget{MaxCost: 1.5, Prices: prices},
+			model:        "pro",
+			promptTokens: 200_001,
+			wantCheck:    true,
+		},
+		{
+			name:         "response exceeds cost budget",
+			budget:       agent.Budget{MaxCost: 1, Prices: prices},
+			model:        "pro",
+			wantCheck:    true,
+			wantExceeded: true,
+		},
+		{
+			name:         "free model",
+			budget:       agent.Budget{MaxCost: 1, Prices: prices},
+			model:        "flash",
+			promptTokens: 1_000_000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := New(tt.budget)
+			if err := tracker.Check(tt.model, 0); err != nil {
+				t.Fatalf("Check() before the first call failed: %v", err)
+			}
+			tracker.Record(tt.model, usage)
+			tracker.Record(tt.model, nil)
+
+			var exceeded *ExceededError
+			if err := tracker.Check(tt.model, tt.promptTokens); errors.As(err, &exceeded) != tt.wantCheck {
+				t.Errorf("Check(%d) = %v, want exceeded %v", tt.promptTokens, err, tt.wantCheck)
+			}
+			if err := tracker.Exceeded(); errors.As(err, &exceeded) != tt.wantExceeded {
+				t.Errorf("Exceeded() = %v, want exceeded %v", err, tt.wantExceeded)
+			}
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("FromContext() = %v, want nil", got)
+	}
+	tracker := New(agent.Budget{MaxTokens: 1})
+	if got := FromContext(ToContext(context.Background(), tracker)); got != tracker {
+		t.Errorf("FromContext() = %v, want %v", got, tracker)
+	}
+}
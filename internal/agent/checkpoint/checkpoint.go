@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkpoint tracks the agent state checkpoints of the resumable
+// invocations.
+package checkpoint
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// Checkpoints holds the last agent states recorded by an invocation.
+//
+// The agents consult the checkpoints when they start, to resume where they
+// stopped. Checkpoints is safe for concurrent use.
+type Checkpoints struct {
+	mu     sync.Mutex
+	states map[string]map[string]any
+	ended  map[string]bool
+}
+
+// New returns empty checkpoints, for a new invocation.
+func New() *Checkpoints {
+	return &Checkpoints{
+		states: make(map[string]map[string]any),
+		ended:  make(map[string]bool),
+	}
+}
+
+// FromEvents restores the checkpoints recorded by the invocation in the
+// events. The root is the agent tree the checkpoints' authors belong to.
+func FromEvents(root agent.Agent, invocationID string, events session.Events) *Checkpoints {
+	c := New()
+	for event := range events.All() {
+		if event.InvocationID != invocationID || (event.Actions.AgentState == nil && !event.Actions.EndOfAgent) {
+			continue
+		}
+		author := findAgent(root, event.Author)
+		if author == nil {
+			continue
+		}
+		c.record(author, event)
+	}
+	return c
+}
+
+// State returns the last state recorded by the agent, or nil if the agent
+// has no checkpoint.
+func (c *Checkpoints) State(agentName string) map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.states[agentName]
+}
+
+// Ended reports whether the agent has finished its run.
+func (c *Checkpoints) Ended(agentName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ended[agentName]
+}
+
+// Checkpoint records the state of the agent of the context and returns the
+// event persisting it.
+//
+// The checkpoints of the sub-agents are cleared, as a new state of the agent
+// means its sub-agents start over.
+func (c *Checkpoints) Checkpoint(ctx agent.InvocationContext, state map[string]any) *session.Event {
+	event := newEvent(ctx)
+	event.Actions.AgentState = state
+	c.record(ctx.Agent(), event)
+	return event
+}
+
+// End records the end of the run of the agent of the context and returns
+// the event persisting it.
+func (c *Checkpoints) End(ctx agent.InvocationContext) *session.Event {
+	event := newEvent(ctx)
+	event.Actions.EndOfAgent = true
+	c.record(ctx.Agent(), event)
+	return event
+}
+
+func (c *Checkpoints) record(a agent.Agent, event *session.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if event.Actions.EndOfAgent {
+		c.ended[a.Name()] = true
+		delete(c.states, a.Name())
+		return
+	}
+	c.states[a.Name()] = event.Actions.AgentState
+	c.ended[a.Name()] = false
+	c.clearSubAgents(a)
+}
+
+func (c *Checkpoints) clearSubAgents(a agent.Agent) {
+	for _, subAgent := range a.SubAgents() {
+		delete(c.states, subAgent.Name())
+		delete(c.ended, subAgent.Name())
+		c.clearSubAgents(subAgent)
+	}
+}
+
+func newEvent(ctx agent.InvocationContext) *session.Event {
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = ctx.Agent().Name()
+	event.Branch = ctx.Branch()
+	return event
+}
+
+func findAgent(cur agent.Agent, name string) agent.Agent {
+	if cur.Name() == name {
+		return cur
+	}
+	for _, subAgent := range cur.SubAgents() {
+		if a := findAgent(subAgent, name); a != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// ToContext returns a context carrying the checkpoints, enabling the
+// resumability of the invocation.
+func ToContext(ctx context.Context, c *Checkpoints) context.Context {
+	return context.WithValue(ctx, checkpointsCtxKey, c)
+}
+
+// FromContext returns the checkpoints of the invocation, or nil if the
+// invocation is not resumable.
+func FromContext(ctx context.Context) *Checkpoints {
+	c, ok := ctx.Value(checkpointsCtxKey).(*Checkpoints)
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+type ctxKey int
+
+const checkpointsCtxKey ctxKey = 0
@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/checkpoint"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/session"
+)
+
+func TestFromEvents(t *testing.T) {
+	child := utils.Must(agent.New(agent.Config{Name: "child"}))
+	parent := utils.Must(agent.New(agent.Config{Name: "parent", SubAgents: []agent.Agent{child}}))
+	root := utils.Must(agent.New(agent.Config{Name: "root", SubAgents: []agent.Agent{parent}}))
+
+	checkpointEvent := func(invocationID, author string, state map[string]any) *session.Event {
+		ev := session.NewEvent(invocationID)
+		ev.Author = author
+		ev.Actions.AgentState = state
+		return ev
+	}
+	endEvent := func(invocationID, author string) *session.Event {
+		ev := session.NewEvent(invocationID)
+		ev.Author = author
+		ev.Actions.EndOfAgent = true
+		return ev
+	}
+
+	type result struct {
+		States map[string]map[string]any
+		Ended  map[string]bool
+	}
+	tests := []struct {
+		name   string
+		events []*session.Event
+		want   result
+	}{
+		{
+			name: "last checkpoints",
+			events: []*session.Event{
+				checkpointEvent("inv1", "root", map[string]any{"step": 1}),
+				checkpointEvent("inv1", "parent", map[string]any{"step": 1}),
+				checkpointEvent("inv1", "parent", map[string]any{"step": 2}),
+			},
+			want: result{
+				States: map[string]map[string]any{"root": {"step": 1}, "parent": {"step": 2}},
+				Ended:  map[string]bool{},
+			},
+		},
+		{
+			name: "ended agent",
+			events: []*session.Event{
+				checkpointEvent("inv1", "root", map[string]any{"step": 1}),
+				checkpointEvent("inv1", "parent", map[string]any{"step": 1}),
+				endEvent("inv1", "parent"),
+			},
+			want: result{
+				States: map[string]map[string]any{"root": {"step": 1}},
+				Ended:  map[string]bool{"parent": true},
+			},
+		},
+		{
+			name: "new parent checkpoint clears the sub-agents",
+			events: []*session.Event{
+				checkpointEvent("inv1", "root", map[string]any{"step": 1}),
+				checkpointEvent("inv1", "parent", map[string]any{"step": 1}),
+				endEvent("inv1", "child"),
+				endEvent("inv1", "parent"),
+				checkpointEvent("inv1", "root", map[string]any{"step": 2}),
+			},
+			want: result{
+				States: map[string]map[string]any{"root": {"step": 2}},
+				Ended:  map[string]bool{},
+			},
+		},
+		{
+			name: "other invocations and unknown agents are ignored",
+			events: []*session.Event{
+				checkpointEvent("inv0", "root", map[string]any{"step": 3}),
+				checkpointEvent("inv1", "unknown", map[string]any{"step": 1}),
+				endEvent("inv0", "parent"),
+			},
+			want: result{
+				States: map[string]map[string]any{},
+				Ended:  map[string]bool{},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := checkpoint.FromEvents(root, "inv1", events(tc.events))
+			got := result{States: map[string]map[string]any{}, Ended: map[string]bool{}}
+			for _, name := range []string{"root", "parent", "child", "unknown"} {
+				if state := c.State(name); state != nil {
+					got.States[name] = state
+				}
+				if c.Ended(name) {
+					got.Ended[name] = true
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("FromEvents() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type events []*session.Event
+
+func (e events) All() iter.Seq[*session.Event] {
+	return slices.Values(e)
+}
+
+func (e events) Len() int {
+	return len(e)
+}
+
+func (e events) At(i int) *session.Event {
+	return e[i]
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package limits enforces the limits of model calls and tool iterations of
+// the invocations.
+package limits
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Counter counts the model calls and the tool iterations of an invocation.
+// It is shared by the agents of the invocation, including the agents run as
+// tools.
+type Counter struct {
+	maxLLMCalls       int64
+	maxToolIterations int64
+
+	llmCalls       atomic.Int64
+	toolIterations atomic.Int64
+}
+
+// New creates a Counter enforcing the limits. Zero or negative limits are
+// not enforced.
+func New(maxLLMCalls, maxToolIterations int) *Counter {
+	return &Counter{maxLLMCalls: int64(maxLLMCalls), maxToolIterations: int64(maxToolIterations)}
+}
+
+// AddLLMCall counts a model call. It returns an error if the call exceeds
+// the limit.
+func (c *Counter) AddLLMCall() error {
+	if n := c.llmCalls.Add(1); c.maxLLMCalls > 0 && n > c.maxLLMCalls {
+		return fmt.Errorf("the invocation exceeded the limit of %d model calls", c.maxLLMCalls)
+	}
+	return nil
+}
+
+// AddToolIteration counts a model response calling tools. It returns an
+// error if the iteration exceeds the limit.
+func (c *Counter) AddToolIteration() error {
+	if n := c.toolIterations.Add(1); c.maxToolIterations > 0 && n > c.maxToolIterations {
+		return fmt.Errorf("the invocation exceeded the limit of %d tool iterations", c.maxToolIterations)
+	}
+	return nil
+}
+
+func ToContext(ctx context.Context, c *Counter) context.Context {
+	return context.WithValue(ctx, counterCtxKey, c)
+}
+
+func FromContext(ctx context.Context) *Counter {
+	c, ok := ctx.Value(counterCtxKey).(*Counter)
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+type ctxKey int
+
+const counterCtxKey ctxKey = 0
@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limits
+
+import "testing"
+
+func TestCounter(t *testing.T) {
+	tests := []struct {
+		name              string
+		maxLLMCalls       int
+		maxToolIterations int
+		wantLLMCalls      int
+		wantIterations    int
+	}{
+		{"limits", 3, 2, 3, 2},
+		{"no limits", -1, 0, 10, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.maxLLMCalls, tt.maxToolIterations)
+			gotLLMCalls, gotIterations := 0, 0
+			for range 10 {
+				if c.AddLLMCall() == nil {
+					gotLLMCalls++
+				}
+				if c.AddToolIteration() == nil {
+					gotIterations++
+				}
+			}
+			if gotLLMCalls != tt.wantLLMCalls || gotIterations != tt.wantIterations {
+				t.Errorf("allowed %d model calls and %d tool iterations, want %d and %d", gotLLMCalls, gotIterations, tt.wantLLMCalls, tt.wantIterations)
+			}
+		})
+	}
+}
@@ -14,7 +14,12 @@
 
 package runconfig
 
-import "context"
+import (
+	"context"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
 
 type StreamingMode string
 
@@ -26,6 +31,14 @@ const (
 
 type RunConfig struct {
 	StreamingMode StreamingMode
+	// LiveRequestQueue holds the user input of the live runs.
+	LiveRequestQueue *agent.LiveRequestQueue
+	// ContextCacheConfig enables the context caching of the LLM requests.
+	ContextCacheConfig *model.ContextCacheConfig
+	// RetryPolicy and RateLimiter apply to the model calls of the agents
+	// not configuring their own.
+	RetryPolicy *model.RetryPolicy
+	RateLimiter *model.RateLimiter
 }
 
 func ToContext(ctx context.Context, cfg *RunConfig) context.Context {
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/fs"
 	"slices"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -56,6 +57,15 @@ func TestArtifactService(t *testing.T, name string, factory func(t *testing.T) (
 		}
 		testArtifactService_UserScoped(ctx, t, srv, name)
 	})
+	t.Run(fmt.Sprintf("Test%sArtifactService_ConcurrentSave", name), func(t *testing.T) {
+		ctx := t.Context()
+		// Create the service using the factory for this sub-test
+		srv, err := factory(t)
+		if err != nil {
+			t.Fatalf("Failed to set up service: %v", err)
+		}
+		testArtifactService_ConcurrentSave(ctx, t, srv, name)
+	})
 }
 
 func testArtifactService(ctx context.Context, t *testing.T, srv artifact.Service, testSuffix string) {
@@ -409,3 +419,72 @@ func testArtifactService_Empty(ctx context.Context, t *testing.T, srv artifact.S
 		}
 	})
 }
+
+func testArtifactService_ConcurrentSave(ctx context.Context, t *testing.T, srv artifact.Service, testSuffix string) {
+	appName := "testapp"
+	userID := "testuser"
+	sessionID := "testsession"
+
+	for _, fileName := range []string{"file1", "user:file1"} {
+		t.Run(fmt.Sprintf("%s_%s", fileName, testSuffix), func(t *testing.T) {
+			const writers = 10
+
+			// Each writer saves its own content, so the version it got can be checked.
+			parts := make(map[int64]*genai.Part)
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			// Start the writers together, to maximize the contention.
+			start := make(chan struct{})
+			for i := range writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					part := genai.NewPartFromBytes(fmt.Appendf(nil, "writer %d", i), "text/plain")
+					got, err := srv.Save(ctx, &artifact.SaveRequest{
+						AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName,
+						Part: part,
+					})
+					if err != nil {
+						t.Errorf("[%d] Save() failed: %v", i, err)
+						return
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					if _, ok := parts[got.Version]; ok {
+						t.Errorf("[%d] Save() = %v, version already returned to another writer", i, got.Version)
+					}
+					parts[got.Version] = part
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			resp, err := srv.Versions(ctx, &artifact.VersionsRequest{
+				AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName,
+			})
+			if err != nil {
+				t.Fatalf("Versions() failed: %v", err)
+			}
+			got := resp.Versions
+			slices.Sort(got)
+			var want []int64
+			for v := range int64(writers) {
+				want = append(want, v+1)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Versions(%q) mismatch (-want +got):\n%s", fileName, diff)
+			}
+
+			for version, want := range parts {
+				got, err := srv.Load(ctx, &artifact.LoadRequest{
+					AppName: appName, UserID: userID, SessionID: sessionID, FileName: fileName,
+					Version: version,
+				})
+				if err != nil || !cmp.Equal(got.Part, want) {
+					t.Errorf("Load(%v) = (%v, %v), want (%v, nil)", version, got, err, want)
+				}
+			}
+		})
+	}
+}
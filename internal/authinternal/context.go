@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authinternal threads the auth state of an invocation through the
+// context.
+package authinternal
+
+import (
+	"context"
+
+	"google.golang.org/adk/auth"
+)
+
+// ToContext returns a context carrying the credential service of the runner.
+func ToContext(ctx context.Context, svc auth.CredentialService) context.Context {
+	return context.WithValue(ctx, credentialServiceCtxKey, svc)
+}
+
+// FromContext returns the credential service of the runner, or nil if none
+// was configured.
+func FromContext(ctx context.Context) auth.CredentialService {
+	svc, ok := ctx.Value(credentialServiceCtxKey).(auth.CredentialService)
+	if !ok {
+		return nil
+	}
+	return svc
+}
+
+// WithAuthResponses returns a context carrying the credentials posted back
+// by the client, keyed by auth.AuthConfig.Key.
+func WithAuthResponses(ctx context.Context, responses map[string]*auth.AuthCredential) context.Context {
+	return context.WithValue(ctx, authResponsesCtxKey, responses)
+}
+
+// AuthResponse returns the credential posted back by the client for the
+// config, or nil if there is none.
+func AuthResponse(ctx context.Context, cfg *auth.AuthConfig) *auth.AuthCredential {
+	responses, ok := ctx.Value(authResponsesCtxKey).(map[string]*auth.AuthCredential)
+	if !ok || cfg == nil {
+		return nil
+	}
+	return responses[cfg.Key()]
+}
+
+type ctxKey int
+
+const (
+	credentialServiceCtxKey ctxKey = iota
+	authResponsesCtxKey
+)
@@ -18,7 +18,9 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/codeexecutor"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
 	"google.golang.org/adk/tool"
 )
 
@@ -49,6 +51,9 @@ type State struct {
 	OutputSchema *genai.Schema
 
 	OutputKey string
+
+	CodeExecutor codeexecutor.CodeExecutor
+	Planner      planner.Planner
 }
 
 type InstructionProvider func(ctx agent.ReadonlyContext) (string, error)
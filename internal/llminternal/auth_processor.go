@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/authinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// authPreprocessor resumes the tool calls which requested end user
+// credentials, once the client posted back the adk_request_credential
+// function responses. The resumed tools read the credentials with
+// tool.Context.AuthResponse.
+func authPreprocessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
+	// reference: adk-python src/google/adk/auth/auth_preprocessor.py
+	return func(yield func(*session.Event, error) bool) {
+		if asLLMAgent(ctx.Agent()) == nil || ctx.Session() == nil {
+			return
+		}
+		events := slices.Collect(ctx.Session().Events().All())
+
+		// The client's responses are in the last event with content, which
+		// must be authored by the user: the tools are resumed once, not at
+		// every later step of the invocation.
+		lastUserEventIndex := -1
+		for k := len(events) - 1; k >= 0; k-- {
+			if events[k].Content == nil {
+				continue
+			}
+			if events[k].Author == "user" {
+				lastUserEventIndex = k
+			}
+			break
+		}
+		if lastUserEventIndex < 0 {
+			return
+		}
+
+		authResponses := make(map[string]*auth.AuthCredential)
+		requestCredentialCallIDs := make(map[string]bool)
+		for _, funcResp := range utils.FunctionResponses(events[lastUserEventIndex].Content) {
+			if funcResp.Name != auth.FunctionCallName {
+				continue
+			}
+			authConfig, err := parseAuthResponse(funcResp.Response)
+			if err != nil {
+				yield(nil, fmt.Errorf("error failed parsing auth response for event id %q: %w", events[lastUserEventIndex].ID, err))
+				return
+			}
+			cred, err := exchangeAuthResponse(ctx, authConfig)
+			if err != nil {
+				yield(nil, fmt.Errorf("error failed exchanging auth response for event id %q: %w", events[lastUserEventIndex].ID, err))
+				return
+			}
+			authResponses[authConfig.Key()] = cred
+			requestCredentialCallIDs[funcResp.ID] = true
+		}
+		if len(requestCredentialCallIDs) == 0 {
+			return
+		}
+
+		// Find the adk_request_credential function calls to get the IDs of the
+		// original function calls.
+		toolsToResume := make(map[string]bool)
+		requestEventIndex := -1
+		for k := lastUserEventIndex - 1; k >= 0 && len(toolsToResume) == 0; k-- {
+			for _, call := range utils.FunctionCalls(events[k].Content) {
+				if !requestCredentialCallIDs[call.ID] {
+					continue
+				}
+				if id, ok := call.Args["functionCallId"].(string); ok && id != "" {
+					toolsToResume[id] = true
+					requestEventIndex = k
+				}
+			}
+		}
+		// Skip the tools which were already resumed.
+		for _, ev := range events[lastUserEventIndex+1:] {
+			for _, resp := range utils.FunctionResponses(ev.Content) {
+				delete(toolsToResume, resp.ID)
+			}
+		}
+		if len(toolsToResume) == 0 {
+			return
+		}
+
+		// Find the original function calls and run them again.
+		for k := requestEventIndex - 1; k >= 0; k-- {
+			var parts []*genai.Part
+			for _, call := range utils.FunctionCalls(events[k].Content) {
+				if toolsToResume[call.ID] {
+					parts = append(parts, &genai.Part{FunctionCall: call})
+				}
+			}
+			if len(parts) == 0 {
+				continue
+			}
+
+			toolsmap := make(map[string]tool.Tool, len(f.Tools))
+			for _, t := range f.Tools {
+				toolsmap[t.Name()] = t
+			}
+			resumeCtx := ctx.WithContext(authinternal.WithAuthResponses(ctx, authResponses))
+			ev, err := f.handleFunctionCalls(resumeCtx, toolsmap, &model.LLMResponse{
+				Content: &genai.Content{Parts: parts, Role: genai.RoleUser},
+			}, nil)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if ev == nil {
+				return
+			}
+			// The resumed tools may request another credential.
+			authEvent, err := generateAuthEvent(ctx, ev)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if authEvent != nil {
+				if !yield(authEvent, nil) {
+					return
+				}
+			}
+			yield(ev, nil)
+			return
+		}
+	}
+}
+
+// parseAuthResponse decodes the auth config posted back by the client.
+func parseAuthResponse(response map[string]any) (*auth.AuthConfig, error) {
+	var data []byte
+	// ADK web client will send a request that is always encapsulated in a 'response' key.
+	if resp, ok := response["response"]; ok && len(response) == 1 {
+		jsonString, ok := resp.(string)
+		if !ok {
+			return nil, fmt.Errorf("'response' key found but value is not a string")
+		}
+		data = []byte(jsonString)
+	} else {
+		var err error
+		data, err = json.Marshal(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var authConfig auth.AuthConfig
+	if err := json.Unmarshal(data, &authConfig); err != nil {
+		return nil, err
+	}
+	return &authConfig, nil
+}
+
+// exchangeAuthResponse returns the credential the tools can use from the
+// auth config posted back by the client. OAuth2 authorization codes are
+// exchanged for tokens.
+func exchangeAuthResponse(ctx agent.InvocationContext, authConfig *auth.AuthConfig) (*auth.AuthCredential, error) {
+	if authConfig.ExchangedAuthCredential == nil {
+		return nil, fmt.Errorf("auth response has no exchanged credential")
+	}
+	if !authConfig.RequiresUserAuthorization() {
+		return authConfig.ExchangedAuthCredential, nil
+	}
+	return auth.Exchange(ctx, authConfig)
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestAuthPreprocessor(t *testing.T) {
+	var exchanges int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "auth-code" {
+			http.Error(w, "invalid code", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access-token","token_type":"bearer","refresh_token":"refresh-token","expires_in":3600}`)
+	}))
+	defer tokenServer.Close()
+
+	authConfig := &auth.AuthConfig{
+		AuthScheme: &auth.AuthScheme{
+			Type: auth.SchemeTypeOAuth2,
+			Flows: &auth.OAuthFlows{
+				AuthorizationCode: &auth.OAuthFlow{
+					AuthorizationURL: tokenServer.URL + "/authorize",
+					TokenURL:         tokenServer.URL + "/token",
+					Scopes:           map[string]string{"read": "read access"},
+				},
+			},
+		},
+		RawAuthCredential: &auth.AuthCredential{
+			AuthType: auth.CredentialTypeOAuth2,
+			OAuth2: &auth.OAuth2Auth{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				RedirectURI:  "https://example.com/callback",
+			},
+		},
+	}
+
+	fetchTool, err := functiontool.New(functiontool.Config{
+		Name:        "fetch",
+		Description: "fetches the user data",
+	}, func(ctx tool.Context, args struct{}) (map[string]any, error) {
+		cred, err := toolutils.ResolveCredential(ctx, authConfig)
+		if err != nil {
+			return nil, err
+		}
+		if cred == nil {
+			return map[string]any{"status": "pending"}, nil
+		}
+		return map[string]any{"token": cred.OAuth2.AccessToken}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The model makes another step after the resumed tool.
+	noopTool, err := functiontool.New(functiontool.Config{
+		Name:        "noop",
+		Description: "does nothing",
+	}, func(ctx tool.Context, args struct{}) (map[string]any, error) {
+		return map[string]any{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("fetch", map[string]any{}, genai.RoleModel),
+			genai.NewContentFromFunctionCall("noop", map[string]any{}, genai.RoleModel),
+			genai.NewContentFromText("done", genai.RoleModel),
+			genai.NewContentFromFunctionCall("fetch", map[string]any{}, genai.RoleModel),
+			genai.NewContentFromText("done again", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: mockModel,
+		Tools: []tool.Tool{fetchTool, noopTool},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService := session.InMemoryService()
+	credentialService := auth.InMemoryCredentialService()
+	r, err := runner.New(runner.Config{
+		AppName:           "app",
+		Agent:             a,
+		SessionService:    sessionService,
+		CredentialService: credentialService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(msg *genai.Content) []*session.Event {
+		t.Helper()
+		var events []*session.Event
+		for ev, err := range r.Run(t.Context(), "user", created.Session.ID(), msg, agent.RunConfig{}) {
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			events = append(events, ev)
+		}
+		return events
+	}
+
+	// The tool requests the credential.
+	var requestCall *genai.FunctionCall
+	for _, ev := range run(genai.NewContentFromText("fetch my data", genai.RoleUser)) {
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part.FunctionCall != nil && part.FunctionCall.Name == auth.FunctionCallName {
+				requestCall = part.FunctionCall
+				if !slices.Contains(ev.LongRunningToolIDs, requestCall.ID) {
+					t.Errorf("credential request %q is not a long running tool call: %v", requestCall.ID, ev.LongRunningToolIDs)
+				}
+			}
+		}
+	}
+	if requestCall == nil {
+		t.Fatalf("no %q function call was emitted", auth.FunctionCallName)
+	}
+	var requested auth.AuthConfig
+	data, _ := json.Marshal(requestCall.Args["authConfig"])
+	if err := json.Unmarshal(data, &requested); err != nil {
+		t.Fatalf("failed to decode the requested auth config: %v", err)
+	}
+	oauth2 := requested.ExchangedAuthCredential.OAuth2
+	if !strings.HasPrefix(oauth2.AuthURI, tokenServer.URL+"/authorize?") || oauth2.State == "" {
+		t.Fatalf("requested auth config has AuthURI %q and State %q, want an authorization URI with a state", oauth2.AuthURI, oauth2.State)
+	}
+
+	// The client posts back the redirect URI, the tool is resumed with the
+	// exchanged token.
+	oauth2.AuthResponseURI = "https://example.com/callback?code=auth-code&state=" + oauth2.State
+	response := map[string]any{}
+	data, _ = json.Marshal(requested)
+	_ = json.Unmarshal(data, &response)
+	events := run(&genai.Content{
+		Role: genai.RoleUser,
+		Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+			ID:       requestCall.ID,
+			Name:     auth.FunctionCallName,
+			Response: response,
+		}}},
+	})
+	if diff := cmp.Diff([]map[string]any{{"token": "access-token"}}, functionResponses(events, "fetch")); diff != "" {
+		t.Errorf("resumed tool responses mismatch (-want +got):\n%s", diff)
+	}
+	if got := events[len(events)-1].Content.Parts[0].Text; got != "done" {
+		t.Errorf("final response = %q, want %q", got, "done")
+	}
+	if exchanges != 1 {
+		t.Errorf("authorization code exchanged %d times, want 1", exchanges)
+	}
+
+	stored, err := credentialService.Load(t.Context(), &auth.LoadRequest{AppName: "app", UserID: "user", AuthConfig: authConfig})
+	if err != nil || stored.Credential == nil || stored.Credential.OAuth2.AccessToken != "access-token" {
+		t.Errorf("credentialService.Load() = (%+v, %v), want the exchanged credential", stored, err)
+	}
+
+	// Later calls use the stored credential.
+	events = run(genai.NewContentFromText("fetch my data again", genai.RoleUser))
+	if diff := cmp.Diff([]map[string]any{{"token": "access-token"}}, functionResponses(events, "fetch")); diff != "" {
+		t.Errorf("tool responses with stored credential mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func functionResponses(events []*session.Event, name string) []map[string]any {
+	var responses []map[string]any
+	for _, ev := range events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part.FunctionResponse != nil && part.FunctionResponse.Name == name {
+				responses = append(responses, part.FunctionResponse.Response)
+			}
+		}
+	}
+	return responses
+}
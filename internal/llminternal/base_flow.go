@@ -15,6 +15,7 @@
 package llminternal
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -22,10 +23,15 @@ import (
 	"maps"
 	"slices"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/agent/budget"
+	"google.golang.org/adk/internal/agent/limits"
 	"google.golang.org/adk/internal/agent/parentmap"
 	"google.golang.org/adk/internal/agent/runconfig"
 	icontext "google.golang.org/adk/internal/context"
@@ -58,15 +64,41 @@ type Flow struct {
 
 	Tools                 []tool.Tool
 	RequestProcessors     []func(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error]
-	ResponseProcessors    []func(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error
+	ResponseProcessors    []func(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) iter.Seq2[*session.Event, error]
 	BeforeModelCallbacks  []BeforeModelCallback
 	AfterModelCallbacks   []AfterModelCallback
 	OnModelErrorCallbacks []OnModelErrorCallback
-	BeforeToolCallbacks   []BeforeToolCallback
-	AfterToolCallbacks    []AfterToolCallback
-	OnToolErrorCallbacks  []OnToolErrorCallback
+	// RetryPolicy and RateLimiter override the ones of the run config.
+	RetryPolicy          *model.RetryPolicy
+	RateLimiter          *model.RateLimiter
+	BeforeToolCallbacks  []BeforeToolCallback
+	AfterToolCallbacks   []AfterToolCallback
+	OnToolErrorCallbacks []OnToolErrorCallback
+
+	// TruncatedResponse is the handling of the truncated model responses,
+	// one of the TruncatedResponse constants. Defaults to returning them.
+	TruncatedResponse string
+	// MaxContinuations and ContinuationPrompt apply to
+	// TruncatedResponseContinue.
+	MaxContinuations   int
+	ContinuationPrompt string
 }
 
+// The handling of the model responses truncated, e.g. because they reached
+// the max output tokens, or because the stream ended with partial
+// responses.
+const (
+	// TruncatedResponseReturn returns the truncated response as final.
+	TruncatedResponseReturn = "return"
+	// TruncatedResponseContinue asks the model to continue the response.
+	TruncatedResponseContinue = "continue"
+	// TruncatedResponseError fails with a [model.TruncatedResponseError].
+	TruncatedResponseError = "error"
+
+	DefaultMaxContinuations   = 3
+	DefaultContinuationPrompt = "Your previous response was truncated. Continue it exactly where it stopped, without repeating any of it."
+)
+
 var (
 	DefaultRequestProcessors = []func(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error]{
 		basicRequestProcessor,
@@ -76,6 +108,7 @@ var (
 		instructionsRequestProcessor,
 		identityRequestProcessor,
 		ContentsRequestProcessor,
+		contextCacheRequestProcessor,
 		// Some implementations of NL Planning mark planning contents as thoughts in the post processor.
 		// Since these need to be unmarked, NL Planning should be after contentsRequestProcessor.
 		nlPlanningRequestProcessor,
@@ -86,17 +119,25 @@ var (
 		AgentTransferRequestProcessor,
 		removeDisplayNameIfExists,
 	}
-	DefaultResponseProcessors = []func(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error{
+	DefaultResponseProcessors = []func(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) iter.Seq2[*session.Event, error]{
 		nlPlanningResponseProcessor,
 		codeExecutionResponseProcessor,
 	}
 )
 
 func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	if cfg := runconfig.FromContext(ctx); cfg != nil && cfg.StreamingMode == runconfig.StreamingModeBidi {
+		return f.runLive(ctx, cfg.LiveRequestQueue)
+	}
 	return func(yield func(*session.Event, error) bool) {
+		continuations := 0
+		continuationPrompt := ""
 		for {
 			var lastEvent *session.Event
-			for ev, err := range f.runOneStep(ctx) {
+			// partial aggregates the trailing partial responses, in case the
+			// stream ends without its final response.
+			partial := NewStreamingResponseAggregator()
+			for ev, err := range f.runOneStep(ctx, continuationPrompt) {
 				if err != nil {
 					yield(nil, err)
 					return
@@ -106,21 +147,61 @@ func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error]
 					return
 				}
 				lastEvent = ev
+				if ev.Partial {
+					resp := ev.LLMResponse
+					partial.aggregateResponse(&resp)
+				} else {
+					partial.clear()
+				}
 			}
-			if lastEvent == nil || lastEvent.IsFinalResponse() {
+			if lastEvent == nil {
 				return
 			}
-			if lastEvent.LLMResponse.Partial {
-				// We may have reached max token limit during streaming mode.
-				// TODO: handle Partial response in model level. CL 781377328
-				yield(nil, fmt.Errorf("TODO: last event is not final"))
+			truncated := lastEvent.Author == ctx.Agent().Name() &&
+				(lastEvent.Partial || lastEvent.FinishReason == genai.FinishReasonMaxTokens)
+			if !truncated {
+				if lastEvent.IsFinalResponse() {
+					return
+				}
+				continuationPrompt = ""
+				continue
+			}
+
+			resp := &lastEvent.LLMResponse
+			if lastEvent.Partial {
+				// The partial responses are not saved, save their text.
+				resp = partial.Partial()
+				if resp == nil {
+					resp = &model.LLMResponse{}
+				} else {
+					ev := session.NewEvent(ctx.InvocationID())
+					ev.Author = ctx.Agent().Name()
+					ev.Branch = ctx.Branch()
+					ev.LLMResponse = *resp
+					if !yield(ev, nil) {
+						return
+					}
+				}
+			}
+			switch f.TruncatedResponse {
+			case TruncatedResponseError:
+				yield(nil, &model.TruncatedResponseError{FinishReason: resp.FinishReason, Response: resp})
 				return
+			case TruncatedResponseContinue:
+				if continuations < cmp.Or(f.MaxContinuations, DefaultMaxContinuations) {
+					continuations++
+					continuationPrompt = cmp.Or(f.ContinuationPrompt, DefaultContinuationPrompt)
+					continue
+				}
 			}
+			return
 		}
 	}
 }
 
-func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+// runOneStep calls the model once and handles its response. The
+// continuationPrompt, if set, is sent to continue a truncated response.
+func (f *Flow) runOneStep(ctx agent.InvocationContext, continuationPrompt string) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
 		if f.Model == nil {
 			yield(nil, fmt.Errorf("agent %q: %w", ctx.Agent().Name(), ErrModelNotConfigured))
@@ -146,6 +227,23 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 		if ctx.Ended() {
 			return
 		}
+		if continuationPrompt != "" {
+			req.Contents = append(req.Contents, genai.NewContentFromText(continuationPrompt, genai.RoleUser))
+		}
+		tracker := budget.FromContext(ctx)
+		if tracker != nil {
+			if ev := f.checkBudget(ctx, tracker, req); ev != nil {
+				yield(ev, nil)
+				return
+			}
+		}
+		counter := limits.FromContext(ctx)
+		if counter != nil {
+			if err := counter.AddLLMCall(); err != nil {
+				yield(endInvocationEvent(ctx, agent.ErrorCodeMaxLLMCallsExceeded, err), nil)
+				return
+			}
+		}
 		sctx, callLLMSpan := telemetry.StartTrace(ctx, "call_llm")
 		ctx = ctx.WithContext(sctx)
 		defer callLLMSpan.End()
@@ -157,9 +255,17 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 				yield(nil, err)
 				return
 			}
-			if err := f.postprocess(ctx, req, resp); err != nil {
-				yield(nil, err)
-				return
+			if tracker != nil && !resp.Partial {
+				tracker.Record(f.Model.Name(), resp.UsageMetadata)
+			}
+			for ev, err := range f.postprocess(ctx, req, resp) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(ev, nil) {
+					return
+				}
 			}
 			// Skip the model response event if there is no content and no error code.
 			// This is needed for the code executor to trigger another loop according to
@@ -186,9 +292,19 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 			if !yield(modelResponseEvent, nil) {
 				return
 			}
-			// TODO: generate and yield an auth event if needed.
-
+			if tracker != nil && !resp.Partial {
+				if err := tracker.Exceeded(); err != nil {
+					yield(budgetExceededEvent(ctx, err), nil)
+					return
+				}
+			}
 			// Handle function calls.
+			if counter != nil && !resp.Partial && len(utils.FunctionCalls(resp.Content)) > 0 {
+				if err := counter.AddToolIteration(); err != nil {
+					yield(endInvocationEvent(ctx, agent.ErrorCodeMaxToolIterationsExceeded, err), nil)
+					return
+				}
+			}
 
 			ev, err := f.handleFunctionCalls(ctx, tools, resp, nil)
 			if err != nil {
@@ -200,6 +316,17 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 				continue
 			}
 
+			authEvent, err := generateAuthEvent(ctx, ev)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if authEvent != nil {
+				if !yield(authEvent, nil) {
+					return
+				}
+			}
+
 			toolConfirmationEvent := generateRequestConfirmationEvent(ctx, modelResponseEvent, ev)
 			if toolConfirmationEvent != nil {
 				if !yield(toolConfirmationEvent, nil) {
@@ -244,6 +371,54 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 	}
 }
 
+// checkBudget returns the event ending the invocation if sending the request
+// would exceed the budget of the invocation. The prompt tokens are estimated
+// locally, unless the budget opts in to count them with the model.
+func (f *Flow) checkBudget(ctx agent.InvocationContext, tracker *budget.Tracker, req *model.LLMRequest) *session.Event {
+	tokens := model.EstimateTokens(req)
+	if tracker.CountTokens() {
+		if counted, err := model.CountTokens(ctx, f.Model, req); err == nil {
+			tokens = counted
+		}
+		// Otherwise the usage of the response is still accounted.
+	}
+	if err := tracker.Check(f.Model.Name(), int64(tokens)); err != nil {
+		return budgetExceededEvent(ctx, err)
+	}
+	return nil
+}
+
+// budgetExceededEvent ends the invocation with an event describing the
+// exceeded budget.
+func budgetExceededEvent(ctx agent.InvocationContext, err error) *session.Event {
+	ev := endInvocationEvent(ctx, agent.ErrorCodeBudgetExceeded, err)
+	var exceeded *budget.ExceededError
+	if errors.As(err, &exceeded) {
+		ev.CustomMetadata = map[string]any{
+			"budget": map[string]any{
+				"tokens":     exceeded.Tokens,
+				"max_tokens": exceeded.MaxTokens,
+				"cost":       exceeded.Cost,
+				"max_cost":   exceeded.MaxCost,
+			},
+		}
+	}
+	return ev
+}
+
+// endInvocationEvent ends the invocation with an event holding the error
+// code and message.
+func endInvocationEvent(ctx agent.InvocationContext, errorCode string, err error) *session.Event {
+	ctx.EndInvocation()
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.ErrorCode = errorCode
+	ev.ErrorMessage = err.Error()
+	ev.TurnComplete = true
+	return ev
+}
+
 func (f *Flow) preprocess(ctx agent.InvocationContext, req *model.LLMRequest) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
 		// apply request processor functions to the request in the configured order.
@@ -253,8 +428,8 @@ func (f *Flow) preprocess(ctx agent.InvocationContext, req *model.LLMRequest) it
 					yield(nil, err)
 					return
 				}
-				if ev != nil {
-					yield(ev, nil)
+				if ev != nil && !yield(ev, nil) {
+					return
 				}
 			}
 		}
@@ -313,7 +488,7 @@ func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, state
 		// TODO: RunLive mode when invocation_context.run_config.support_cfc is true.
 		useStream := runconfig.FromContext(ctx).StreamingMode == runconfig.StreamingModeSSE
 
-		for resp, err := range f.Model.GenerateContent(ctx, req, useStream) {
+		for resp, err := range f.generateContent(ctx, req, useStream) {
 			if err != nil {
 				cbResp, cbErr := f.runOnModelErrorCallbacks(ctx, req, stateDelta, err)
 				if cbErr != nil {
@@ -357,6 +532,68 @@ func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, state
 	}
 }
 
+// generateContent calls the model, waiting for the rate limiter and
+// retrying the calls failed before yielding any response as set by the
+// retry policy, so the consumers never receive duplicate partial responses. The waits and retries are recorded in the current span.
+func (f *Flow) generateContent(ctx agent.InvocationContext, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	retryPolicy, rateLimiter := f.RetryPolicy, f.RateLimiter
+	if cfg := runconfig.FromContext(ctx); cfg != nil {
+		retryPolicy = cmp.Or(retryPolicy, cfg.RetryPolicy)
+		rateLimiter = cmp.Or(rateLimiter, cfg.RateLimiter)
+	}
+	if retryPolicy == nil && rateLimiter == nil {
+		return f.Model.GenerateContent(ctx, req, stream)
+	}
+	return func(yield func(*model.LLMResponse, error) bool) {
+		span := trace.SpanFromContext(ctx)
+		for attempt := 1; ; attempt++ {
+			if rateLimiter != nil {
+				wait, err := rateLimiter.Wait(ctx, f.Model.Name())
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if wait > 0 {
+					telemetry.TraceRateLimitWait(span, wait)
+				}
+			}
+
+			var llmErr error
+			yielded := false
+			for resp, err := range f.Model.GenerateContent(ctx, req, stream) {
+				if err != nil {
+					llmErr = err
+					break
+				}
+				if resp == nil {
+					continue
+				}
+				yielded = true
+				if !yield(resp, nil) {
+					return
+				}
+			}
+			if llmErr == nil {
+				return
+			}
+			delay, retry := retryPolicy.Delay(attempt, llmErr)
+			if yielded || !retry {
+				yield(nil, llmErr)
+				return
+			}
+			telemetry.TraceLLMRetry(span, attempt, delay, llmErr)
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				yield(nil, ctx.Err())
+				return
+			}
+		}
+	}
+}
+
 func (f *Flow) runAfterModelCallbacks(ctx agent.InvocationContext, llmResp *model.LLMResponse, stateDelta map[string]any, llmErr error) (*model.LLMResponse, error) {
 	pluginManager := pluginManagerFromContext(ctx)
 	if pluginManager != nil {
@@ -401,14 +638,23 @@ func (f *Flow) runOnModelErrorCallbacks(ctx agent.InvocationContext, llmReq *mod
 	return nil, nil
 }
 
-func (f *Flow) postprocess(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error {
-	// apply response processor functions to the response in the configured order.
-	for _, processor := range f.ResponseProcessors {
-		if err := processor(ctx, req, resp); err != nil {
-			return err
+func (f *Flow) postprocess(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		// apply response processor functions to the response in the configured order.
+		for _, processor := range f.ResponseProcessors {
+			for ev, err := range processor(ctx, req, resp) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if ev != nil {
+					if !yield(ev, nil) {
+						return
+					}
+				}
+			}
 		}
 	}
-	return nil
 }
 
 func (f *Flow) agentToRun(ctx agent.InvocationContext, agentName string) agent.Agent {
@@ -724,6 +970,12 @@ func mergeEventActions(base, other *session.EventActions) *session.EventActions
 		}
 		maps.Copy(base.RequestedToolConfirmations, other.RequestedToolConfirmations)
 	}
+	if other.RequestedAuthConfigs != nil {
+		if base.RequestedAuthConfigs == nil {
+			base.RequestedAuthConfigs = make(map[string]auth.AuthConfig)
+		}
+		maps.Copy(base.RequestedAuthConfigs, other.RequestedAuthConfigs)
+	}
 	return base
 }
 
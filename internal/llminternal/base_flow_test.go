@@ -15,12 +15,20 @@
 package llminternal
 
 import (
+	"context"
 	"errors"
+	"iter"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/budget"
+	"google.golang.org/adk/internal/agent/runconfig"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/model"
@@ -575,3 +583,272 @@ func TestMergeEventActions(t *testing.T) {
 		})
 	}
 }
+
+func TestPreprocess_StopsWhenConsumerStops(t *testing.T) {
+	var calls int
+	processor := func(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
+		return func(yield func(*session.Event, error) bool) {
+			calls++
+			for range 2 {
+				if !yield(session.NewEvent(ctx.InvocationID()), nil) {
+					return
+				}
+			}
+		}
+	}
+	f := &Flow{RequestProcessors: []func(agent.InvocationContext, *model.LLMRequest, *Flow) iter.Seq2[*session.Event, error]{processor, processor}}
+	ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
+
+	var got int
+	for range f.preprocess(ctx, &model.LLMRequest{}) {
+		got++
+		break
+	}
+	if got != 1 || calls != 1 {
+		t.Errorf("preprocess() yielded %d events from %d processors after the consumer stopped, want 1 event from 1 processor", got, calls)
+	}
+}
+
+// scriptedModel yields the responses of the next script for each call.
+type scriptedModel struct {
+	scripts [][]scriptedResponse
+	calls   int
+}
+
+type scriptedResponse struct {
+	text    string
+	partial bool
+	err     error
+	// empty yields a nil response.
+	empty bool
+}
+
+func (m *scriptedModel) Name() string { return "scripted" }
+
+func (m *scriptedModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	script := m.scripts[min(m.calls, len(m.scripts)-1)]
+	m.calls++
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for _, r := range script {
+			if r.err != nil {
+				yield(nil, r.err)
+				return
+			}
+			if r.empty {
+				if !yield(nil, nil) {
+					return
+				}
+				continue
+			}
+			if !yield(&model.LLMResponse{Content: genai.NewContentFromText(r.text, genai.RoleModel), Partial: r.partial}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestGenerateContent_RetryAndRateLimit(t *testing.T) {
+	errUnavailable := genai.APIError{Code: 503, Message: "unavailable"}
+	errBadRequest := genai.APIError{Code: 400, Message: "bad request"}
+	policy := &model.RetryPolicy{InitialDelay: time.Millisecond, Jitter: -1}
+
+	tests := []struct {
+		name        string
+		scripts     [][]scriptedResponse
+		retryPolicy *model.RetryPolicy
+		rateLimiter *model.RateLimiter
+		runConfig   *runconfig.RunConfig
+		want        []string
+		wantErrCode int
+		wantCalls   int
+		wantEvents  []string
+	}{
+		{
+			name:        "no policy",
+			scripts:     [][]scriptedResponse{{{err: errUnavailable}}, {{text: "hello"}}},
+			wantErrCode: 503,
+			wantCalls:   1,
+		},
+		{
+			name:        "retries transient error",
+			scripts:     [][]scriptedResponse{{{err: errUnavailable}}, {{text: "hello"}}},
+			retryPolicy: policy,
+			want:        []string{"hello"},
+			wantCalls:   2,
+			wantEvents:  []string{"llm_retry"},
+		},
+		{
+			name:       "retry policy of the run config",
+			scripts:    [][]scriptedResponse{{{err: errUnavailable}}, {{text: "hello"}}},
+			runConfig:  &runconfig.RunConfig{RetryPolicy: policy},
+			want:       []string{"hello"},
+			wantCalls:  2,
+			wantEvents: []string{"llm_retry"},
+		},
+		{
+			name:        "agent policy takes over the run config",
+			scripts:     [][]scriptedResponse{{{err: errUnavailable}}, {{err: errUnavailable}}, {{text: "hello"}}},
+			retryPolicy: &model.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond},
+			runConfig:   &runconfig.RunConfig{RetryPolicy: policy},
+			wantErrCode: 503,
+			wantCalls:   2,
+			wantEvents:  []string{"llm_retry"},
+		},
+		{
+			name:        "gives up after max attempts",
+			scripts:     [][]scriptedResponse{{{err: errUnavailable}}},
+			retryPolicy: policy,
+			wantErrCode: 503,
+			wantCalls:   3,
+			wantEvents:  []string{"llm_retry", "llm_retry"},
+		},
+		{
+			name:        "does not retry permanent error",
+			scripts:     [][]scriptedResponse{{{err: errBadRequest}}, {{text: "hello"}}},
+			retryPolicy: policy,
+			wantErrCode: 400,
+			wantCalls:   1,
+		},
+		{
+			name: "does not retry after partial responses",
+			scripts: [][]scriptedResponse{
+				{{text: "hel", partial: true}, {err: errUnavailable}},
+				{{text: "hel", partial: true}, {text: "hello"}},
+			},
+			retryPolicy: policy,
+			want:        []string{"hel (partial)"},
+			wantErrCode: 503,
+			wantCalls:   1,
+		},
+		{
+			name: "retries after nil responses",
+			scripts: [][]scriptedResponse{
+				{{empty: true}, {err: errUnavailable}},
+				{{text: "hello"}},
+			},
+			retryPolicy: policy,
+			want:        []string{"hello"},
+			wantCalls:   2,
+			wantEvents:  []string{"llm_retry"},
+		},
+		{
+			name:        "does not retry after final response",
+			scripts:     [][]scriptedResponse{{{text: "hello"}, {err: errUnavailable}}},
+			retryPolicy: policy,
+			want:        []string{"hello"},
+			wantErrCode: 503,
+			wantCalls:   1,
+		},
+		{
+			name:        "waits for rate limiter",
+			scripts:     [][]scriptedResponse{{{err: errUnavailable}}, {{text: "hello"}}},
+			retryPolicy: policy,
+			rateLimiter: model.NewRateLimiter(model.RateLimit{Rate: 100}, nil),
+			want:        []string{"hello"},
+			wantCalls:   2,
+			wantEvents:  []string{"llm_retry", "rate_limit_wait"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			spanCtx, span := tp.Tracer("test").Start(t.Context(), "call_llm")
+			if tt.runConfig != nil {
+				spanCtx = runconfig.ToContext(spanCtx, tt.runConfig)
+			}
+			ctx := icontext.NewInvocationContext(spanCtx, icontext.InvocationContextParams{})
+
+			llm := &scriptedModel{scripts: tt.scripts}
+			f := &Flow{Model: llm, RetryPolicy: tt.retryPolicy, RateLimiter: tt.rateLimiter}
+			var got []string
+			var gotErr error
+			for resp, err := range f.generateContent(ctx, &model.LLMRequest{}, true) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				s := resp.Content.Parts[0].Text
+				if resp.Partial {
+					s += " (partial)"
+				}
+				got = append(got, s)
+			}
+			span.End()
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("generateContent() responses mismatch (-want +got):\n%s", diff)
+			}
+			gotErrCode := 0
+			if apiErr := (genai.APIError{}); errors.As(gotErr, &apiErr) {
+				gotErrCode = apiErr.Code
+			}
+			if gotErrCode != tt.wantErrCode {
+				t.Errorf("generateContent() error = %v, want code %d", gotErr, tt.wantErrCode)
+			}
+			if llm.calls != tt.wantCalls {
+				t.Errorf("generateContent() made %d model calls, want %d", llm.calls, tt.wantCalls)
+			}
+			var gotEvents []string
+			for _, ev := range recorder.Ended()[0].Events() {
+				gotEvents = append(gotEvents, ev.Name)
+			}
+			if diff := cmp.Diff(tt.wantEvents, gotEvents); diff != "" {
+				t.Errorf("span events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// countingModel is a model.TokenCounter counting its calls.
+type countingModel struct {
+	scriptedModel
+	tokens int32
+	counts int
+}
+
+func (m *countingModel) CountTokens(ctx context.Context, req *model.LLMRequest) (int32, error) {
+	m.counts++
+	return m.tokens, nil
+}
+
+func TestCheckBudget(t *testing.T) {
+	a, err := agent.New(agent.Config{Name: "worker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		budget     agent.Budget
+		wantEnded  bool
+		wantCounts int
+	}{
+		{
+			name:   "estimates the prompt tokens",
+			budget: agent.Budget{MaxTokens: 500},
+		},
+		{
+			name:       "counts the prompt tokens on opt-in",
+			budget:     agent.Budget{MaxTokens: 500, CountTokens: true},
+			wantEnded:  true,
+			wantCounts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The model counts more tokens than estimated for the short request.
+			llm := &countingModel{tokens: 1000}
+			f := &Flow{Model: llm}
+			ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{Agent: a})
+
+			ev := f.checkBudget(ctx, budget.New(tt.budget), &model.LLMRequest{Contents: genai.Text("hi")})
+			if gotEnded := ev != nil && ev.ErrorCode == agent.ErrorCodeBudgetExceeded; gotEnded != tt.wantEnded {
+				t.Errorf("checkBudget() = %v, want budget exceeded %v", ev, tt.wantEnded)
+			}
+			if llm.counts != tt.wantCounts {
+				t.Errorf("checkBudget() counted the tokens %d times, want %d", llm.counts, tt.wantCounts)
+			}
+		})
+	}
+}
@@ -50,8 +50,15 @@ func basicRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f
 			req.Config.ResponseMIMEType = "application/json"
 		}
 
-		// TODO: missing features
-		//  populate LLMRequest LiveConnectConfig setting
+		if cfg := ctx.RunConfig(); cfg != nil && cfg.StreamingMode == agent.StreamingModeBidi {
+			req.LiveConnectConfig = &genai.LiveConnectConfig{
+				ResponseModalities:       cfg.ResponseModalities,
+				SpeechConfig:             cfg.SpeechConfig,
+				InputAudioTranscription:  cfg.InputAudioTranscription,
+				OutputAudioTranscription: cfg.OutputAudioTranscription,
+				RealtimeInputConfig:      cfg.RealtimeInputConfig,
+			}
+		}
 	}
 }
 
@@ -0,0 +1,579 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"iter"
+	"maps"
+	"path"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/codeexecutor"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// Session state keys used to keep the code executor bookkeeping across
+// model calls. Values are kept JSON-compatible so they survive persistent
+// session services.
+const (
+	codeExecutorErrorCountsKey    = "_code_executor_error_counts"
+	codeExecutorInputFilesKey     = "_code_executor_input_files"
+	codeExecutorProcessedFilesKey = "_code_executor_processed_files"
+)
+
+// dataFileLoaders maps the MIME types of the data files supported by the
+// data file optimization to their file extension and python loader code.
+var dataFileLoaders = map[string]struct {
+	extension string
+	loader    string
+}{
+	"text/csv": {extension: ".csv", loader: "pd.read_csv('%s')"},
+}
+
+const dataFileHelperLib = `
+import pandas as pd
+
+def explore_df(df: pd.DataFrame) -> None:
+  """Prints some information about a pandas DataFrame."""
+
+  with pd.option_context(
+      'display.max_columns', None, 'display.expand_frame_repr', False
+  ):
+    # Print the column names to never encounter KeyError when selecting one.
+    df_dtypes = df.dtypes
+
+    # Obtain information about data types and missing values.
+    df_nulls = (len(df) - df.isnull().sum()).apply(
+        lambda x: f'{x} / {df.shape[0]} non-null'
+    )
+
+    # Explore unique total values in columns using ` + "`.unique()`" + `.
+    df_unique_count = df.apply(lambda x: len(x.unique()))
+
+    # Explore unique values in columns using ` + "`.unique()`" + `.
+    df_unique = df.apply(lambda x: crop(str(list(x.unique()))))
+
+    df_info = pd.concat(
+        (
+            df_dtypes.rename('Dtype'),
+            df_nulls.rename('Non-Null Count'),
+            df_unique_count.rename('Unique Values Count'),
+            df_unique.rename('Unique Values'),
+        ),
+        axis=1,
+    )
+    df_info.index.name = 'Columns'
+    print(f"""Total rows: {df.shape[0]}
+Total columns: {df.shape[1]}
+
+{df_info}""")
+
+
+def crop(s: str, max_chars: int = 64) -> str:
+  """Truncates a string to a maximum number of characters."""
+  if s and len(s) > max_chars:
+    return s[:max_chars] + '...'
+  return s
+`
+
+// codeExecutionRequestProcessor prepares the request for agents with a code
+// executor: it lets model side executors adjust the request, runs the data
+// file optimization and converts code execution parts from the history to
+// text, as non built-in executors are unknown to the model.
+func codeExecutionRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
+	// reference: adk-python src/google/adk/flows/llm_flows/_code_execution.py
+	return func(yield func(*session.Event, error) bool) {
+		llmAgent := asLLMAgent(ctx.Agent())
+		if llmAgent == nil {
+			return
+		}
+		executor := llmAgent.internal().CodeExecutor
+		if executor == nil {
+			return
+		}
+		if p, ok := executor.(codeexecutor.RequestProcessor); ok {
+			if err := p.ProcessRequest(req); err != nil {
+				yield(nil, fmt.Errorf("failed to process request for code executor: %w", err))
+			}
+			return
+		}
+
+		cfg := executor.Config()
+		if cfg.OptimizeDataFile {
+			for ev, err := range runDataFilePreprocessing(ctx, req, executor) {
+				if !yield(ev, err) || err != nil {
+					return
+				}
+			}
+		}
+
+		codeDelimiter := codeexecutor.Delimiter{}
+		if len(cfg.CodeBlockDelimiters) > 0 {
+			codeDelimiter = cfg.CodeBlockDelimiters[0]
+		}
+		for _, content := range req.Contents {
+			convertCodeExecutionParts(content, codeDelimiter, cfg.ExecutionResultDelimiters)
+		}
+	}
+}
+
+// codeExecutionResponseProcessor extracts the code from the model response,
+// executes it and yields the code and the execution result events.
+//
+// When code is executed the response content is cleared, so the flow skips
+// the original model response event and calls the model again with the
+// execution result.
+func codeExecutionResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if resp == nil || resp.Partial {
+			return
+		}
+		llmAgent := asLLMAgent(ctx.Agent())
+		if llmAgent == nil {
+			return
+		}
+		executor := llmAgent.internal().CodeExecutor
+		if executor == nil {
+			return
+		}
+		if _, ok := executor.(codeexecutor.RequestProcessor); ok {
+			// The code was executed by the model.
+			return
+		}
+
+		cfg := executor.Config()
+		state := newCodeExecutorState(ctx)
+		if cfg.ErrorRetryAttempts > 0 && state.errorCount(ctx.InvocationID()) >= cfg.ErrorRetryAttempts {
+			return
+		}
+
+		code := extractCodeAndTruncateContent(resp.Content, cfg.CodeBlockDelimiters)
+		if code == "" {
+			return
+		}
+
+		codeEvent := session.NewEvent(ctx.InvocationID())
+		codeEvent.Author = ctx.Agent().Name()
+		codeEvent.Branch = ctx.Branch()
+		codeEvent.LLMResponse = *resp
+		if !yield(codeEvent, nil) {
+			return
+		}
+
+		result, err := executor.Execute(ctx, &codeexecutor.Input{
+			Code:        code,
+			InputFiles:  state.inputFiles(),
+			ExecutionID: executionID(ctx, cfg),
+		})
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to execute code: %w", err))
+			return
+		}
+		resultEvent, err := postprocessCodeExecutionResult(ctx, state, result)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if !yield(resultEvent, nil) {
+			return
+		}
+		// The model response was already yielded as the code event.
+		resp.Content = nil
+	}
+}
+
+// runDataFilePreprocessing replaces the data files inlined in the request
+// with a placeholder and runs a snippet loading and describing each new file,
+// so the model can work on the data without reading the raw file.
+func runDataFilePreprocessing(ctx agent.InvocationContext, req *model.LLMRequest, executor codeexecutor.CodeExecutor) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		state := newCodeExecutorState(ctx)
+		cfg := executor.Config()
+		if cfg.ErrorRetryAttempts > 0 && state.errorCount(ctx.InvocationID()) >= cfg.ErrorRetryAttempts {
+			return
+		}
+
+		processed := state.processedFiles()
+		for _, file := range extractAndReplaceInlineFiles(state, req) {
+			if processed[file.Name] {
+				continue
+			}
+			code := dataFilePreprocessingCode(file)
+			if code == "" {
+				continue
+			}
+			codeEvent := session.NewEvent(ctx.InvocationID())
+			codeEvent.Author = ctx.Agent().Name()
+			codeEvent.Branch = ctx.Branch()
+			codeEvent.Content = &genai.Content{
+				Role: genai.RoleModel,
+				Parts: []*genai.Part{
+					genai.NewPartFromText(fmt.Sprintf("Processing input file: `%s`", file.Name)),
+					genai.NewPartFromExecutableCode(code, genai.LanguagePython),
+				},
+			}
+			req.Contents = append(req.Contents, clone(codeEvent.Content))
+			if !yield(codeEvent, nil) {
+				return
+			}
+
+			result, err := executor.Execute(ctx, &codeexecutor.Input{
+				Code:        code,
+				InputFiles:  []codeexecutor.File{file},
+				ExecutionID: executionID(ctx, cfg),
+			})
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to execute data file preprocessing code: %w", err))
+				return
+			}
+			state.addProcessedFiles(file.Name)
+			resultEvent, err := postprocessCodeExecutionResult(ctx, state, result)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			req.Contents = append(req.Contents, clone(resultEvent.Content))
+			if !yield(resultEvent, nil) {
+				return
+			}
+		}
+	}
+}
+
+// extractAndReplaceInlineFiles replaces the supported data files inlined in
+// the user contents of the request with a text placeholder. The files are
+// registered as code executor input files and returned.
+func extractAndReplaceInlineFiles(state *codeExecutorState, req *model.LLMRequest) []codeexecutor.File {
+	known := make(map[string]bool)
+	for _, f := range state.inputFiles() {
+		known[f.Name] = true
+	}
+	var files, added []codeexecutor.File
+	for i, content := range req.Contents {
+		if content == nil || content.Role != genai.RoleUser {
+			continue
+		}
+		for j, part := range content.Parts {
+			if part == nil || part.InlineData == nil {
+				continue
+			}
+			loader, ok := dataFileLoaders[part.InlineData.MIMEType]
+			if !ok {
+				continue
+			}
+			name := fmt.Sprintf("data_%d_%d%s", i+1, j+1, loader.extension)
+			content.Parts[j] = genai.NewPartFromText(fmt.Sprintf("\nAvailable file: `%s`\n", name))
+			file := codeexecutor.File{Name: name, Content: part.InlineData.Data, MIMEType: part.InlineData.MIMEType}
+			if !known[name] {
+				known[name] = true
+				added = append(added, file)
+			}
+			files = append(files, file)
+		}
+	}
+	state.addInputFiles(added...)
+	return files
+}
+
+func dataFilePreprocessingCode(file codeexecutor.File) string {
+	loader, ok := dataFileLoaders[file.MIMEType]
+	if !ok {
+		return ""
+	}
+	varName := normalizedFileName(file.Name)
+	return fmt.Sprintf(`
+%s
+
+# Load the dataframe.
+%s = %s
+
+# Use `+"`explore_df`"+` to guide my analysis.
+explore_df(%s)
+`, dataFileHelperLib, varName, fmt.Sprintf(loader.loader, file.Name), varName)
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// normalizedFileName returns a python variable name for the data file.
+func normalizedFileName(name string) string {
+	name = nonIdentifierChars.ReplaceAllString(strings.TrimSuffix(name, path.Ext(name)), "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// postprocessCodeExecutionResult saves the output files of the execution as
+// artifacts and creates the event holding the execution result.
+func postprocessCodeExecutionResult(ctx agent.InvocationContext, state *codeExecutorState, result *codeexecutor.Result) (*session.Event, error) {
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.Content = &genai.Content{
+		Role:  genai.RoleModel,
+		Parts: []*genai.Part{buildCodeExecutionResultPart(result)},
+	}
+
+	if result.Stderr != "" {
+		state.incrementErrorCount(ctx.InvocationID())
+	} else {
+		state.resetErrorCount(ctx.InvocationID())
+	}
+
+	if len(result.OutputFiles) > 0 {
+		if ctx.Artifacts() == nil {
+			return nil, fmt.Errorf("artifact service is not set, cannot save %d code execution output files", len(result.OutputFiles))
+		}
+		ev.Actions.ArtifactDelta = make(map[string]int64)
+		for _, f := range result.OutputFiles {
+			resp, err := ctx.Artifacts().Save(ctx, f.Name, genai.NewPartFromBytes(f.Content, f.MIMEType))
+			if err != nil {
+				return nil, fmt.Errorf("failed to save code execution output file %q: %w", f.Name, err)
+			}
+			ev.Actions.ArtifactDelta[f.Name] = resp.Version
+		}
+	}
+	maps.Copy(ev.Actions.StateDelta, state.delta)
+	return ev, nil
+}
+
+func buildCodeExecutionResultPart(result *codeexecutor.Result) *genai.Part {
+	if result.Stderr != "" {
+		return genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, result.Stderr)
+	}
+	var sections []string
+	if result.Stdout != "" || len(result.OutputFiles) == 0 {
+		sections = append(sections, "Code execution result:\n"+result.Stdout+"\n")
+	}
+	if len(result.OutputFiles) > 0 {
+		names := make([]string, 0, len(result.OutputFiles))
+		for _, f := range result.OutputFiles {
+			names = append(names, "`"+f.Name+"`")
+		}
+		sections = append(sections, "Saved artifacts:\n"+strings.Join(names, ","))
+	}
+	return genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, strings.Join(sections, "\n\n"))
+}
+
+// extractCodeAndTruncateContent returns the first code block of the content
+// and truncates the content right after it, so that the text the model
+// hallucinated as the code output is dropped.
+//
+// It returns an empty string if the content has no code to execute.
+func extractCodeAndTruncateContent(content *genai.Content, delimiters []codeexecutor.Delimiter) string {
+	if content == nil || len(content.Parts) == 0 {
+		return ""
+	}
+
+	// Executable code parts take precedence over code blocks in text, unless
+	// they were already executed.
+	for i, part := range content.Parts {
+		if part == nil || part.ExecutableCode == nil {
+			continue
+		}
+		if i == len(content.Parts)-1 || content.Parts[i+1] == nil || content.Parts[i+1].CodeExecutionResult == nil {
+			content.Parts = content.Parts[:i+1]
+			return part.ExecutableCode.Code
+		}
+	}
+
+	if len(delimiters) == 0 {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part != nil && part.Text != "" && !part.Thought {
+			texts = append(texts, part.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return ""
+	}
+	var leading, trailing []string
+	for _, d := range delimiters {
+		leading = append(leading, regexp.QuoteMeta(d.Start))
+		trailing = append(trailing, regexp.QuoteMeta(d.End))
+	}
+	re, err := regexp.Compile(`(?s)^(.*?)(?:` + strings.Join(leading, "|") + `)(.*?)(?:` + strings.Join(trailing, "|") + `)`)
+	if err != nil {
+		return ""
+	}
+	m := re.FindStringSubmatch(strings.Join(texts, ""))
+	if m == nil || m[2] == "" {
+		return ""
+	}
+	prefix, code := m[1], m[2]
+	content.Parts = nil
+	if prefix != "" {
+		content.Parts = append(content.Parts, genai.NewPartFromText(prefix))
+	}
+	content.Parts = append(content.Parts, genai.NewPartFromExecutableCode(code, genai.LanguagePython))
+	return code
+}
+
+// convertCodeExecutionParts converts a trailing executable code part or a
+// lone code execution result part into text wrapped in the delimiters.
+func convertCodeExecutionParts(content *genai.Content, codeDelimiter, resultDelimiter codeexecutor.Delimiter) {
+	if content == nil || len(content.Parts) == 0 {
+		return
+	}
+	last := content.Parts[len(content.Parts)-1]
+	switch {
+	case last != nil && last.ExecutableCode != nil:
+		content.Parts[len(content.Parts)-1] = genai.NewPartFromText(codeDelimiter.Start + last.ExecutableCode.Code + codeDelimiter.End)
+	case len(content.Parts) == 1 && last != nil && last.CodeExecutionResult != nil:
+		content.Parts[0] = genai.NewPartFromText(resultDelimiter.Start + last.CodeExecutionResult.Output + resultDelimiter.End)
+		content.Role = genai.RoleUser
+	}
+}
+
+func executionID(ctx agent.InvocationContext, cfg codeexecutor.Config) string {
+	if !cfg.Stateful || ctx.Session() == nil {
+		return ""
+	}
+	return ctx.Session().ID()
+}
+
+// codeExecutorState reads the code executor bookkeeping from the session
+// state and collects the updates in delta.
+type codeExecutorState struct {
+	state session.ReadonlyState
+	delta map[string]any
+}
+
+func newCodeExecutorState(ctx agent.InvocationContext) *codeExecutorState {
+	s := &codeExecutorState{delta: make(map[string]any)}
+	if ctx.Session() != nil {
+		s.state = ctx.Session().State()
+	}
+	return s
+}
+
+func (s *codeExecutorState) get(key string) any {
+	if v, ok := s.delta[key]; ok {
+		return v
+	}
+	if s.state == nil {
+		return nil
+	}
+	v, err := s.state.Get(key)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+func (s *codeExecutorState) errorCounts() map[string]any {
+	counts := make(map[string]any)
+	if m, ok := s.get(codeExecutorErrorCountsKey).(map[string]any); ok {
+		maps.Copy(counts, m)
+	}
+	return counts
+}
+
+func (s *codeExecutorState) errorCount(invocationID string) int {
+	switch v := s.errorCounts()[invocationID].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func (s *codeExecutorState) incrementErrorCount(invocationID string) {
+	counts := s.errorCounts()
+	counts[invocationID] = s.errorCount(invocationID) + 1
+	s.delta[codeExecutorErrorCountsKey] = counts
+}
+
+func (s *codeExecutorState) resetErrorCount(invocationID string) {
+	counts := s.errorCounts()
+	if _, ok := counts[invocationID]; !ok {
+		return
+	}
+	delete(counts, invocationID)
+	s.delta[codeExecutorErrorCountsKey] = counts
+}
+
+func (s *codeExecutorState) processedFiles() map[string]bool {
+	processed := make(map[string]bool)
+	if names, ok := s.get(codeExecutorProcessedFilesKey).([]any); ok {
+		for _, n := range names {
+			if name, ok := n.(string); ok {
+				processed[name] = true
+			}
+		}
+	}
+	return processed
+}
+
+func (s *codeExecutorState) addProcessedFiles(names ...string) {
+	var all []any
+	if prev, ok := s.get(codeExecutorProcessedFilesKey).([]any); ok {
+		all = append(all, prev...)
+	}
+	for _, n := range names {
+		all = append(all, n)
+	}
+	s.delta[codeExecutorProcessedFilesKey] = all
+}
+
+func (s *codeExecutorState) inputFiles() []codeexecutor.File {
+	stored, _ := s.get(codeExecutorInputFilesKey).([]any)
+	files := make([]codeexecutor.File, 0, len(stored))
+	for _, v := range stored {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		encoded, _ := m["content"].(string)
+		mimeType, _ := m["mime_type"].(string)
+		content, err := base64.StdEncoding.DecodeString(encoded)
+		if name == "" || err != nil {
+			continue
+		}
+		files = append(files, codeexecutor.File{Name: name, Content: content, MIMEType: mimeType})
+	}
+	return files
+}
+
+func (s *codeExecutorState) addInputFiles(files ...codeexecutor.File) {
+	if len(files) == 0 {
+		return
+	}
+	var all []any
+	if prev, ok := s.get(codeExecutorInputFilesKey).([]any); ok {
+		all = append(all, prev...)
+	}
+	for _, f := range files {
+		all = append(all, map[string]any{
+			"name":      f.Name,
+			"content":   base64.StdEncoding.EncodeToString(f.Content),
+			"mime_type": f.MIMEType,
+		})
+	}
+	s.delta[codeExecutorInputFilesKey] = all
+}
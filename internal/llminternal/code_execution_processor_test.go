@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/codeexecutor"
+)
+
+func TestExtractCodeAndTruncateContent(t *testing.T) {
+	delimiters := codeexecutor.DefaultConfig().CodeBlockDelimiters
+
+	for _, tc := range []struct {
+		name      string
+		content   *genai.Content
+		wantCode  string
+		wantParts []*genai.Part
+	}{
+		{
+			name:    "nil content",
+			content: nil,
+		},
+		{
+			name:      "text without code",
+			content:   genai.NewContentFromText("hello", genai.RoleModel),
+			wantParts: []*genai.Part{genai.NewPartFromText("hello")},
+		},
+		{
+			name:     "code block in text",
+			content:  genai.NewContentFromText("Let me compute.\n```python\nprint(1+1)\n```\nThe answer is 3.", genai.RoleModel),
+			wantCode: "print(1+1)",
+			wantParts: []*genai.Part{
+				genai.NewPartFromText("Let me compute.\n"),
+				genai.NewPartFromExecutableCode("print(1+1)", genai.LanguagePython),
+			},
+		},
+		{
+			name:     "tool_code block",
+			content:  genai.NewContentFromText("```tool_code\nx = 1\nprint(x)\n```", genai.RoleModel),
+			wantCode: "x = 1\nprint(x)",
+			wantParts: []*genai.Part{
+				genai.NewPartFromExecutableCode("x = 1\nprint(x)", genai.LanguagePython),
+			},
+		},
+		{
+			name: "executable code part truncates the rest",
+			content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+				genai.NewPartFromText("run:"),
+				genai.NewPartFromExecutableCode("print(2)", genai.LanguagePython),
+				genai.NewPartFromText("hallucinated output"),
+			}},
+			wantCode: "print(2)",
+			wantParts: []*genai.Part{
+				genai.NewPartFromText("run:"),
+				genai.NewPartFromExecutableCode("print(2)", genai.LanguagePython),
+			},
+		},
+		{
+			name: "already executed code is skipped",
+			content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{
+				genai.NewPartFromExecutableCode("print(2)", genai.LanguagePython),
+				genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "2"),
+			}},
+			wantParts: []*genai.Part{
+				genai.NewPartFromExecutableCode("print(2)", genai.LanguagePython),
+				genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "2"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := extractCodeAndTruncateContent(tc.content, delimiters)
+			if got != tc.wantCode {
+				t.Errorf("extractCodeAndTruncateContent() = %q, want %q", got, tc.wantCode)
+			}
+			if tc.content == nil {
+				return
+			}
+			if diff := cmp.Diff(tc.wantParts, tc.content.Parts); diff != "" {
+				t.Errorf("content parts mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertCodeExecutionParts(t *testing.T) {
+	cfg := codeexecutor.DefaultConfig()
+
+	for _, tc := range []struct {
+		name    string
+		content *genai.Content
+		want    *genai.Content
+	}{
+		{
+			name:    "trailing executable code",
+			content: genai.NewContentFromExecutableCode("print(1)", genai.LanguagePython, genai.RoleModel),
+			want:    genai.NewContentFromText("```tool_code\nprint(1)\n```", genai.RoleModel),
+		},
+		{
+			name:    "code execution result",
+			content: genai.NewContentFromCodeExecutionResult(genai.OutcomeOK, "1", genai.RoleModel),
+			want:    genai.NewContentFromText("```tool_output\n1\n```", genai.RoleUser),
+		},
+		{
+			name:    "text is unchanged",
+			content: genai.NewContentFromText("hi", genai.RoleModel),
+			want:    genai.NewContentFromText("hi", genai.RoleModel),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			convertCodeExecutionParts(tc.content, cfg.CodeBlockDelimiters[0], cfg.ExecutionResultDelimiters)
+			if diff := cmp.Diff(tc.want, tc.content); diff != "" {
+				t.Errorf("convertCodeExecutionParts() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildCodeExecutionResultPart(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		result *codeexecutor.Result
+		want   *genai.Part
+	}{
+		{
+			name:   "stdout",
+			result: &codeexecutor.Result{Stdout: "2"},
+			want:   genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "Code execution result:\n2\n"),
+		},
+		{
+			name:   "stderr",
+			result: &codeexecutor.Result{Stdout: "partial", Stderr: "NameError"},
+			want:   genai.NewPartFromCodeExecutionResult(genai.OutcomeFailed, "NameError"),
+		},
+		{
+			name: "output files",
+			result: &codeexecutor.Result{OutputFiles: []codeexecutor.File{
+				{Name: "a.png"}, {Name: "b.csv"},
+			}},
+			want: genai.NewPartFromCodeExecutionResult(genai.OutcomeOK, "Saved artifacts:\n`a.png`,`b.csv`"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := buildCodeExecutionResultPart(tc.result)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("buildCodeExecutionResultPart() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNormalizedFileName(t *testing.T) {
+	for name, want := range map[string]string{
+		"data_1_2.csv":  "data_1_2",
+		"1-sales.csv":   "_1_sales",
+		"my file.v2.tx": "my_file_v2",
+	} {
+		if got := normalizedFileName(name); got != want {
+			t.Errorf("normalizedFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
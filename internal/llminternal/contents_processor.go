@@ -26,6 +26,7 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -64,6 +65,8 @@ func ContentsRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest
 // buildContentsDefault returns the contents for the LLM request by applying
 // filtering, rearrangement, and content processing to the given events.
 func buildContentsDefault(agentName, invocationBranch string, events []*session.Event) ([]*genai.Content, error) {
+	events = applyCompactions(events)
+
 	// parse the events, leaving the contents and the function calls and responses from the current agent.
 	var filtered []*session.Event
 	for _, ev := range events {
@@ -125,6 +128,87 @@ func buildContentsDefault(agentName, invocationBranch string, events []*session.
 	return contents, nil
 }
 
+// applyCompactions replaces the compacted events by the summary of their
+// compaction. The summary takes the place of the first compacted event.
+//
+// A compaction covered by a later one, e.g. a compaction superseded by a
+// token threshold compaction, is ignored.
+func applyCompactions(events []*session.Event) []*session.Event {
+	// reference: adk-python src/google/adk/flows/llm_flows/contents.py _process_compaction_events
+	// The compactions match the events by ID: the event timestamps may have a
+	// lower precision in some session stores.
+	var compactions []*session.Event
+	compactedBy := make(map[string]*session.Event)
+	for i, ev := range events {
+		c := ev.Actions.Compaction
+		if c == nil {
+			continue
+		}
+		covered := false
+		for _, later := range events[i+1:] {
+			if lc := later.Actions.Compaction; lc != nil && supersedes(lc, c) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			compactions = append(compactions, ev)
+			for _, id := range c.EventIDs {
+				compactedBy[id] = ev
+			}
+		}
+	}
+	if len(compactions) == 0 {
+		return events
+	}
+
+	emitted := make(map[*session.Event]bool)
+	var result []*session.Event
+	emit := func(ev *session.Event) {
+		if emitted[ev] {
+			return
+		}
+		emitted[ev] = true
+		summary := session.NewEvent(ev.InvocationID)
+		summary.ID = ev.ID
+		summary.Timestamp = ev.Actions.Compaction.EndTime
+		summary.Author = ev.Author
+		summary.Branch = ev.Branch
+		summary.Content = ev.Actions.Compaction.CompactedContent
+		result = append(result, summary)
+	}
+	for _, ev := range events {
+		if ev.Actions.Compaction != nil {
+			if slices.Contains(compactions, ev) {
+				// The compacted events may be missing, e.g. with a
+				// truncated history.
+				emit(ev)
+			}
+			continue
+		}
+		if c, ok := compactedBy[ev.ID]; ok {
+			emit(c)
+		} else {
+			result = append(result, ev)
+		}
+	}
+	return result
+}
+
+// supersedes reports whether the compaction c covers all the events of the
+// earlier compaction.
+func supersedes(c, earlier *session.EventCompaction) bool {
+	if len(earlier.EventIDs) == 0 {
+		return false
+	}
+	for _, id := range earlier.EventIDs {
+		if !slices.Contains(c.EventIDs, id) {
+			return false
+		}
+	}
+	return true
+}
+
 func eventBelongsToBranch(invocationBranch string, event *session.Event) bool {
 	if invocationBranch == "" || event.Branch == "" {
 		return true
@@ -505,20 +589,16 @@ func stringify(v any) string {
 	return string(s)
 }
 
-// requestEUCFunctionCallName is a special function to handle credential
-// request.
-const requestEUCFunctionCallName = "adk_request_credential"
-
 func isAuthEvent(ev *session.Event) bool {
 	c := utils.Content(ev)
 	if c == nil {
 		return false
 	}
 	for _, p := range c.Parts {
-		if p.FunctionCall != nil && p.FunctionCall.Name == requestEUCFunctionCallName {
+		if p.FunctionCall != nil && p.FunctionCall.Name == auth.FunctionCallName {
 			return true
 		}
-		if p.FunctionResponse != nil && p.FunctionResponse.Name == requestEUCFunctionCallName {
+		if p.FunctionResponse != nil && p.FunctionResponse.Name == auth.FunctionCallName {
 			return true
 		}
 	}
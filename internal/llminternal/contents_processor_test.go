@@ -15,6 +15,7 @@
 package llminternal_test
 
 import (
+	"fmt"
 	"iter"
 	"slices"
 	"strings"
@@ -971,3 +972,148 @@ var (
 	_ session.Session = (*fakeSession)(nil)
 	_ session.Events  = (*fakeSession)(nil)
 )
+
+func TestContentsRequestProcessor_Compaction(t *testing.T) {
+	const agentName = "testAgent"
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return base.Add(time.Duration(i) * time.Second) }
+	text := func(i int, author, role, text string) *session.Event {
+		return &session.Event{
+			ID:          fmt.Sprintf("e%d", i),
+			Timestamp:   at(i),
+			Author:      author,
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.Role(role))},
+		}
+	}
+	compaction := func(i, start, end int, summary string) *session.Event {
+		var ids []string
+		for k := start; k <= end; k++ {
+			ids = append(ids, fmt.Sprintf("e%d", k))
+		}
+		return &session.Event{
+			ID:        fmt.Sprintf("c%d", i),
+			Timestamp: at(i),
+			Author:    "user",
+			Actions: session.EventActions{Compaction: &session.EventCompaction{
+				StartTime:        at(start),
+				EndTime:          at(end),
+				EventIDs:         ids,
+				CompactedContent: genai.NewContentFromText(summary, genai.RoleModel),
+			}},
+		}
+	}
+	// sameTimestamp sets the timestamps of the events to the same value, as
+	// in the session stores with a low timestamp precision.
+	sameTimestamp := func(events ...*session.Event) []*session.Event {
+		for _, ev := range events {
+			ev.Timestamp = base
+			if c := ev.Actions.Compaction; c != nil {
+				c.StartTime, c.EndTime = base, base
+			}
+		}
+		return events
+	}
+
+	for _, tc := range []struct {
+		name   string
+		events []*session.Event
+		want   []*genai.Content
+	}{
+		{
+			name: "summary replaces compacted events",
+			events: []*session.Event{
+				text(1, "user", "user", "q1"),
+				text(2, agentName, "model", "a1"),
+				compaction(3, 1, 2, "summary 1-2"),
+				text(4, "user", "user", "q2"),
+			},
+			want: []*genai.Content{
+				genai.NewContentFromText("summary 1-2", genai.RoleModel),
+				genai.NewContentFromText("q2", genai.RoleUser),
+			},
+		},
+		{
+			name: "retained events after the compacted range",
+			events: []*session.Event{
+				text(1, "user", "user", "q1"),
+				text(2, agentName, "model", "a1"),
+				text(3, "user", "user", "q2"),
+				text(4, agentName, "model", "a2"),
+				compaction(5, 1, 2, "summary 1-2"),
+			},
+			want: []*genai.Content{
+				genai.NewContentFromText("summary 1-2", genai.RoleModel),
+				genai.NewContentFromText("q2", genai.RoleUser),
+				genai.NewContentFromText("a2", genai.RoleModel),
+			},
+		},
+		{
+			name: "overlapping compactions",
+			events: []*session.Event{
+				text(1, "user", "user", "q1"),
+				text(2, agentName, "model", "a1"),
+				compaction(3, 1, 2, "summary 1-2"),
+				text(4, "user", "user", "q2"),
+				text(5, agentName, "model", "a2"),
+				compaction(6, 2, 5, "summary 2-5"),
+				text(7, "user", "user", "q3"),
+			},
+			want: []*genai.Content{
+				genai.NewContentFromText("summary 1-2", genai.RoleModel),
+				genai.NewContentFromText("summary 2-5", genai.RoleModel),
+				genai.NewContentFromText("q3", genai.RoleUser),
+			},
+		},
+		{
+			name: "superseded compaction",
+			events: []*session.Event{
+				text(1, "user", "user", "q1"),
+				text(2, agentName, "model", "a1"),
+				compaction(3, 1, 2, "summary 1-2"),
+				text(4, "user", "user", "q2"),
+				compaction(5, 1, 4, "summary 1-4"),
+				text(6, "user", "user", "q3"),
+			},
+			want: []*genai.Content{
+				genai.NewContentFromText("summary 1-4", genai.RoleModel),
+				genai.NewContentFromText("q3", genai.RoleUser),
+			},
+		},
+		{
+			name: "same timestamps",
+			events: sameTimestamp(
+				text(1, "user", "user", "q1"),
+				text(2, agentName, "model", "a1"),
+				compaction(3, 1, 2, "summary 1-2"),
+				text(4, "user", "user", "q2"),
+				text(5, agentName, "model", "a2"),
+			),
+			want: []*genai.Content{
+				genai.NewContentFromText("summary 1-2", genai.RoleModel),
+				genai.NewContentFromText("q2", genai.RoleUser),
+				genai.NewContentFromText("a2", genai.RoleModel),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testAgent := utils.Must(llmagent.New(llmagent.Config{
+				Name:  agentName,
+				Model: &testModel{},
+			}))
+			ctx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{
+				Agent:   testAgent,
+				Session: &fakeSession{events: tc.events},
+			})
+
+			req := &model.LLMRequest{}
+			for _, err := range llminternal.ContentsRequestProcessor(ctx, req, &llminternal.Flow{}) {
+				if err != nil {
+					t.Fatalf("ContentsRequestProcessor failed: %v", err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, req.Contents); diff != "" {
+				t.Errorf("LLMRequest contents mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
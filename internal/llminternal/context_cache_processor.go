@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"iter"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/runconfig"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// contextCacheRequestProcessor enables the context caching of the request if
+// the app configures it. It passes the cache metadata and the prompt token
+// count of the previous request of the agent, for the model to reuse or
+// refresh the cache.
+func contextCacheRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
+	// reference: adk-python src/google/adk/flows/llm_flows/context_cache_processor.py
+	return func(yield func(*session.Event, error) bool) {
+		cfg := runconfig.FromContext(ctx)
+		if cfg == nil || cfg.ContextCacheConfig == nil {
+			return
+		}
+		req.CacheConfig = cfg.ContextCacheConfig
+
+		events := ctx.Session().Events()
+		agentName := ctx.Agent().Name()
+		for i := events.Len() - 1; i >= 0; i-- {
+			ev := events.At(i)
+			if ev.Author != agentName {
+				continue
+			}
+			if req.CacheableContentsTokenCount == 0 && ev.UsageMetadata != nil {
+				req.CacheableContentsTokenCount = ev.UsageMetadata.PromptTokenCount
+			}
+			if req.CacheMetadata == nil && ev.CacheMetadata != nil {
+				metadata := *ev.CacheMetadata
+				// The cache is used in one more invocation.
+				if metadata.CacheName != "" && ev.InvocationID != ctx.InvocationID() {
+					metadata.InvocationsUsed++
+				}
+				req.CacheMetadata = &metadata
+			}
+			if req.CacheMetadata != nil && req.CacheableContentsTokenCount > 0 {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/runconfig"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestContextCacheRequestProcessor(t *testing.T) {
+	cacheConfig := &model.ContextCacheConfig{CacheIntervals: 3}
+	expireTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cached := &model.CacheMetadata{
+		CacheName:       "cachedContents/1",
+		ExpireTime:      expireTime,
+		Fingerprint:     "abc",
+		InvocationsUsed: 1,
+		ContentsCount:   2,
+	}
+	fingerprintOnly := &model.CacheMetadata{Fingerprint: "abc", ContentsCount: 2}
+	event := func(invocationID, author string, metadata *model.CacheMetadata, promptTokens int32) *session.Event {
+		ev := session.NewEvent(invocationID)
+		ev.Author = author
+		ev.Content = genai.NewContentFromText("hello", genai.RoleModel)
+		ev.CacheMetadata = metadata
+		if promptTokens > 0 {
+			ev.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: promptTokens}
+		}
+		return ev
+	}
+
+	tests := []struct {
+		name        string
+		cacheConfig *model.ContextCacheConfig
+		events      []*session.Event
+		want        *model.LLMRequest
+	}{
+		{
+			name:   "caching disabled",
+			events: []*session.Event{event("inv-1", "agent", cached, 100)},
+			want:   &model.LLMRequest{},
+		},
+		{
+			name:        "no previous request",
+			cacheConfig: cacheConfig,
+			want:        &model.LLMRequest{CacheConfig: cacheConfig},
+		},
+		{
+			name:        "cache used in a new invocation",
+			cacheConfig: cacheConfig,
+			events:      []*session.Event{event("inv-1", "agent", cached, 100)},
+			want: &model.LLMRequest{
+				CacheConfig: cacheConfig,
+				CacheMetadata: &model.CacheMetadata{
+					CacheName:       "cachedContents/1",
+					ExpireTime:      expireTime,
+					Fingerprint:     "abc",
+					InvocationsUsed: 2,
+					ContentsCount:   2,
+				},
+				CacheableContentsTokenCount: 100,
+			},
+		},
+		{
+			name:        "cache used in the same invocation",
+			cacheConfig: cacheConfig,
+			events:      []*session.Event{event("inv-2", "agent", cached, 100)},
+			want: &model.LLMRequest{
+				CacheConfig:                 cacheConfig,
+				CacheMetadata:               cached,
+				CacheableContentsTokenCount: 100,
+			},
+		},
+		{
+			name:        "fingerprint only",
+			cacheConfig: cacheConfig,
+			events:      []*session.Event{event("inv-1", "agent", fingerprintOnly, 100)},
+			want: &model.LLMRequest{
+				CacheConfig:                 cacheConfig,
+				CacheMetadata:               fingerprintOnly,
+				CacheableContentsTokenCount: 100,
+			},
+		},
+		{
+			name:        "latest events of the agent",
+			cacheConfig: cacheConfig,
+			events: []*session.Event{
+				event("inv-1", "agent", fingerprintOnly, 50),
+				event("inv-1", "agent", cached, 0),
+				event("inv-1", "agent", nil, 100),
+				event("inv-2", "other_agent", &model.CacheMetadata{Fingerprint: "other"}, 300),
+			},
+			want: &model.LLMRequest{
+				CacheConfig: cacheConfig,
+				CacheMetadata: &model.CacheMetadata{
+					CacheName:       "cachedContents/1",
+					ExpireTime:      expireTime,
+					Fingerprint:     "abc",
+					InvocationsUsed: 2,
+					ContentsCount:   2,
+				},
+				CacheableContentsTokenCount: 100,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionService := session.InMemoryService()
+			created, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ev := range tt.events {
+				if err := sessionService.AppendEvent(t.Context(), created.Session, ev); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := sessionService.Get(t.Context(), &session.GetRequest{AppName: "app", UserID: "user", SessionID: created.Session.ID()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, err := agent.New(agent.Config{Name: "agent"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := icontext.NewInvocationContext(
+				runconfig.ToContext(t.Context(), &runconfig.RunConfig{ContextCacheConfig: tt.cacheConfig}),
+				icontext.InvocationContextParams{
+					Agent:        a,
+					Session:      got.Session,
+					InvocationID: "inv-2",
+				})
+
+			req := &model.LLMRequest{}
+			for _, err := range contextCacheRequestProcessor(ctx, req, &Flow{}) {
+				if err != nil {
+					t.Fatalf("contextCacheRequestProcessor() error = %v", err)
+				}
+			}
+			if diff := cmp.Diff(tt.want, req); diff != "" {
+				t.Errorf("contextCacheRequestProcessor() request mismatch (-want +got):\n%s", diff)
+			}
+			if cached.InvocationsUsed != 1 {
+				t.Errorf("contextCacheRequestProcessor() modified the event cache metadata")
+			}
+		})
+	}
+}
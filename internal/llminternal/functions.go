@@ -15,11 +15,16 @@
 package llminternal
 
 import (
+	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/converters"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -95,3 +100,48 @@ func generateRequestConfirmationEvent(
 		Actions:            session.EventActions{},
 	}
 }
+
+// generateAuthEvent creates a new Event containing adk_request_credential
+// function calls for the credentials requested by the tools with
+// tool.Context.RequestCredential.
+func generateAuthEvent(invocationContext agent.InvocationContext, functionResponseEvent *session.Event) (*session.Event, error) {
+	// reference: adk-python src/google/adk/flows/llm_flows/functions.py (generate_auth_event)
+	if functionResponseEvent == nil || len(functionResponseEvent.Actions.RequestedAuthConfigs) == 0 {
+		return nil, nil
+	}
+
+	var parts []*genai.Part
+	// Sort to emit the calls in a deterministic order.
+	for _, funcID := range slices.Sorted(maps.Keys(functionResponseEvent.Actions.RequestedAuthConfigs)) {
+		authConfig, err := converters.ToMapStructure(functionResponseEvent.Actions.RequestedAuthConfigs[funcID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert auth config of function call %q: %w", funcID, err)
+		}
+		parts = append(parts, &genai.Part{
+			FunctionCall: &genai.FunctionCall{
+				Name: auth.FunctionCallName,
+				Args: map[string]any{
+					"functionCallId": funcID,
+					"authConfig":     authConfig,
+				},
+			},
+		})
+	}
+
+	content := &genai.Content{
+		Parts: parts,
+		Role:  genai.RoleModel,
+	}
+	utils.PopulateClientFunctionCallID(content)
+	var longRunningToolIDs []string
+	for _, call := range utils.FunctionCalls(content) {
+		longRunningToolIDs = append(longRunningToolIDs, call.ID)
+	}
+
+	ev := session.NewEvent(invocationContext.InvocationID())
+	ev.Author = invocationContext.Agent().Name()
+	ev.Branch = invocationContext.Branch()
+	ev.LLMResponse = model.LLMResponse{Content: content}
+	ev.LongRunningToolIDs = longRunningToolIDs
+	return ev, nil
+}
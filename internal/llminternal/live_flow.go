@@ -0,0 +1,319 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// runLive runs the agent over a live connection to the model. The requests
+// of the queue are forwarded to the model, and the model responses are
+// yielded as events, until the queue is closed.
+func (f *Flow) runLive(ctx agent.InvocationContext, queue *agent.LiveRequestQueue) iter.Seq2[*session.Event, error] {
+	// reference: adk-python src/google/adk/flows/llm_flows/base_llm_flow.py BaseLlmFlow.run_live
+	return func(yield func(*session.Event, error) bool) {
+		if f.Model == nil {
+			yield(nil, fmt.Errorf("agent %q: %w", ctx.Agent().Name(), ErrModelNotConfigured))
+			return
+		}
+		liveModel, ok := f.Model.(model.LiveLLM)
+		if !ok {
+			yield(nil, fmt.Errorf("agent %q: model %q does not support live streaming", ctx.Agent().Name(), f.Model.Name()))
+			return
+		}
+		if queue == nil {
+			yield(nil, fmt.Errorf("live request queue is required in live streaming mode"))
+			return
+		}
+
+		req := &model.LLMRequest{
+			Model: f.Model.Name(),
+		}
+		for ev, err := range f.preprocess(ctx, req) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if ev != nil {
+				if !yield(ev, nil) {
+					return
+				}
+			}
+		}
+		if ctx.Ended() {
+			return
+		}
+		tools := make(map[string]tool.Tool)
+		for k, v := range req.Tools {
+			t, ok := v.(tool.Tool)
+			if !ok {
+				yield(nil, fmt.Errorf("unexpected tool type %T for tool %v", v, k))
+				return
+			}
+			tools[k] = t
+		}
+
+		conn, err := liveModel.Connect(ctx, req)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to connect to model: %w", err))
+			return
+		}
+		defer conn.Close()
+		conn = &syncConnection{LiveConnection: conn}
+		if len(req.Contents) > 0 {
+			if err := conn.SendHistory(ctx, req.Contents); err != nil {
+				yield(nil, fmt.Errorf("failed to send history: %w", err))
+				return
+			}
+		}
+
+		liveCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// The sender forwards the requests of the queue to the connection. It
+		// reports the user contents it sent, so they are added to the session.
+		userContents := make(chan *genai.Content)
+		sendErr := make(chan error, 1)
+		go func() {
+			sendErr <- sendToModel(liveCtx, conn, queue, userContents)
+		}()
+
+		type liveResponse struct {
+			resp *model.LLMResponse
+			err  error
+		}
+		responses := make(chan liveResponse)
+		go func() {
+			defer close(responses)
+			for resp, err := range conn.Receive(liveCtx) {
+				select {
+				case responses <- liveResponse{resp, err}:
+				case <-liveCtx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			var r liveResponse
+			select {
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			case content := <-userContents:
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Author = "user"
+				ev.Branch = ctx.Branch()
+				ev.LLMResponse = model.LLMResponse{Content: content}
+				if !yield(ev, nil) {
+					return
+				}
+				continue
+			case resp, ok := <-responses:
+				if !ok {
+					// The connection was closed, either by the sender or the model.
+					cancel()
+					if err := <-sendErr; err != nil {
+						yield(nil, err)
+					}
+					return
+				}
+				r = resp
+			}
+			if r.err != nil {
+				yield(nil, r.err)
+				return
+			}
+			resp := r.resp
+
+			for ev, err := range f.postprocess(ctx, req, resp) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(ev, nil) {
+					return
+				}
+			}
+			if isEmptyLiveResponse(resp) {
+				continue
+			}
+
+			ev := f.finalizeModelResponseEvent(ctx, resp, tools, nil)
+			if resp.InputTranscription != nil {
+				ev.Author = "user"
+			}
+			if !yield(ev, nil) {
+				return
+			}
+
+			fnResponseEvent, err := f.handleFunctionCalls(ctx, tools, resp, nil)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if fnResponseEvent == nil {
+				continue
+			}
+			if !yield(fnResponseEvent, nil) {
+				return
+			}
+
+			if fnResponseEvent.Actions.TransferToAgent == "" {
+				// Answer the function calls of the model.
+				if err := conn.SendContent(ctx, fnResponseEvent.Content); err != nil {
+					yield(nil, fmt.Errorf("failed to send function responses: %w", err))
+					return
+				}
+				continue
+			}
+			// Stop using the connection before the next agent opens its own
+			// over the same queue.
+			cancel()
+			<-sendErr
+			conn.Close()
+			nextAgent := f.agentToRun(ctx, fnResponseEvent.Actions.TransferToAgent)
+			if nextAgent == nil {
+				yield(nil, fmt.Errorf("failed to find agent: %s", fnResponseEvent.Actions.TransferToAgent))
+				return
+			}
+			for ev, err := range nextAgent.Run(ctx) {
+				if !yield(ev, err) || err != nil { // forward
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
+// sendToModel forwards the requests of the queue to the connection until the
+// queue is closed or the context is done. The user contents sent to the
+// model are reported to userContents.
+func sendToModel(ctx context.Context, conn model.LiveConnection, queue *agent.LiveRequestQueue, userContents chan<- *genai.Content) error {
+	for {
+		req, err := queue.Receive(ctx)
+		if err != nil {
+			return nil
+		}
+		if req.Close {
+			return conn.Close()
+		}
+		if err := sendLiveRequest(ctx, conn, req); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to send live request: %w", err)
+		}
+		if req.Content == nil || len(utils.FunctionResponses(req.Content)) > 0 {
+			// Function responses are already in the session.
+			continue
+		}
+		content := req.Content
+		if content.Role == "" {
+			// The content belongs to the caller.
+			withRole := *content
+			withRole.Role = genai.RoleUser
+			content = &withRole
+		}
+		select {
+		case userContents <- content:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// syncConnection serializes the sends to the connection, made by the sender
+// and by the flow answering the function calls.
+type syncConnection struct {
+	model.LiveConnection
+	mu sync.Mutex
+}
+
+func (c *syncConnection) SendHistory(ctx context.Context, history []*genai.Content) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.LiveConnection.SendHistory(ctx, history)
+}
+
+func (c *syncConnection) SendContent(ctx context.Context, content *genai.Content) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.LiveConnection.SendContent(ctx, content)
+}
+
+func (c *syncConnection) SendRealtime(ctx context.Context, input genai.LiveRealtimeInput) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.LiveConnection.SendRealtime(ctx, input)
+}
+
+func sendLiveRequest(ctx context.Context, conn model.LiveConnection, req *agent.LiveRequest) error {
+	if req.ActivityStart {
+		if err := conn.SendRealtime(ctx, genai.LiveRealtimeInput{ActivityStart: &genai.ActivityStart{}}); err != nil {
+			return err
+		}
+	}
+	if req.ActivityEnd {
+		if err := conn.SendRealtime(ctx, genai.LiveRealtimeInput{ActivityEnd: &genai.ActivityEnd{}}); err != nil {
+			return err
+		}
+	}
+	if req.Blob != nil {
+		if err := conn.SendRealtime(ctx, realtimeBlobInput(req.Blob)); err != nil {
+			return err
+		}
+	}
+	if req.Content != nil {
+		if err := conn.SendContent(ctx, req.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// realtimeBlobInput returns the realtime input carrying the blob, based on
+// its MIME type.
+func realtimeBlobInput(blob *genai.Blob) genai.LiveRealtimeInput {
+	switch {
+	case strings.HasPrefix(blob.MIMEType, "audio/"):
+		return genai.LiveRealtimeInput{Audio: blob}
+	case strings.HasPrefix(blob.MIMEType, "image/"), strings.HasPrefix(blob.MIMEType, "video/"):
+		return genai.LiveRealtimeInput{Video: blob}
+	default:
+		return genai.LiveRealtimeInput{Media: blob}
+	}
+}
+
+// isEmptyLiveResponse reports whether the live response carries nothing to
+// yield as an event.
+func isEmptyLiveResponse(resp *model.LLMResponse) bool {
+	return resp.Content == nil && resp.ErrorCode == "" && !resp.Interrupted && !resp.TurnComplete &&
+		resp.InputTranscription == nil && resp.OutputTranscription == nil && resp.UsageMetadata == nil
+}
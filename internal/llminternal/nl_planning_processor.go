@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"fmt"
+	"iter"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
+	"google.golang.org/adk/session"
+)
+
+// nlPlanningRequestProcessor lets the agent's planner extend the request and
+// unmarks the thoughts of the previous planning responses, so the model sees
+// its earlier plan and reasoning.
+func nlPlanningRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
+	// reference: adk-python src/google/adk/flows/llm_flows/_nl_planning.py
+	return func(yield func(*session.Event, error) bool) {
+		p := agentPlanner(ctx)
+		if p == nil {
+			return
+		}
+		instruction, err := p.BuildPlanningInstruction(icontext.NewReadonlyContext(ctx), req)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to build planning instruction: %w", err))
+			return
+		}
+		if instruction != "" {
+			utils.AppendInstructions(req, instruction)
+		}
+		for _, content := range req.Contents {
+			if content == nil {
+				continue
+			}
+			for _, part := range content.Parts {
+				if part != nil {
+					part.Thought = false
+				}
+			}
+		}
+	}
+}
+
+// nlPlanningResponseProcessor lets the agent's planner rewrite the response
+// parts. State changes made by the planner are yielded as a separate event.
+func nlPlanningResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if resp == nil || resp.Content == nil || len(resp.Content.Parts) == 0 {
+			return
+		}
+		p := agentPlanner(ctx)
+		if p == nil {
+			return
+		}
+		stateDelta := make(map[string]any)
+		cctx := icontext.NewCallbackContextWithDelta(ctx, stateDelta)
+		parts, err := p.ProcessPlanningResponse(cctx, resp.Content.Parts)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to process planning response: %w", err))
+			return
+		}
+		if parts != nil {
+			resp.Content.Parts = parts
+		}
+		if len(stateDelta) > 0 {
+			ev := session.NewEvent(ctx.InvocationID())
+			ev.Author = ctx.Agent().Name()
+			ev.Branch = ctx.Branch()
+			ev.Actions.StateDelta = stateDelta
+			yield(ev, nil)
+		}
+	}
+}
+
+func agentPlanner(ctx agent.InvocationContext) planner.Planner {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil {
+		return nil
+	}
+	return llmAgent.internal().Planner
+}
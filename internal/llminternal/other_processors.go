@@ -26,28 +26,3 @@ func identityRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest
 	// TODO: implement (adk-python src/google/adk/flows/llm_flows/identity.py)
 	return func(yield func(*session.Event, error) bool) {}
 }
-
-func nlPlanningRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
-	// TODO: implement (adk-python src/google/adk/flows/llm_flows/_nl_plnning.py)
-	return func(yield func(*session.Event, error) bool) {}
-}
-
-func codeExecutionRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
-	// TODO: implement (adk-python src/google/adk/flows/llm_flows/_code_execution.py)
-	return func(yield func(*session.Event, error) bool) {}
-}
-
-func authPreprocessor(ctx agent.InvocationContext, req *model.LLMRequest, f *Flow) iter.Seq2[*session.Event, error] {
-	// TODO: implement (adk-python src/google/adk/auth/auth_preprocessor.py)
-	return func(yield func(*session.Event, error) bool) {}
-}
-
-func nlPlanningResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error {
-	// TODO: implement (adk-python src/google/adk/_nl_planning.py)
-	return nil
-}
-
-func codeExecutionResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error {
-	// TODO: implement (adk-python src/google/adk_code_execution.py)
-	return nil
-}
@@ -112,7 +112,11 @@ func (s *streamingResponseAggregator) Close() *model.LLMResponse {
 	return s.createAggregateResponse()
 }
 
-func (s *streamingResponseAggregator) createAggregateResponse() *model.LLMResponse {
+// Partial returns the response aggregating the text of the partial
+// responses processed since the last aggregated response, or nil if there
+// is none. Unlike Close, it does not reset the aggregator, e.g. to inspect
+// a stream which ended with partial responses.
+func (s *streamingResponseAggregator) Partial() *model.LLMResponse {
 	if (s.text != "" || s.thoughtText != "") && s.response != nil {
 		var parts []*genai.Part
 		if s.thoughtText != "" {
@@ -122,7 +126,7 @@ func (s *streamingResponseAggregator) createAggregateResponse() *model.LLMRespon
 			parts = append(parts, &genai.Part{Text: s.text, Thought: false})
 		}
 
-		response := &model.LLMResponse{
+		return &model.LLMResponse{
 			Content:           &genai.Content{Parts: parts, Role: s.role},
 			ErrorCode:         s.response.ErrorCode,
 			ErrorMessage:      s.response.ErrorMessage,
@@ -130,13 +134,16 @@ func (s *streamingResponseAggregator) createAggregateResponse() *model.LLMRespon
 			GroundingMetadata: s.response.GroundingMetadata,
 			FinishReason:      s.response.FinishReason,
 		}
-		s.clear()
-		return response
 	}
-	s.clear()
 	return nil
 }
 
+func (s *streamingResponseAggregator) createAggregateResponse() *model.LLMResponse {
+	response := s.Partial()
+	s.clear()
+	return response
+}
+
 func (s *streamingResponseAggregator) clear() {
 	s.response = nil
 	s.text = ""
@@ -43,6 +43,7 @@ func TestMemory_AddAndSearch(t *testing.T) {
 
 	events := []*session.Event{
 		{
+			ID:        "event1",
 			Timestamp: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
 			Author:    "user1",
 			LLMResponse: model.LLMResponse{
@@ -50,6 +51,7 @@ func TestMemory_AddAndSearch(t *testing.T) {
 			},
 		},
 		{
+			ID:        "event2",
 			Timestamp: time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC),
 			Author:    "user1",
 			LLMResponse: model.LLMResponse{
@@ -79,11 +81,13 @@ func TestMemory_AddAndSearch(t *testing.T) {
 
 	// Expected MemoryEntry items
 	entry1 := memory.Entry{
+		ID:        memory.EntryID(sessionID, "event1"),
 		Content:   content1,
 		Author:    "user1",
 		Timestamp: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
 	}
 	entry2 := memory.Entry{
+		ID:        memory.EntryID(sessionID, "event2"),
 		Content:   content2,
 		Author:    "user1",
 		Timestamp: time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC),
@@ -21,6 +21,7 @@ package telemetry
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -61,6 +62,13 @@ const (
 	gcpVertexAgentLLMResponseName  = "gcp.vertex.agent.llm_response"
 	gcpVertexAgentInvocationID     = "gcp.vertex.agent.invocation_id"
 	gcpVertexAgentSessionID        = "gcp.vertex.agent.session_id"
+	gcpVertexAgentRetryAttempt     = "gcp.vertex.agent.retry.attempt"
+	gcpVertexAgentRetryDelay       = "gcp.vertex.agent.retry.delay_ms"
+	gcpVertexAgentRetryError       = "gcp.vertex.agent.retry.error"
+	gcpVertexAgentRateLimitWait    = "gcp.vertex.agent.rate_limit.wait_ms"
+
+	llmRetryEventName      = "llm_retry"
+	rateLimitWaitEventName = "rate_limit_wait"
 
 	executeToolName = "execute_tool"
 	mergeToolName   = "(merged tools)"
@@ -174,6 +182,24 @@ func TraceLLMCall(span trace.Span, agentCtx agent.InvocationContext, llmRequest
 	span.SetAttributes(attributes...)
 }
 
+// TraceLLMRetry records the retry of a failed model call as an event of the
+// call_llm span.
+func TraceLLMRetry(span trace.Span, attempt int, delay time.Duration, err error) {
+	span.AddEvent(llmRetryEventName, trace.WithAttributes(
+		attribute.Int(gcpVertexAgentRetryAttempt, attempt),
+		attribute.Int64(gcpVertexAgentRetryDelay, delay.Milliseconds()),
+		attribute.String(gcpVertexAgentRetryError, err.Error()),
+	))
+}
+
+// TraceRateLimitWait records the wait of a rate limited model call as an
+// event of the call_llm span.
+func TraceRateLimitWait(span trace.Span, wait time.Duration) {
+	span.AddEvent(rateLimitWaitEventName, trace.WithAttributes(
+		attribute.Int64(gcpVertexAgentRateLimitWait, wait.Milliseconds()),
+	))
+}
+
 func safeSerialize(obj any) string {
 	dump, err := json.Marshal(obj)
 	if err != nil {
@@ -23,6 +23,8 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/authinternal"
 	contextinternal "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
@@ -131,3 +133,53 @@ func (c *toolContext) RequestConfirmation(hint string, payload any) error {
 	c.eventActions.SkipSummarization = true
 	return nil
 }
+
+func (c *toolContext) RequestCredential(cfg *auth.AuthConfig) error {
+	if c.functionCallID == "" {
+		return fmt.Errorf("error function call id not set when requesting credential for tool")
+	}
+	req, err := auth.GenerateAuthRequest(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to generate auth request: %w", err)
+	}
+	if c.eventActions.RequestedAuthConfigs == nil {
+		c.eventActions.RequestedAuthConfigs = make(map[string]auth.AuthConfig)
+	}
+	c.eventActions.RequestedAuthConfigs[c.functionCallID] = *req
+	// As for RequestConfirmation, stop the agent loop until the client posts
+	// back the credential.
+	c.eventActions.SkipSummarization = true
+	return nil
+}
+
+func (c *toolContext) AuthResponse(cfg *auth.AuthConfig) *auth.AuthCredential {
+	return authinternal.AuthResponse(c.invocationContext, cfg)
+}
+
+func (c *toolContext) LoadCredential(ctx context.Context, cfg *auth.AuthConfig) (*auth.AuthCredential, error) {
+	svc := authinternal.FromContext(c.invocationContext)
+	if svc == nil {
+		return nil, nil
+	}
+	resp, err := svc.Load(ctx, &auth.LoadRequest{
+		AppName:    c.AppName(),
+		UserID:     c.UserID(),
+		AuthConfig: cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Credential, nil
+}
+
+func (c *toolContext) SaveCredential(ctx context.Context, cfg *auth.AuthConfig) error {
+	svc := authinternal.FromContext(c.invocationContext)
+	if svc == nil {
+		return fmt.Errorf("credential service is not set")
+	}
+	return svc.Save(ctx, &auth.SaveRequest{
+		AppName:    c.AppName(),
+		UserID:     c.UserID(),
+		AuthConfig: cfg,
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolutils
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/authinternal"
+	"google.golang.org/adk/tool"
+)
+
+// tokenExpiryMargin avoids using a token expiring during the call.
+const tokenExpiryMargin = time.Minute
+
+// credentialStore loads and saves the credentials of the current user.
+type credentialStore interface {
+	LoadCredential(ctx context.Context, cfg *auth.AuthConfig) (*auth.AuthCredential, error)
+	SaveCredential(ctx context.Context, cfg *auth.AuthConfig) error
+}
+
+// ResolveCredential returns the credential a tool uses to call its service.
+//
+// Credentials which don't need the end user's consent are exchanged right
+// away. The exchanged tokens are stored in the credential service, and
+// exchanged again only once expired. Otherwise the credential posted back
+// by the client or stored in the credential service is used, and saved for
+// later invocations. If there is none, the credential is requested from the
+// client with tool.Context.RequestCredential and ResolveCredential returns
+// nil: the tool should then tell the model the authorization is pending.
+func ResolveCredential(ctx tool.Context, cfg *auth.AuthConfig) (*auth.AuthCredential, error) {
+	// reference: adk-python src/google/adk/tools/_authenticated_function_tool.py
+	if !cfg.RequiresUserAuthorization() {
+		return exchange(ctx, ctx, cfg)
+	}
+
+	if cred := ctx.AuthResponse(cfg); cred != nil {
+		if err := saveCredential(ctx, ctx, cfg, cred); err != nil {
+			return nil, err
+		}
+		return cred, nil
+	}
+
+	cred, err := loadStored(ctx, ctx, cfg)
+	if err != nil || cred != nil {
+		return cred, err
+	}
+	return nil, ctx.RequestCredential(cfg)
+}
+
+// LoadCredential returns the credential used outside of a tool call, e.g. to
+// list the tools of a tool set. It is resolved as by ResolveCredential,
+// except that the end user's consent is never requested: if there is no
+// stored credential for the user, LoadCredential returns nil.
+func LoadCredential(ctx agent.ReadonlyContext, cfg *auth.AuthConfig) (*auth.AuthCredential, error) {
+	store := &readonlyStore{ctx: ctx}
+	if !cfg.RequiresUserAuthorization() {
+		return exchange(ctx, store, cfg)
+	}
+	return loadStored(ctx, store, cfg)
+}
+
+// exchange returns the credential of a config which doesn't need the end
+// user's consent. The tokens obtained from service accounts and OAuth2
+// client credentials are reused until they expire.
+func exchange(ctx context.Context, store credentialStore, cfg *auth.AuthConfig) (*auth.AuthCredential, error) {
+	if !needsExchange(cfg) {
+		return auth.Exchange(ctx, cfg)
+	}
+	stored, err := store.LoadCredential(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil && unexpired(stored) {
+		return stored, nil
+	}
+	cred, err := auth.Exchange(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveCredential(ctx, store, cfg, cred); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// loadStored returns the credential stored for a config needing the end
+// user's consent, or nil if there is none.
+func loadStored(ctx context.Context, store credentialStore, cfg *auth.AuthConfig) (*auth.AuthCredential, error) {
+	stored, err := store.LoadCredential(ctx, cfg)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+	withStored := *cfg
+	withStored.ExchangedAuthCredential = stored
+	// Refreshes the access token if it expired. If that fails, e.g. the
+	// refresh token was revoked, the user is asked again.
+	cred, err := auth.Exchange(ctx, &withStored)
+	if err != nil {
+		return nil, nil
+	}
+	if cred.OAuth2 != nil && stored.OAuth2 != nil && cred.OAuth2.AccessToken != stored.OAuth2.AccessToken {
+		if err := saveCredential(ctx, store, cfg, cred); err != nil {
+			return nil, err
+		}
+	}
+	return cred, nil
+}
+
+// needsExchange reports whether the config holds a credential exchanged for
+// a token by auth.Exchange.
+func needsExchange(cfg *auth.AuthConfig) bool {
+	if cfg.ExchangedAuthCredential != nil || cfg.RawAuthCredential == nil {
+		return false
+	}
+	switch cfg.RawAuthCredential.AuthType {
+	case auth.CredentialTypeServiceAccount, auth.CredentialTypeOAuth2:
+		return true
+	default:
+		return false
+	}
+}
+
+// unexpired reports whether the token of the exchanged credential can still
+// be used.
+func unexpired(cred *auth.AuthCredential) bool {
+	var expiresAt int64
+	switch {
+	case cred.OAuth2 != nil && cred.OAuth2.AccessToken != "":
+		expiresAt = cred.OAuth2.ExpiresAt
+	case cred.HTTP != nil:
+		expiresAt = cred.HTTP.ExpiresAt
+	default:
+		return false
+	}
+	return expiresAt == 0 || time.Now().Add(tokenExpiryMargin).Unix() < expiresAt
+}
+
+// saveCredential stores the credential if a credential service is configured.
+func saveCredential(ctx context.Context, store credentialStore, cfg *auth.AuthConfig, cred *auth.AuthCredential) error {
+	if authinternal.FromContext(ctx) == nil {
+		return nil
+	}
+	withCred := *cfg
+	withCred.ExchangedAuthCredential = cred
+	return store.SaveCredential(ctx, &withCred)
+}
+
+// readonlyStore is the credentialStore of a context outside of a tool call.
+type readonlyStore struct {
+	ctx agent.ReadonlyContext
+}
+
+func (s *readonlyStore) LoadCredential(ctx context.Context, cfg *auth.AuthConfig) (*auth.AuthCredential, error) {
+	svc := authinternal.FromContext(s.ctx)
+	if svc == nil {
+		return nil, nil
+	}
+	resp, err := svc.Load(ctx, &auth.LoadRequest{AppName: s.ctx.AppName(), UserID: s.ctx.UserID(), AuthConfig: cfg})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Credential, nil
+}
+
+func (s *readonlyStore) SaveCredential(ctx context.Context, cfg *auth.AuthConfig) error {
+	svc := authinternal.FromContext(s.ctx)
+	if svc == nil {
+		return nil
+	}
+	return svc.Save(ctx, &auth.SaveRequest{AppName: s.ctx.AppName(), UserID: s.ctx.UserID(), AuthConfig: cfg})
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolutils_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/authinternal"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/session"
+)
+
+func TestResolveCredential_ReusesExchangedToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		expiresIn     int
+		withService   bool
+		wantExchanges int
+	}{
+		{
+			name:          "token reused until expiry",
+			expiresIn:     3600,
+			withService:   true,
+			wantExchanges: 1,
+		},
+		{
+			name:          "expiring token exchanged again",
+			expiresIn:     30,
+			withService:   true,
+			wantExchanges: 3,
+		},
+		{
+			name:          "no credential service",
+			expiresIn:     3600,
+			wantExchanges: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var exchanges int
+			tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				exchanges++
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, exchanges, tt.expiresIn)
+			}))
+			defer tokenServer.Close()
+
+			cfg := &auth.AuthConfig{
+				AuthScheme: &auth.AuthScheme{
+					Type:  auth.SchemeTypeOAuth2,
+					Flows: &auth.OAuthFlows{ClientCredentials: &auth.OAuthFlow{TokenURL: tokenServer.URL}},
+				},
+				RawAuthCredential: &auth.AuthCredential{
+					AuthType: auth.CredentialTypeOAuth2,
+					OAuth2:   &auth.OAuth2Auth{ClientID: "client-id", ClientSecret: "client-secret"},
+				},
+			}
+			ctx := t.Context()
+			if tt.withService {
+				ctx = authinternal.ToContext(ctx, auth.InMemoryCredentialService())
+			}
+			sessionService := session.InMemoryService()
+			created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			invCtx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{Session: created.Session})
+
+			for range 3 {
+				cred, err := toolutils.ResolveCredential(toolinternal.NewToolContext(invCtx, "fn1", nil, nil), cfg)
+				if err != nil {
+					t.Fatalf("ResolveCredential() failed: %v", err)
+				}
+				if cred == nil || cred.OAuth2 == nil || cred.OAuth2.AccessToken != fmt.Sprintf("token-%d", exchanges) {
+					t.Fatalf("ResolveCredential() = %+v, want the last exchanged token", cred)
+				}
+			}
+			if exchanges != tt.wantExchanges {
+				t.Errorf("token exchanged %d times, want %d", exchanges, tt.wantExchanges)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package database provides a [memory.Service] that persists memories in a
+// relational database via the GORM library and answers queries with the
+// database full-text search.
+package database
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genai"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+// databaseService is a database implementation of memory.Service.
+type databaseService struct {
+	db *gorm.DB
+}
+
+// NewMemoryService creates a new [memory.Service] implementation that uses a
+// relational database (e.g., PostgreSQL, SQLite) via the GORM library.
+//
+// Search uses FTS5 on SQLite and a tsvector column on PostgreSQL. Other
+// databases fall back to matching any query word with LIKE.
+//
+// It requires a [gorm.Dialector] to specify the database connection and
+// accepts optional [gorm.Option] values for further GORM configuration.
+//
+// The returned service also implements [memory.Manager].
+//
+// It returns the new [memory.Service] or an error if the database connection
+// [gorm.Open] fails.
+func NewMemoryService(dialector gorm.Dialector, opts ...gorm.Option) (memory.Service, error) {
+	db, err := gorm.Open(dialector, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating database memory service: %w", err)
+	}
+	return &databaseService{db: db}, nil
+}
+
+// AutoMigrate runs the GORM auto-migration tool to ensure the database schema
+// matches the internal storage model, and creates the full-text search index
+// for the database dialect.
+//
+// NOTE: This function relies on a type assertion to the concrete *databaseService
+// implementation. It will return an error if the provided memory.Service is
+// a different implementation.
+func AutoMigrate(service memory.Service) error {
+	dbservice, ok := service.(*databaseService)
+	if !ok {
+		return fmt.Errorf("invalid memory service type")
+	}
+	err := dbservice.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&storageMemory{}); err != nil {
+			return err
+		}
+		for _, stmt := range searchIndexStatements(tx.Dialector.Name()) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("auto migrate failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserMemories deletes all memories of the given user, e.g. to serve
+// a GDPR erasure request. Unlike [memory.Manager.Delete], it does not keep
+// any record of the deleted memories.
+//
+// NOTE: This function relies on a type assertion to the concrete *databaseService
+// implementation. It will return an error if the provided memory.Service is
+// a different implementation.
+func DeleteUserMemories(ctx context.Context, service memory.Service, appName, userID string) error {
+	dbservice, ok := service.(*databaseService)
+	if !ok {
+		return fmt.Errorf("invalid memory service type")
+	}
+	if appName == "" || userID == "" {
+		return fmt.Errorf("app_name and user_id are required")
+	}
+	err := dbservice.db.WithContext(ctx).
+		Where("app_name = ? AND user_id = ?", appName, userID).
+		Delete(&storageMemory{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete memories: %w", err)
+	}
+	return nil
+}
+
+// AddSession stores the text events of the session, implements memory.Service.
+//
+// Adding the same session again updates its previously stored memories and
+// stores the new ones. The memories deleted or updated via memory.Manager are
+// kept as they are.
+func (s *databaseService) AddSession(ctx context.Context, curSession session.Session) error {
+	var rows []*storageMemory
+	events := curSession.Events()
+	for i := range events.Len() {
+		event := events.At(i)
+		if event.LLMResponse.Content == nil {
+			continue
+		}
+		text := contentText(event.LLMResponse.Content)
+		if text == "" {
+			continue
+		}
+		content, err := json.Marshal(event.LLMResponse.Content)
+		if err != nil {
+			return fmt.Errorf("failed to marshal content of event %q: %w", event.ID, err)
+		}
+		rows = append(rows, &storageMemory{
+			AppName:   curSession.AppName(),
+			UserID:    curSession.UserID(),
+			SessionID: curSession.ID(),
+			EventID:   event.ID,
+			// Events without an ID are identified by their position.
+			EntryID:   memory.EntryID(curSession.ID(), cmp.Or(event.ID, strconv.Itoa(i))),
+			Author:    event.Author,
+			Content:   string(content),
+			Text:      text,
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []storageMemory
+		err := tx.Where("app_name = ? AND user_id = ? AND session_id = ?", curSession.AppName(), curSession.UserID(), curSession.ID()).
+			Find(&existing).Error
+		if err != nil {
+			return fmt.Errorf("failed to load previous memories of session %q: %w", curSession.ID(), err)
+		}
+		stored := make(map[string]storageMemory, len(existing))
+		for _, row := range existing {
+			stored[row.EntryID] = row
+		}
+
+		var added []*storageMemory
+		for _, row := range rows {
+			prev, ok := stored[row.EntryID]
+			if !ok {
+				added = append(added, row)
+				continue
+			}
+			if prev.Deleted || prev.Edited || (prev.Content == row.Content && prev.Author == row.Author) {
+				continue
+			}
+			err := tx.Model(&storageMemory{}).Where("id = ?", prev.ID).
+				Updates(map[string]any{"author": row.Author, "content": row.Content, "text": row.Text, "timestamp": row.Timestamp}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update memory of event %q: %w", row.EventID, err)
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		// A concurrent call may have stored the same events meanwhile.
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(added, 100).Error; err != nil {
+			return fmt.Errorf("failed to save memories of session %q: %w", curSession.ID(), err)
+		}
+		return nil
+	})
+}
+
+// Search returns the memories matching any word of the query, best matches
+// first, implements memory.Service.
+func (s *databaseService) Search(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	words := queryWords(req.Query)
+	if len(words) == 0 {
+		return &memory.SearchResponse{}, nil
+	}
+
+	var rows []storageMemory
+	query := s.db.WithContext(ctx).Model(&storageMemory{}).
+		Where("memories.app_name = ? AND memories.user_id = ? AND memories.deleted = ?", req.AppName, req.UserID, false)
+	if err := searchQuery(query, words).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search memories: %w", err)
+	}
+
+	res := &memory.SearchResponse{}
+	for _, row := range rows {
+		entry, err := row.entry()
+		if err != nil {
+			return nil, err
+		}
+		res.Memories = append(res.Memories, entry)
+	}
+	return res, nil
+}
+
+// List returns the memories of the user, oldest first, implements
+// memory.Manager.
+func (s *databaseService) List(ctx context.Context, req *memory.ListRequest) (*memory.ListResponse, error) {
+	var rows []storageMemory
+	err := s.db.WithContext(ctx).
+		Where("app_name = ? AND user_id = ? AND deleted = ?", req.AppName, req.UserID, false).
+		Order("timestamp").Order("id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memories: %w", err)
+	}
+
+	res := &memory.ListResponse{}
+	for _, row := range rows {
+		entry, err := row.entry()
+		if err != nil {
+			return nil, err
+		}
+		res.Memories = append(res.Memories, entry)
+	}
+	return res, nil
+}
+
+// Delete deletes a memory of the user, implements memory.Manager.
+func (s *databaseService) Delete(ctx context.Context, req *memory.DeleteRequest) error {
+	result := s.db.WithContext(ctx).Model(&storageMemory{}).
+		Where("app_name = ? AND user_id = ? AND entry_id = ? AND deleted = ?", req.AppName, req.UserID, req.ID, false).
+		Updates(deletedFields())
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete memory: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %q", memory.ErrEntryNotFound, req.ID)
+	}
+	return nil
+}
+
+// DeleteSession deletes the memories added from a session, implements
+// memory.Manager.
+func (s *databaseService) DeleteSession(ctx context.Context, req *memory.DeleteSessionRequest) error {
+	err := s.db.WithContext(ctx).Model(&storageMemory{}).
+		Where("app_name = ? AND user_id = ? AND session_id = ? AND deleted = ?", req.AppName, req.UserID, req.SessionID, false).
+		Updates(deletedFields()).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete memories of session %q: %w", req.SessionID, err)
+	}
+	return nil
+}
+
+// Update replaces the content of a memory of the user, implements
+// memory.Manager.
+func (s *databaseService) Update(ctx context.Context, req *memory.UpdateRequest) error {
+	if req.Content == nil {
+		return errors.New("content is required")
+	}
+	text := contentText(req.Content)
+	if text == "" {
+		return errors.New("content has no text")
+	}
+	content, err := json.Marshal(req.Content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal content: %w", err)
+	}
+	result := s.db.WithContext(ctx).Model(&storageMemory{}).
+		Where("app_name = ? AND user_id = ? AND entry_id = ? AND deleted = ?", req.AppName, req.UserID, req.ID, false).
+		Updates(map[string]any{"content": string(content), "text": text, "edited": true})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update memory: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %q", memory.ErrEntryNotFound, req.ID)
+	}
+	return nil
+}
+
+// deletedFields returns the column values of a deleted memory, which keep no
+// content.
+func deletedFields() map[string]any {
+	return map[string]any{"content": "", "text": "", "edited": false, "deleted": true}
+}
+
+// contentText joins the text parts of the content.
+func contentText(content *genai.Content) string {
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
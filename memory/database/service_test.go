@@ -0,0 +1,372 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"errors"
+	"iter"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func Test_databaseService_Search(t *testing.T) {
+	sessions := []session.Session{
+		makeSession("app1", "user1", "sess1", []*session.Event{
+			textEvent("e1", "user1", "The Quick brown fox", 1),
+			textEvent("e2", "bot", "jumps over the lazy dog", 2),
+			{ID: "e3", Author: "bot", Timestamp: ts(3)},
+		}),
+		makeSession("app1", "user1", "sess2", []*session.Event{
+			textEvent("e4", "test-bot", "hello world, hello everyone", 4),
+		}),
+		makeSession("app1", "user2", "sess3", []*session.Event{
+			textEvent("e5", "user2", "hello from another user", 5),
+		}),
+	}
+
+	tests := []struct {
+		name     string
+		sessions []session.Session
+		req      *memory.SearchRequest
+		want     []memory.Entry
+	}{
+		{
+			name:     "find events",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "quick HELLO"},
+			want: []memory.Entry{
+				textEntry("user1", "The Quick brown fox", 1),
+				textEntry("test-bot", "hello world, hello everyone", 4),
+			},
+		},
+		{
+			name:     "query syntax is escaped",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: `"lazy" OR NEAR(dog*`},
+			want: []memory.Entry{
+				textEntry("bot", "jumps over the lazy dog", 2),
+			},
+		},
+		{
+			name:     "no leakage for different app",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app2", UserID: "user1", Query: "hello"},
+		},
+		{
+			name:     "no leakage for different user",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user3", Query: "hello"},
+		},
+		{
+			name:     "no matches",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "something different"},
+		},
+		{
+			name:     "empty query",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: " ?! "},
+		},
+		{
+			name: "lookup on empty store",
+			req:  &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "hello"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := emptyService(t)
+			for _, sess := range tt.sessions {
+				if err := s.AddSession(t.Context(), sess); err != nil {
+					t.Fatalf("AddSession() error = %v", err)
+				}
+			}
+
+			got, err := s.Search(t.Context(), tt.req)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if diff := cmp.Diff(&memory.SearchResponse{Memories: tt.want}, got, ignoreID); diff != "" {
+				t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_databaseService_AddSessionIsIdempotent(t *testing.T) {
+	s := emptyService(t)
+	events := []*session.Event{textEvent("e1", "user1", "I live in Zurich", 1)}
+	for range 2 {
+		if err := s.AddSession(t.Context(), makeSession("app", "user", "sess", events)); err != nil {
+			t.Fatalf("AddSession() error = %v", err)
+		}
+	}
+	events = append(events, textEvent("e2", "user1", "I moved from Zurich to Geneva", 2))
+	if err := s.AddSession(t.Context(), makeSession("app", "user", "sess", events)); err != nil {
+		t.Fatalf("AddSession() error = %v", err)
+	}
+
+	got, err := s.Search(t.Context(), &memory.SearchRequest{AppName: "app", UserID: "user", Query: "zurich"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{
+		textEntry("user1", "I live in Zurich", 1),
+		textEntry("user1", "I moved from Zurich to Geneva", 2),
+	}}
+	if diff := cmp.Diff(want, got, sortMemories, ignoreID); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeleteUserMemories(t *testing.T) {
+	s := emptyService(t)
+	for _, sess := range []session.Session{
+		makeSession("app", "user1", "sess1", []*session.Event{textEvent("e1", "user1", "hello one", 1)}),
+		makeSession("app", "user1", "sess2", []*session.Event{textEvent("e2", "user1", "hello two", 2)}),
+		makeSession("app", "user2", "sess3", []*session.Event{textEvent("e3", "user2", "hello three", 3)}),
+	} {
+		if err := s.AddSession(t.Context(), sess); err != nil {
+			t.Fatalf("AddSession() error = %v", err)
+		}
+	}
+
+	if err := DeleteUserMemories(t.Context(), s, "app", "user1"); err != nil {
+		t.Fatalf("DeleteUserMemories() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		userID string
+		want   []memory.Entry
+	}{
+		{userID: "user1"},
+		{userID: "user2", want: []memory.Entry{textEntry("user2", "hello three", 3)}},
+	} {
+		got, err := s.Search(t.Context(), &memory.SearchRequest{AppName: "app", UserID: tc.userID, Query: "hello"})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if diff := cmp.Diff(&memory.SearchResponse{Memories: tc.want}, got, ignoreID); diff != "" {
+			t.Errorf("Search(%q) after delete mismatch (-want +got):\n%s", tc.userID, diff)
+		}
+	}
+
+	if err := DeleteUserMemories(t.Context(), s, "app", ""); err == nil {
+		t.Error("DeleteUserMemories() with empty user succeeded, want error")
+	}
+	if err := DeleteUserMemories(t.Context(), memory.InMemoryService(), "app", "user1"); err == nil {
+		t.Error("DeleteUserMemories() with in-memory service succeeded, want error")
+	}
+}
+
+func Test_databaseService_Manager(t *testing.T) {
+	ctx := t.Context()
+	s := emptyService(t)
+	m, ok := s.(memory.Manager)
+	if !ok {
+		t.Fatal("NewMemoryService() does not implement memory.Manager")
+	}
+	sessions := []session.Session{
+		makeSession("app", "user", "sess1", []*session.Event{
+			textEvent("e1", "user", "I like tea", 1),
+			textEvent("e2", "bot", "Noted", 2),
+		}),
+		makeSession("app", "user", "sess2", []*session.Event{textEvent("e3", "user", "I live in Zurich", 3)}),
+		makeSession("app", "other", "sess3", []*session.Event{textEvent("e4", "other", "I like coffee", 4)}),
+	}
+	addSessions := func() {
+		t.Helper()
+		for _, sess := range sessions {
+			if err := s.AddSession(ctx, sess); err != nil {
+				t.Fatalf("AddSession() error = %v", err)
+			}
+		}
+	}
+	addSessions()
+
+	list := func(userID string) []memory.Entry {
+		t.Helper()
+		resp, err := m.List(ctx, &memory.ListRequest{AppName: "app", UserID: userID})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		return resp.Memories
+	}
+	withID := func(id string, e memory.Entry) memory.Entry {
+		e.ID = id
+		return e
+	}
+	tea := withID(memory.EntryID("sess1", "e1"), textEntry("user", "I like tea", 1))
+	noted := withID(memory.EntryID("sess1", "e2"), textEntry("bot", "Noted", 2))
+	zurich := withID(memory.EntryID("sess2", "e3"), textEntry("user", "I live in Zurich", 3))
+
+	if diff := cmp.Diff([]memory.Entry{tea, noted, zurich}, list("user")); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := m.Delete(ctx, &memory.DeleteRequest{AppName: "app", UserID: "user", ID: noted.ID}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for _, id := range []string{noted.ID, memory.EntryID("sess3", "e4"), "invalid"} {
+		// The entry of e4 belongs to another user.
+		if err := m.Delete(ctx, &memory.DeleteRequest{AppName: "app", UserID: "user", ID: id}); !errors.Is(err, memory.ErrEntryNotFound) {
+			t.Errorf("Delete(%q) error = %v, want %v", id, err, memory.ErrEntryNotFound)
+		}
+	}
+
+	coffee := genai.NewContentFromText("I like coffee now", genai.RoleUser)
+	if err := m.Update(ctx, &memory.UpdateRequest{AppName: "app", UserID: "user", ID: tea.ID, Content: coffee}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := m.Update(ctx, &memory.UpdateRequest{AppName: "app", UserID: "user", ID: memory.EntryID("sess3", "e4"), Content: coffee}); !errors.Is(err, memory.ErrEntryNotFound) {
+		t.Errorf("Update() of other user entry error = %v, want %v", err, memory.ErrEntryNotFound)
+	}
+	tea.Content = coffee
+	got, err := s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "coffee"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if diff := cmp.Diff(&memory.SearchResponse{Memories: []memory.Entry{tea}}, got); diff != "" {
+		t.Errorf("Search() after Update() mismatch (-want +got):\n%s", diff)
+	}
+	// Adding the sessions again, e.g. after the next invocation, neither
+	// brings back the deleted entry nor overwrites the updated one.
+	addSessions()
+	if diff := cmp.Diff([]memory.Entry{tea, zurich}, list("user")); diff != "" {
+		t.Errorf("List() after AddSession() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := m.DeleteSession(ctx, &memory.DeleteSessionRequest{AppName: "app", UserID: "user", SessionID: "sess2"}); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	if diff := cmp.Diff([]memory.Entry{tea}, list("user")); diff != "" {
+		t.Errorf("List() after DeleteSession() mismatch (-want +got):\n%s", diff)
+	}
+	addSessions()
+	if diff := cmp.Diff([]memory.Entry{tea}, list("user")); diff != "" {
+		t.Errorf("List() after DeleteSession() and AddSession() mismatch (-want +got):\n%s", diff)
+	}
+	if got := list("other"); len(got) != 1 {
+		t.Errorf("List() of other user returned %d entries, want 1", len(got))
+	}
+}
+
+func TestAutoMigrate(t *testing.T) {
+	s := emptyService(t)
+	if err := s.AddSession(t.Context(), makeSession("app", "user", "sess", []*session.Event{textEvent("e1", "user", "hello", 1)})); err != nil {
+		t.Fatalf("AddSession() error = %v", err)
+	}
+
+	// The migration must be safe to run again on an existing database.
+	if err := AutoMigrate(s); err != nil {
+		t.Fatalf("AutoMigrate() second run error = %v", err)
+	}
+	if err := s.AddSession(t.Context(), makeSession("app", "user", "sess2", []*session.Event{textEvent("e2", "user", "hello again", 2)})); err != nil {
+		t.Fatalf("AddSession() error = %v", err)
+	}
+	got, err := s.Search(t.Context(), &memory.SearchRequest{AppName: "app", UserID: "user", Query: "hello"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{
+		textEntry("user", "hello", 1),
+		textEntry("user", "hello again", 2),
+	}}
+	if diff := cmp.Diff(want, got, sortMemories, ignoreID); diff != "" {
+		t.Errorf("Search() after migration mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := AutoMigrate(memory.InMemoryService()); err == nil {
+		t.Error("AutoMigrate() with in-memory service succeeded, want error")
+	}
+}
+
+func emptyService(t *testing.T) memory.Service {
+	t.Helper()
+
+	service, err := NewMemoryService(sqlite.Open(filepath.Join(t.TempDir(), "memory.db")))
+	if err != nil {
+		t.Fatalf("Failed to create memory service: %v", err)
+	}
+	if err := AutoMigrate(service); err != nil {
+		t.Fatalf("Failed to AutoMigrate db: %v", err)
+	}
+	return service
+}
+
+func ts(day int) time.Time {
+	return time.Date(2025, 10, day, 10, 0, 0, 0, time.UTC)
+}
+
+func textEvent(id, author, text string, day int) *session.Event {
+	return &session.Event{
+		ID:          id,
+		Author:      author,
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+		Timestamp:   ts(day),
+	}
+}
+
+func textEntry(author, text string, day int) memory.Entry {
+	return memory.Entry{
+		Content:   genai.NewContentFromText(text, genai.RoleUser),
+		Author:    author,
+		Timestamp: ts(day),
+	}
+}
+
+// ignoreID ignores the IDs of the entries, which are derived from the events.
+var ignoreID = cmpopts.IgnoreFields(memory.Entry{}, "ID")
+
+var sortMemories = cmp.Transformer("Sort", func(in *memory.SearchResponse) *memory.SearchResponse {
+	slices.SortFunc(in.Memories, func(m1, m2 memory.Entry) int {
+		return m1.Timestamp.Compare(m2.Timestamp)
+	})
+	return in
+})
+
+func makeSession(appName, userID, sessionID string, events []*session.Event) session.Session {
+	return &testSession{
+		appName:   appName,
+		userID:    userID,
+		sessionID: sessionID,
+		events:    events,
+	}
+}
+
+type testSession struct {
+	appName, userID, sessionID string
+	events                     []*session.Event
+}
+
+func (s *testSession) ID() string                    { return s.sessionID }
+func (s *testSession) AppName() string               { return s.appName }
+func (s *testSession) UserID() string                { return s.userID }
+func (s *testSession) Events() session.Events        { return s }
+func (s *testSession) All() iter.Seq[*session.Event] { return slices.Values(s.events) }
+func (s *testSession) Len() int                      { return len(s.events) }
+func (s *testSession) At(i int) *session.Event       { return s.events[i] }
+func (s *testSession) State() session.State          { panic("not implemented") }
+func (s *testSession) LastUpdateTime() time.Time     { panic("not implemented") }
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/genai"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"google.golang.org/adk/memory"
+)
+
+// storageMemory corresponds to the 'memories' table.
+type storageMemory struct {
+	ID        uint   `gorm:"primaryKey"`
+	AppName   string `gorm:"index:idx_memories_session,priority:1;uniqueIndex:idx_memories_entry,priority:1"`
+	UserID    string `gorm:"index:idx_memories_session,priority:2;uniqueIndex:idx_memories_entry,priority:2"`
+	SessionID string `gorm:"index:idx_memories_session,priority:3"`
+	EventID   string
+	// EntryID is the memory.EntryID of the session and the event.
+	EntryID string `gorm:"uniqueIndex:idx_memories_entry,priority:3"`
+	Author  string
+	// Content is the JSON encoded genai.Content of the event.
+	Content string
+	// Text is the searchable text of the content.
+	Text      string
+	Timestamp time.Time `gorm:"precision:6"`
+	// Edited is set when the user updated the content, adding the session
+	// again does not overwrite it.
+	Edited bool
+	// Deleted is set when the user deleted the memory. The row is kept
+	// without its content, so that adding the session again does not bring
+	// the memory back.
+	Deleted bool
+}
+
+// TableName explicitly sets the table name for the storageMemory struct.
+func (storageMemory) TableName() string {
+	return "memories"
+}
+
+// entry converts the row to a memory.Entry.
+func (m *storageMemory) entry() (memory.Entry, error) {
+	var content *genai.Content
+	if err := json.Unmarshal([]byte(m.Content), &content); err != nil {
+		return memory.Entry{}, fmt.Errorf("failed to unmarshal content of memory %q: %w", m.EntryID, err)
+	}
+	return memory.Entry{
+		ID:        m.EntryID,
+		Content:   content,
+		Author:    m.Author,
+		Timestamp: m.Timestamp,
+	}, nil
+}
+
+// searchIndexStatements returns the statements creating the full-text search
+// index for the given dialect. They are safe to run more than once.
+func searchIndexStatements(dialect string) []string {
+	switch dialect {
+	case "sqlite":
+		// An external content FTS5 table kept in sync by triggers.
+		return []string{
+			`CREATE VIRTUAL TABLE IF NOT EXISTS memories_fts USING fts5(text, content='memories', content_rowid='id')`,
+			`CREATE TRIGGER IF NOT EXISTS memories_fts_insert AFTER INSERT ON memories BEGIN
+				INSERT INTO memories_fts(rowid, text) VALUES (new.id, new.text);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS memories_fts_delete AFTER DELETE ON memories BEGIN
+				INSERT INTO memories_fts(memories_fts, rowid, text) VALUES ('delete', old.id, old.text);
+			END`,
+			`CREATE TRIGGER IF NOT EXISTS memories_fts_update AFTER UPDATE ON memories BEGIN
+				INSERT INTO memories_fts(memories_fts, rowid, text) VALUES ('delete', old.id, old.text);
+				INSERT INTO memories_fts(rowid, text) VALUES (new.id, new.text);
+			END`,
+			// The SQLite migrator may recreate the memories table, dropping the
+			// triggers above, so the index is rebuilt from the table.
+			`INSERT INTO memories_fts(memories_fts) VALUES ('rebuild')`,
+		}
+	case "postgres":
+		return []string{
+			`ALTER TABLE memories ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED`,
+			`CREATE INDEX IF NOT EXISTS idx_memories_search_vector ON memories USING GIN (search_vector)`,
+		}
+	default:
+		return nil
+	}
+}
+
+// searchQuery adds the conditions matching any of the words, and the ordering
+// by relevance where the dialect supports it.
+func searchQuery(db *gorm.DB, words []string) *gorm.DB {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		quoted := make([]string, len(words))
+		for i, w := range words {
+			quoted[i] = `"` + w + `"`
+		}
+		return db.Select("memories.*").
+			Joins("JOIN memories_fts ON memories_fts.rowid = memories.id").
+			Where("memories_fts MATCH ?", strings.Join(quoted, " OR ")).
+			Order("memories_fts.rank")
+	case "postgres":
+		tsquery := strings.Join(words, " | ")
+		return db.Where("memories.search_vector @@ to_tsquery('simple', ?)", tsquery).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "ts_rank(memories.search_vector, to_tsquery('simple', ?)) DESC",
+				Vars: []any{tsquery},
+			}})
+	default:
+		cond := db.Session(&gorm.Session{NewDB: true})
+		for _, w := range words {
+			cond = cond.Or("LOWER(memories.text) LIKE ?", "%"+w+"%")
+		}
+		return db.Where(cond).Order("memories.timestamp")
+	}
+}
+
+// queryWords splits the query into lowercase words made of letters and
+// digits only, so that they can be embedded into any search syntax.
+func queryWords(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	slices.Sort(words)
+	return slices.Compact(words)
+}
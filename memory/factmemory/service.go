@@ -0,0 +1,290 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package factmemory provides a [memory.Service] wrapper that stores the
+// durable facts and preferences an LLM extracts from the sessions, instead of
+// their raw events.
+//
+// Each fact is stored in the wrapped service as a memory of its own, so any
+// [memory.Service] implementation can be used to search them.
+package factmemory
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// DefaultInstruction is the instruction of the fact extraction if none is
+// provided. The existing memories and the conversation are appended to it.
+const DefaultInstruction = "You extract long-term memories from a conversation between a user and an AI agent. " +
+	"List the durable facts about the user and the user's preferences which will still be useful in future conversations, " +
+	"such as personal details, plans, likes and dislikes. " +
+	"Ignore small talk, temporary details and the agent's own statements. " +
+	"Write each fact as one short self-contained sentence in the third person, e.g. \"The user lives in Zurich.\". " +
+	"Do not repeat facts already listed in the existing memories, unless they changed. " +
+	"Respond with a JSON array of strings, which is empty if there is nothing worth remembering."
+
+const defaultMaxExistingMemories = 20
+
+// Config configures the service returned by [NewService].
+type Config struct {
+	// Service stores the extracted facts and searches them. Required.
+	Service memory.Service
+	// LLM extracts the facts from the sessions. Required.
+	LLM model.LLM
+	// Instruction tells the LLM which facts to extract. Defaults to
+	// [DefaultInstruction].
+	Instruction string
+	// MaxExistingMemories is the maximum number of existing memories given
+	// to the LLM, so it does not extract them again. Defaults to 20.
+	MaxExistingMemories int
+}
+
+// NewService returns a [memory.Service] which extracts facts from the added
+// sessions with the LLM, and stores them in the wrapped service.
+//
+// The extracted facts are deduplicated against the memories of the user found
+// by searching the conversation, and against each other. A fact is stored
+// under an ID derived from its text, so adding the same fact again, e.g. when
+// a session is added at the end of each invocation, does not duplicate it.
+//
+// The returned service implements [memory.Manager] if the wrapped service
+// does. The facts are listed and deleted as any other memory entries, but
+// they are not deleted with the session they were extracted from.
+func NewService(cfg Config) (memory.Service, error) {
+	if cfg.Service == nil {
+		return nil, fmt.Errorf("memory service is required")
+	}
+	if cfg.LLM == nil {
+		return nil, fmt.Errorf("LLM is required")
+	}
+	if cfg.Instruction == "" {
+		cfg.Instruction = DefaultInstruction
+	}
+	if cfg.MaxExistingMemories <= 0 {
+		cfg.MaxExistingMemories = defaultMaxExistingMemories
+	}
+	s := &factService{cfg: cfg}
+	if m, ok := cfg.Service.(memory.Manager); ok {
+		return &managedFactService{factService: s, Manager: m}, nil
+	}
+	return s, nil
+}
+
+type factService struct {
+	cfg Config
+}
+
+// managedFactService is a factService whose wrapped service implements
+// memory.Manager.
+type managedFactService struct {
+	*factService
+	memory.Manager
+}
+
+func (s *factService) AddSession(ctx context.Context, curSession session.Session) error {
+	conversation, lastTimestamp := formatEvents(curSession.Events())
+	if conversation == "" {
+		return nil
+	}
+
+	existing, err := s.cfg.Service.Search(ctx, &memory.SearchRequest{
+		AppName: curSession.AppName(),
+		UserID:  curSession.UserID(),
+		Query:   conversation,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search existing memories: %w", err)
+	}
+	var existingTexts []string
+	for _, entry := range existing.Memories {
+		if text := contentText(entry.Content); text != "" {
+			existingTexts = append(existingTexts, text)
+		}
+		if len(existingTexts) == s.cfg.MaxExistingMemories {
+			break
+		}
+	}
+
+	facts, err := s.extract(ctx, conversation, existingTexts)
+	if err != nil {
+		return fmt.Errorf("failed to extract facts from session %q: %w", curSession.ID(), err)
+	}
+
+	seen := make(map[string]bool)
+	for _, text := range existingTexts {
+		seen[normalize(text)] = true
+	}
+	for _, fact := range facts {
+		key := normalize(fact)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		sum := sha256.Sum256([]byte(key))
+		factSession := &factSession{
+			appName: curSession.AppName(),
+			userID:  curSession.UserID(),
+			id:      "fact-" + hex.EncodeToString(sum[:16]),
+			event: &session.Event{
+				LLMResponse: model.LLMResponse{
+					Content: genai.NewContentFromText(fact, genai.RoleModel),
+				},
+				Timestamp: lastTimestamp,
+			},
+		}
+		if err := s.cfg.Service.AddSession(ctx, factSession); err != nil {
+			return fmt.Errorf("failed to store fact: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *factService) Search(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	return s.cfg.Service.Search(ctx, req)
+}
+
+// extract asks the LLM for the facts of the conversation.
+func (s *factService) extract(ctx context.Context, conversation string, existing []string) ([]string, error) {
+	var sb strings.Builder
+	sb.WriteString(s.cfg.Instruction)
+	sb.WriteString("\n\nExisting memories:\n")
+	if len(existing) == 0 {
+		sb.WriteString("(none)\n")
+	}
+	for _, text := range existing {
+		fmt.Fprintf(&sb, "- %s\n", text)
+	}
+	sb.WriteString("\nConversation:\n")
+	sb.WriteString(conversation)
+
+	req := &model.LLMRequest{
+		Model: s.cfg.LLM.Name(),
+		Contents: []*genai.Content{
+			genai.NewContentFromText(sb.String(), genai.RoleUser),
+		},
+		Config: &genai.GenerateContentConfig{
+			ResponseMIMEType: "application/json",
+			ResponseSchema: &genai.Schema{
+				Type:  genai.TypeArray,
+				Items: &genai.Schema{Type: genai.TypeString},
+			},
+		},
+	}
+	var text string
+	for resp, err := range s.cfg.LLM.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, err
+		}
+		if resp.ErrorCode != "" {
+			return nil, fmt.Errorf("model returned error %s: %s", resp.ErrorCode, resp.ErrorMessage)
+		}
+		if resp.Content != nil && !resp.Partial {
+			text = contentText(resp.Content)
+		}
+	}
+
+	// Models without the JSON mode may wrap the response in a code block.
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.Trim(text, "`\n ")
+	var facts []string
+	if err := json.Unmarshal([]byte(text), &facts); err != nil {
+		return nil, fmt.Errorf("failed to parse model response %q: %w", text, err)
+	}
+	return facts, nil
+}
+
+// formatEvents renders the text of the events as one line per part, prefixed
+// by the event author, and returns the timestamp of the latest event.
+func formatEvents(events session.Events) (string, time.Time) {
+	var sb strings.Builder
+	var last time.Time
+	for ev := range events.All() {
+		if ev.Timestamp.After(last) {
+			last = ev.Timestamp
+		}
+		if ev.Content == nil {
+			continue
+		}
+		for _, part := range ev.Content.Parts {
+			if part == nil || part.Thought || part.Text == "" {
+				continue
+			}
+			fmt.Fprintf(&sb, "%s: %s\n", ev.Author, part.Text)
+		}
+	}
+	return sb.String(), last
+}
+
+func contentText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part != nil && !part.Thought && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// normalize returns the text compared to find duplicated facts.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimRight(strings.TrimSpace(text), "."))), " ")
+}
+
+// factSession is a session holding a single fact, as stored in the wrapped
+// memory service.
+type factSession struct {
+	appName, userID, id string
+	event               *session.Event
+}
+
+func (s *factSession) ID() string                { return s.id }
+func (s *factSession) AppName() string           { return s.appName }
+func (s *factSession) UserID() string            { return s.userID }
+func (s *factSession) Events() session.Events    { return s }
+func (s *factSession) State() session.State      { return emptyState{} }
+func (s *factSession) LastUpdateTime() time.Time { return s.event.Timestamp }
+
+func (s *factSession) All() iter.Seq[*session.Event] {
+	return slices.Values([]*session.Event{s.event})
+}
+func (s *factSession) Len() int                { return 1 }
+func (s *factSession) At(i int) *session.Event { return s.event }
+
+// emptyState is the state of the fact sessions.
+type emptyState struct{}
+
+func (emptyState) Get(key string) (any, error) { return nil, session.ErrStateKeyNotExist }
+func (emptyState) Set(key string, value any) error {
+	return fmt.Errorf("fact sessions have no state")
+}
+func (emptyState) All() iter.Seq2[string, any] { return func(func(string, any) bool) {} }
@@ -0,0 +1,277 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package factmemory_test
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/memory/factmemory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/fakellm"
+	"google.golang.org/adk/session"
+)
+
+func TestService_AddSession(t *testing.T) {
+	at := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+	conversation := makeSession("sess1", []*session.Event{
+		textEvent("user", "Hi, I just moved to Zurich with a dog", at),
+		textEvent("bot", "Welcome to Zurich!", at.Add(time.Minute)),
+	})
+	fact := func(text string) memory.Entry {
+		return memory.Entry{Content: genai.NewContentFromText(text, genai.RoleModel), Timestamp: at.Add(time.Minute)}
+	}
+
+	tests := []struct {
+		name     string
+		existing []string
+		response string
+		want     []memory.Entry
+	}{
+		{
+			name:     "facts are stored",
+			response: `["The user lives in Zurich.", "The user has a dog."]`,
+			want:     []memory.Entry{fact("The user lives in Zurich."), fact("The user has a dog.")},
+		},
+		{
+			name:     "code block",
+			response: "```json\n[\"The user lives in Zurich.\"]\n```",
+			want:     []memory.Entry{fact("The user lives in Zurich.")},
+		},
+		{
+			name:     "duplicated facts",
+			response: `["The user lives in Zurich.", "the user lives in zurich", ""]`,
+			want:     []memory.Entry{fact("The user lives in Zurich.")},
+		},
+		{
+			name:     "existing facts are skipped",
+			existing: []string{`["The user has a dog."]`},
+			response: `["The user lives in Zurich.", "The user has a dog"]`,
+			want: []memory.Entry{
+				{Content: genai.NewContentFromText("The user has a dog.", genai.RoleModel), Timestamp: at},
+				fact("The user lives in Zurich."),
+			},
+		},
+		{
+			name:     "nothing to remember",
+			response: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var turns []fakellm.Turn
+			for _, response := range tt.existing {
+				turns = append(turns, fakellm.Turn{Responses: fakellm.Text(response)})
+			}
+			wantExisting := "(none)"
+			if len(tt.existing) > 0 {
+				wantExisting = "- The user has a dog."
+			}
+			turns = append(turns, fakellm.Turn{
+				Expect: []fakellm.Matcher{
+					fakellm.ContainsText(factmemory.DefaultInstruction),
+					fakellm.ContainsText("Existing memories:\n" + wantExisting),
+					fakellm.ContainsText("user: Hi, I just moved to Zurich with a dog\nbot: Welcome to Zurich!\n"),
+				},
+				Responses: fakellm.Text(tt.response),
+			})
+
+			s, err := factmemory.NewService(factmemory.Config{
+				Service: memory.InMemoryService(),
+				LLM:     fakellm.New(t, turns...),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tt.existing) > 0 {
+				earlier := makeSession("sess0", []*session.Event{textEvent("user", "I have a dog", at)})
+				if err := s.AddSession(t.Context(), earlier); err != nil {
+					t.Fatalf("AddSession() error = %v", err)
+				}
+			}
+
+			if err := s.AddSession(t.Context(), conversation); err != nil {
+				t.Fatalf("AddSession() error = %v", err)
+			}
+
+			got, err := s.Search(t.Context(), &memory.SearchRequest{AppName: "app", UserID: "user", Query: "user"})
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if diff := cmp.Diff(&memory.SearchResponse{Memories: tt.want}, got, sortMemories, ignoreID); diff != "" {
+				t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_AddSessionIsIdempotent(t *testing.T) {
+	llm := fakellm.New(t,
+		fakellm.Turn{Responses: fakellm.Text(`["The user likes tea."]`)},
+		// Adding the session again returns the same fact, e.g. because the
+		// existing memory was not found by the search.
+		fakellm.Turn{Responses: fakellm.Text(`["The user likes tea."]`)},
+	)
+	inner := &hidingService{Service: memory.InMemoryService()}
+	s, err := factmemory.NewService(factmemory.Config{Service: inner, LLM: llm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := makeSession("sess1", []*session.Event{textEvent("user", "I like tea", time.Time{})})
+	for range 2 {
+		if err := s.AddSession(t.Context(), sess); err != nil {
+			t.Fatalf("AddSession() error = %v", err)
+		}
+		inner.hide = true
+	}
+
+	inner.hide = false
+	got, err := s.Search(t.Context(), &memory.SearchRequest{AppName: "app", UserID: "user", Query: "user"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{{Content: genai.NewContentFromText("The user likes tea.", genai.RoleModel)}}}
+	if diff := cmp.Diff(want, got, ignoreID); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestService_AddSession_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		turn    fakellm.Turn
+		wantErr string
+	}{
+		{
+			name:    "invalid response",
+			turn:    fakellm.Turn{Responses: fakellm.Text("The user likes tea.")},
+			wantErr: "failed to parse model response",
+		},
+		{
+			name:    "model error",
+			turn:    fakellm.Turn{Responses: []*model.LLMResponse{{ErrorCode: "SAFETY", ErrorMessage: "blocked"}}},
+			wantErr: "model returned error SAFETY",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := factmemory.NewService(factmemory.Config{Service: memory.InMemoryService(), LLM: fakellm.New(t, tt.turn)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sess := makeSession("sess1", []*session.Event{textEvent("user", "I like tea", time.Time{})})
+			if err := s.AddSession(t.Context(), sess); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AddSession() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_AddSession_NoText(t *testing.T) {
+	// The LLM is not called for sessions without text.
+	s, err := factmemory.NewService(factmemory.Config{Service: memory.InMemoryService(), LLM: fakellm.New(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := makeSession("sess1", []*session.Event{{Author: "user"}})
+	if err := s.AddSession(t.Context(), sess); err != nil {
+		t.Errorf("AddSession() error = %v", err)
+	}
+}
+
+func TestNewService_Manager(t *testing.T) {
+	s, err := factmemory.NewService(factmemory.Config{Service: memory.InMemoryService(), LLM: fakellm.New(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.(memory.Manager); !ok {
+		t.Error("NewService() wrapping a memory.Manager does not implement memory.Manager")
+	}
+
+	s, err = factmemory.NewService(factmemory.Config{Service: &hidingService{Service: memory.InMemoryService()}, LLM: fakellm.New(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.(memory.Manager); ok {
+		t.Error("NewService() wrapping a service without memory.Manager implements memory.Manager")
+	}
+}
+
+func TestNewService(t *testing.T) {
+	if _, err := factmemory.NewService(factmemory.Config{LLM: fakellm.New(t)}); err == nil {
+		t.Error("NewService() without service succeeded, want error")
+	}
+	if _, err := factmemory.NewService(factmemory.Config{Service: memory.InMemoryService()}); err == nil {
+		t.Error("NewService() without LLM succeeded, want error")
+	}
+}
+
+// hidingService hides the stored memories from the searches if hide is set.
+type hidingService struct {
+	memory.Service
+	hide bool
+}
+
+func (r *hidingService) Search(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	if r.hide {
+		return &memory.SearchResponse{}, nil
+	}
+	return r.Service.Search(ctx, req)
+}
+
+var ignoreID = cmpopts.IgnoreFields(memory.Entry{}, "ID")
+
+var sortMemories = cmp.Transformer("Sort", func(in *memory.SearchResponse) *memory.SearchResponse {
+	slices.SortFunc(in.Memories, func(m1, m2 memory.Entry) int {
+		return strings.Compare(m1.Content.Parts[0].Text, m2.Content.Parts[0].Text)
+	})
+	return in
+})
+
+func textEvent(author, text string, timestamp time.Time) *session.Event {
+	return &session.Event{
+		Author:      author,
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+		Timestamp:   timestamp,
+	}
+}
+
+func makeSession(id string, events []*session.Event) session.Session {
+	return &testSession{id: id, events: events}
+}
+
+type testSession struct {
+	id     string
+	events []*session.Event
+}
+
+func (s *testSession) ID() string                    { return s.id }
+func (s *testSession) AppName() string               { return "app" }
+func (s *testSession) UserID() string                { return "user" }
+func (s *testSession) Events() session.Events        { return s }
+func (s *testSession) All() iter.Seq[*session.Event] { return slices.Values(s.events) }
+func (s *testSession) Len() int                      { return len(s.events) }
+func (s *testSession) At(i int) *session.Event       { return s.events[i] }
+func (s *testSession) State() session.State          { panic("not implemented") }
+func (s *testSession) LastUpdateTime() time.Time     { panic("not implemented") }
@@ -15,8 +15,12 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,9 +31,13 @@ import (
 )
 
 // InMemoryService returns a new in-memory implementation of the memory service. Thread-safe.
+//
+// The returned service also implements [Manager].
 func InMemoryService() Service {
 	return &inMemoryService{
-		store: make(map[key]map[sessionID][]value),
+		store:   make(map[key]map[sessionID][]value),
+		deleted: make(map[key]map[string]struct{}),
+		edited:  make(map[key]map[string]*genai.Content),
 	}
 }
 
@@ -40,6 +48,7 @@ type key struct {
 type sessionID string
 
 type value struct {
+	id        string
 	content   *genai.Content
 	author    string
 	timestamp time.Time
@@ -52,45 +61,53 @@ type value struct {
 type inMemoryService struct {
 	mu    sync.RWMutex
 	store map[key]map[sessionID][]value
+	// deleted and edited record the IDs of the entries deleted and updated
+	// via Manager, so that adding their session again keeps them as they are.
+	deleted map[key]map[string]struct{}
+	edited  map[key]map[string]*genai.Content
 }
 
 func (s *inMemoryService) AddSession(ctx context.Context, curSession session.Session) error {
-	var values []value
+	k := key{
+		appName: curSession.AppName(),
+		userID:  curSession.UserID(),
+	}
 
-	for event := range curSession.Events().All() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var values []value
+	events := curSession.Events()
+	for i := range events.Len() {
+		event := events.At(i)
 		if event.LLMResponse.Content == nil {
 			continue
 		}
 
-		words := make(map[string]struct{})
-		for _, part := range event.LLMResponse.Content.Parts {
-			if part.Text == "" {
-				continue
-			}
-
-			maps.Copy(words, extractWords(part.Text))
+		// Events without an ID are identified by their position.
+		id := EntryID(curSession.ID(), cmp.Or(event.ID, strconv.Itoa(i)))
+		if _, ok := s.deleted[k][id]; ok {
+			continue
+		}
+		content := event.LLMResponse.Content
+		if edited, ok := s.edited[k][id]; ok {
+			content = edited
 		}
 
+		words := contentWords(content)
 		if len(words) == 0 {
 			continue
 		}
 
 		values = append(values, value{
-			content:   event.LLMResponse.Content,
+			id:        id,
+			content:   content,
 			author:    event.Author,
 			timestamp: event.Timestamp,
 			words:     words,
 		})
 	}
 
-	k := key{
-		appName: curSession.AppName(),
-		userID:  curSession.UserID(),
-	}
-
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	v, ok := s.store[k]
 	if !ok {
 		v = map[sessionID][]value{}
@@ -111,22 +128,14 @@ func (s *inMemoryService) Search(ctx context.Context, req *SearchRequest) (*Sear
 	}
 
 	s.mu.RLock()
-	values, ok := s.store[k]
-	s.mu.RUnlock()
-	if !ok {
-		return &SearchResponse{}, nil
-	}
+	defer s.mu.RUnlock()
 
 	res := &SearchResponse{}
 
-	for _, events := range values {
+	for _, events := range s.store[k] {
 		for _, e := range events {
 			if checkMapsIntersect(e.words, queryWords) {
-				res.Memories = append(res.Memories, Entry{
-					Content:   e.content,
-					Author:    e.author,
-					Timestamp: e.timestamp,
-				})
+				res.Memories = append(res.Memories, e.entry())
 			}
 		}
 	}
@@ -134,6 +143,138 @@ func (s *inMemoryService) Search(ctx context.Context, req *SearchRequest) (*Sear
 	return res, nil
 }
 
+func (s *inMemoryService) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	k := key{
+		appName: req.AppName,
+		userID:  req.UserID,
+	}
+
+	s.mu.RLock()
+	var values []value
+	for _, events := range s.store[k] {
+		values = append(values, events...)
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(values, func(a, b value) int {
+		if c := a.timestamp.Compare(b.timestamp); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.id, b.id)
+	})
+
+	res := &ListResponse{}
+	for _, v := range values {
+		res.Memories = append(res.Memories, v.entry())
+	}
+	return res, nil
+}
+
+func (s *inMemoryService) Delete(ctx context.Context, req *DeleteRequest) error {
+	k := key{
+		appName: req.AppName,
+		userID:  req.UserID,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sid, events := range s.store[k] {
+		i := slices.IndexFunc(events, func(v value) bool { return v.id == req.ID })
+		if i < 0 {
+			continue
+		}
+		// Copy the values, the previous slice may still be read.
+		s.store[k][sid] = slices.Delete(slices.Clone(events), i, i+1)
+		s.markDeleted(k, req.ID)
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrEntryNotFound, req.ID)
+}
+
+func (s *inMemoryService) DeleteSession(ctx context.Context, req *DeleteSessionRequest) error {
+	k := key{
+		appName: req.AppName,
+		userID:  req.UserID,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sid := sessionID(req.SessionID)
+	for _, v := range s.store[k][sid] {
+		s.markDeleted(k, v.id)
+	}
+	delete(s.store[k], sid)
+	return nil
+}
+
+func (s *inMemoryService) Update(ctx context.Context, req *UpdateRequest) error {
+	if req.Content == nil {
+		return fmt.Errorf("content is required")
+	}
+	words := contentWords(req.Content)
+	if len(words) == 0 {
+		return fmt.Errorf("content has no text")
+	}
+	k := key{
+		appName: req.AppName,
+		userID:  req.UserID,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sid, events := range s.store[k] {
+		i := slices.IndexFunc(events, func(v value) bool { return v.id == req.ID })
+		if i < 0 {
+			continue
+		}
+		events = slices.Clone(events)
+		events[i].content = req.Content
+		events[i].words = words
+		s.store[k][sid] = events
+		if s.edited[k] == nil {
+			s.edited[k] = map[string]*genai.Content{}
+		}
+		s.edited[k][req.ID] = req.Content
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrEntryNotFound, req.ID)
+}
+
+// markDeleted records that the entry was deleted. The caller must hold the
+// lock.
+func (s *inMemoryService) markDeleted(k key, id string) {
+	if s.deleted[k] == nil {
+		s.deleted[k] = map[string]struct{}{}
+	}
+	s.deleted[k][id] = struct{}{}
+	delete(s.edited[k], id)
+}
+
+func (v *value) entry() Entry {
+	return Entry{
+		ID:        v.id,
+		Content:   v.content,
+		Author:    v.author,
+		Timestamp: v.timestamp,
+	}
+}
+
+// contentWords returns the set of words in the text parts of the content.
+func contentWords(content *genai.Content) map[string]struct{} {
+	words := make(map[string]struct{})
+	for _, part := range content.Parts {
+		if part.Text == "" {
+			continue
+		}
+
+		maps.Copy(words, extractWords(part.Text))
+	}
+	return words
+}
+
 func checkMapsIntersect(m1, m2 map[string]struct{}) bool {
 	if len(m1) == 0 || len(m2) == 0 {
 		return false
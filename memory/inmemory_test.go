@@ -15,6 +15,7 @@
 package memory_test
 
 import (
+	"errors"
 	"iter"
 	"slices"
 	"testing"
@@ -41,6 +42,7 @@ func Test_inMemoryService_SearchMemory(t *testing.T) {
 			initSessions: []session.Session{
 				makeSession(t, "app1", "user1", "sess1", []*session.Event{
 					{
+						ID:     "e1",
 						Author: "user1",
 						LLMResponse: model.LLMResponse{
 							Content: genai.NewContentFromText("The Quick brown fox", genai.RoleUser),
@@ -55,6 +57,7 @@ func Test_inMemoryService_SearchMemory(t *testing.T) {
 				}),
 				makeSession(t, "app1", "user1", "sess2", []*session.Event{
 					{
+						ID:          "e3",
 						Author:      "test-bot",
 						LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello world", genai.RoleModel)},
 						Timestamp:   must(time.Parse(time.RFC3339, "2023-10-02T10:00:00Z")),
@@ -72,11 +75,13 @@ func Test_inMemoryService_SearchMemory(t *testing.T) {
 			wantResp: &memory.SearchResponse{
 				Memories: []memory.Entry{
 					{
+						ID:        memory.EntryID("sess1", "e1"),
 						Content:   genai.NewContentFromText("The Quick brown fox", genai.RoleUser),
 						Author:    "user1",
 						Timestamp: must(time.Parse(time.RFC3339, "2023-10-01T10:00:00Z")),
 					},
 					{
+						ID:        memory.EntryID("sess2", "e3"),
 						Content:   genai.NewContentFromText("hello world", genai.RoleModel),
 						Author:    "test-bot",
 						Timestamp: must(time.Parse(time.RFC3339, "2023-10-02T10:00:00Z")),
@@ -157,6 +162,106 @@ func Test_inMemoryService_SearchMemory(t *testing.T) {
 	}
 }
 
+func Test_inMemoryService_Manager(t *testing.T) {
+	ctx := t.Context()
+	s := memory.InMemoryService()
+	m, ok := s.(memory.Manager)
+	if !ok {
+		t.Fatal("InMemoryService() does not implement memory.Manager")
+	}
+
+	sessions := []session.Session{
+		makeSession(t, "app", "user", "sess1", []*session.Event{
+			{ID: "e1", Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("I like tea", genai.RoleUser)}, Timestamp: at(1)},
+			{ID: "e2", Author: "bot", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("Noted", genai.RoleModel)}, Timestamp: at(2)},
+		}),
+		makeSession(t, "app", "user", "sess2", []*session.Event{
+			{ID: "e3", Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("I live in Zurich", genai.RoleUser)}, Timestamp: at(3)},
+		}),
+		makeSession(t, "app", "other", "sess3", []*session.Event{
+			{ID: "e4", Author: "other", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("I like coffee", genai.RoleUser)}, Timestamp: at(4)},
+		}),
+	}
+	addSessions := func() {
+		t.Helper()
+		for _, sess := range sessions {
+			if err := s.AddSession(ctx, sess); err != nil {
+				t.Fatalf("AddSession() error = %v", err)
+			}
+		}
+	}
+	addSessions()
+
+	list := func(userID string) []memory.Entry {
+		t.Helper()
+		resp, err := m.List(ctx, &memory.ListRequest{AppName: "app", UserID: userID})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		return resp.Memories
+	}
+	tea := memory.Entry{ID: memory.EntryID("sess1", "e1"), Content: genai.NewContentFromText("I like tea", genai.RoleUser), Author: "user", Timestamp: at(1)}
+	noted := memory.Entry{ID: memory.EntryID("sess1", "e2"), Content: genai.NewContentFromText("Noted", genai.RoleModel), Author: "bot", Timestamp: at(2)}
+	zurich := memory.Entry{ID: memory.EntryID("sess2", "e3"), Content: genai.NewContentFromText("I live in Zurich", genai.RoleUser), Author: "user", Timestamp: at(3)}
+
+	if diff := cmp.Diff([]memory.Entry{tea, noted, zurich}, list("user")); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := m.Delete(ctx, &memory.DeleteRequest{AppName: "app", UserID: "user", ID: noted.ID}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for _, id := range []string{noted.ID, memory.EntryID("sess3", "e4"), "invalid"} {
+		// The entry of e4 belongs to another user.
+		if err := m.Delete(ctx, &memory.DeleteRequest{AppName: "app", UserID: "user", ID: id}); !errors.Is(err, memory.ErrEntryNotFound) {
+			t.Errorf("Delete(%q) error = %v, want %v", id, err, memory.ErrEntryNotFound)
+		}
+	}
+
+	coffee := genai.NewContentFromText("I like coffee now", genai.RoleUser)
+	if err := m.Update(ctx, &memory.UpdateRequest{AppName: "app", UserID: "user", ID: tea.ID, Content: coffee}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := m.Update(ctx, &memory.UpdateRequest{AppName: "app", UserID: "user", ID: noted.ID, Content: coffee}); !errors.Is(err, memory.ErrEntryNotFound) {
+		t.Errorf("Update() of deleted entry error = %v, want %v", err, memory.ErrEntryNotFound)
+	}
+	tea.Content = coffee
+	if diff := cmp.Diff([]memory.Entry{tea, zurich}, list("user")); diff != "" {
+		t.Errorf("List() after Delete() and Update() mismatch (-want +got):\n%s", diff)
+	}
+	// Adding the sessions again, e.g. after the next invocation, neither
+	// brings back the deleted entry nor overwrites the updated one.
+	addSessions()
+	if diff := cmp.Diff([]memory.Entry{tea, zurich}, list("user")); diff != "" {
+		t.Errorf("List() after AddSession() mismatch (-want +got):\n%s", diff)
+	}
+	got, err := s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "coffee"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if diff := cmp.Diff(&memory.SearchResponse{Memories: []memory.Entry{tea}}, got); diff != "" {
+		t.Errorf("Search() after Update() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := m.DeleteSession(ctx, &memory.DeleteSessionRequest{AppName: "app", UserID: "user", SessionID: "sess2"}); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	if diff := cmp.Diff([]memory.Entry{tea}, list("user")); diff != "" {
+		t.Errorf("List() after DeleteSession() mismatch (-want +got):\n%s", diff)
+	}
+	addSessions()
+	if diff := cmp.Diff([]memory.Entry{tea}, list("user")); diff != "" {
+		t.Errorf("List() after DeleteSession() and AddSession() mismatch (-want +got):\n%s", diff)
+	}
+	if got := list("other"); len(got) != 1 {
+		t.Errorf("List() of other user returned %d entries, want 1", len(got))
+	}
+}
+
+func at(day int) time.Time {
+	return time.Date(2025, 10, day, 10, 0, 0, 0, time.UTC)
+}
+
 func makeSession(t *testing.T, appName, userID, sessionID string, events []*session.Event) session.Session {
 	t.Helper()
 
@@ -17,6 +17,9 @@ package memory
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"time"
 
 	"google.golang.org/genai"
@@ -38,6 +41,25 @@ type Service interface {
 	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
 }
 
+// Manager is implemented by the memory services which let users see and
+// remove what is remembered about them.
+type Manager interface {
+	// List returns the memory entries of the user, oldest first.
+	List(ctx context.Context, req *ListRequest) (*ListResponse, error)
+	// Delete deletes a memory entry. It returns [ErrEntryNotFound] if the
+	// user has no entry with the ID.
+	Delete(ctx context.Context, req *DeleteRequest) error
+	// DeleteSession deletes the memory entries added from a session.
+	DeleteSession(ctx context.Context, req *DeleteSessionRequest) error
+	// Update replaces the content of a memory entry. It returns
+	// [ErrEntryNotFound] if the user has no entry with the ID.
+	Update(ctx context.Context, req *UpdateRequest) error
+}
+
+// ErrEntryNotFound is returned by the [Manager] methods when the memory entry
+// does not exist.
+var ErrEntryNotFound = errors.New("memory entry not found")
+
 // SearchRequest represents a request for memory search.
 type SearchRequest struct {
 	Query   string
@@ -50,8 +72,46 @@ type SearchResponse struct {
 	Memories []Entry
 }
 
+// ListRequest represents a request to list the memory entries of a user.
+type ListRequest struct {
+	AppName string
+	UserID  string
+}
+
+// ListResponse represents the response from listing memory entries.
+type ListResponse struct {
+	Memories []Entry
+}
+
+// DeleteRequest represents a request to delete a memory entry.
+type DeleteRequest struct {
+	AppName string
+	UserID  string
+	ID      string
+}
+
+// DeleteSessionRequest represents a request to delete the memory entries
+// added from a session.
+type DeleteSessionRequest struct {
+	AppName   string
+	UserID    string
+	SessionID string
+}
+
+// UpdateRequest represents a request to replace the content of a memory
+// entry.
+type UpdateRequest struct {
+	AppName string
+	UserID  string
+	ID      string
+	Content *genai.Content
+}
+
 // Entry represents a single memory entry.
 type Entry struct {
+	// ID identifies the entry among the memories of the user. It is set by
+	// the services implementing [Manager], see [EntryID].
+	ID string
 	// Content contains the main content of the memory.
 	Content *genai.Content
 	// Author of the memory.
@@ -60,3 +120,12 @@ type Entry struct {
 	// This string will be forwarded to LLM. Preferred format is ISO 8601 format.
 	Timestamp time.Time
 }
+
+// EntryID returns the ID of the memory entry added from an event of a
+// session. It does not change when the session is added again, so that the
+// services implementing [Manager] keep the entries deleted or updated by the
+// user as they are.
+func EntryID(sessionID, eventID string) string {
+	sum := sha256.Sum256([]byte(sessionID + "\x00" + eventID))
+	return hex.EncodeToString(sum[:12])
+}
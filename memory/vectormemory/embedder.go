@@ -0,0 +1,163 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectormemory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genai"
+)
+
+// TaskType tells the [Embedder] what the embedded text will be used for.
+// Some embedding models produce different vectors for documents and queries.
+type TaskType string
+
+const (
+	// TaskDocument is used for the memory chunks stored by the service.
+	TaskDocument TaskType = "RETRIEVAL_DOCUMENT"
+	// TaskQuery is used for the search queries.
+	TaskQuery TaskType = "RETRIEVAL_QUERY"
+)
+
+// Embedder converts texts into embedding vectors.
+type Embedder interface {
+	// Embed returns one vector per text, in the same order as texts.
+	// All vectors returned by an Embedder must have the same dimension.
+	Embed(ctx context.Context, texts []string, task TaskType) ([][]float32, error)
+}
+
+// geminiEmbedder is an Embedder backed by the Gemini embedding models.
+type geminiEmbedder struct {
+	client     *genai.Client
+	model      string
+	dimensions int32
+}
+
+// GeminiEmbedderConfig configures the embedder returned by [NewGeminiEmbedder].
+type GeminiEmbedderConfig struct {
+	// Model is the embedding model name, e.g. "gemini-embedding-001".
+	Model string
+	// Dimensions optionally reduces the size of the returned vectors.
+	// Zero means the model default.
+	Dimensions int32
+	// ClientConfig is used to create the underlying [genai.Client].
+	ClientConfig *genai.ClientConfig
+}
+
+// NewGeminiEmbedder returns an [Embedder] that calls the Gemini embeddings API.
+func NewGeminiEmbedder(ctx context.Context, cfg GeminiEmbedderConfig) (Embedder, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required")
+	}
+	client, err := genai.NewClient(ctx, cfg.ClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+	return &geminiEmbedder{client: client, model: cfg.Model, dimensions: cfg.Dimensions}, nil
+}
+
+func (e *geminiEmbedder) Embed(ctx context.Context, texts []string, task TaskType) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	contents := make([]*genai.Content, len(texts))
+	for i, text := range texts {
+		contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+	}
+	cfg := &genai.EmbedContentConfig{TaskType: string(task)}
+	if e.dimensions > 0 {
+		cfg.OutputDimensionality = &e.dimensions
+	}
+	resp, err := e.client.Models.EmbedContent(ctx, e.model, contents, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed content: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for i, embedding := range resp.Embeddings {
+		if embedding == nil {
+			return nil, fmt.Errorf("missing embedding for text %d", i)
+		}
+		vectors[i] = embedding.Values
+	}
+	return vectors, nil
+}
+
+// hashEmbedder is a deterministic Embedder that does not call any model.
+type hashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder returns an [Embedder] that maps the words of a text into a
+// vector of the given dimension using feature hashing.
+//
+// It captures word overlap only, not meaning, and is intended for tests and
+// local development. The task type is ignored.
+func NewHashEmbedder(dimensions int) Embedder {
+	if dimensions <= 0 {
+		dimensions = 256
+	}
+	return &hashEmbedder{dimensions: dimensions}
+}
+
+func (e *hashEmbedder) Embed(ctx context.Context, texts []string, task TaskType) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = e.embed(text)
+	}
+	return vectors, nil
+}
+
+func (e *hashEmbedder) embed(text string) []float32 {
+	v := make([]float32, e.dimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		// The top bit picks the sign so that collisions tend to cancel out.
+		if sum>>63 == 0 {
+			v[sum%uint64(e.dimensions)]++
+		} else {
+			v[sum%uint64(e.dimensions)]--
+		}
+	}
+	normalize(v)
+	return v
+}
+
+// normalize scales v to unit length in place. Zero vectors are left as is.
+func normalize(v []float32) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+}
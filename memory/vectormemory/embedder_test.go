@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectormemory_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory/vectormemory"
+)
+
+func TestGeminiEmbedder_Embed(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"embeddings":[{"values":[0.1,0.2]},{"values":[0.3,0.4]}]}`))
+	}))
+	defer srv.Close()
+
+	e, err := vectormemory.NewGeminiEmbedder(t.Context(), vectormemory.GeminiEmbedderConfig{
+		Model:      "gemini-embedding-001",
+		Dimensions: 2,
+		ClientConfig: &genai.ClientConfig{
+			APIKey:      "fake-key",
+			Backend:     genai.BackendGeminiAPI,
+			HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := e.Embed(t.Context(), []string{"first", "second"}, vectormemory.TaskQuery)
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if diff := cmp.Diff([][]float32{{0.1, 0.2}, {0.3, 0.4}}, got); diff != "" {
+		t.Errorf("Embed() mismatch (-want +got):\n%s", diff)
+	}
+	if !strings.HasSuffix(gotPath, "/models/gemini-embedding-001:batchEmbedContents") {
+		t.Errorf("request path = %q, want batchEmbedContents", gotPath)
+	}
+	requests, _ := gotBody["requests"].([]any)
+	if len(requests) != 2 {
+		t.Fatalf("request has %d embed requests, want 2: %v", len(requests), gotBody)
+	}
+	first, _ := requests[0].(map[string]any)
+	if first["taskType"] != "RETRIEVAL_QUERY" || first["outputDimensionality"] != float64(2) {
+		t.Errorf("request = %v, want taskType RETRIEVAL_QUERY and outputDimensionality 2", first)
+	}
+}
+
+func TestHashEmbedder(t *testing.T) {
+	e := vectormemory.NewHashEmbedder(64)
+	got, err := e.Embed(t.Context(), []string{"Hello, world", "hello WORLD", "", "goodbye"}, vectormemory.TaskDocument)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("Embed() returned %d vectors, want 4", len(got))
+	}
+	for i, v := range got {
+		if len(v) != 64 {
+			t.Errorf("Embed() vector %d has %d dimensions, want 64", i, len(v))
+		}
+	}
+	if diff := cmp.Diff(got[0], got[1]); diff != "" {
+		t.Errorf("Embed() is not case and punctuation insensitive (-first +second):\n%s", diff)
+	}
+	if slices.ContainsFunc(got[2], func(x float32) bool { return x != 0 }) {
+		t.Errorf("Embed(\"\") = %v, want zero vector", got[2])
+	}
+	if slices.Equal(got[0], got[3]) {
+		t.Errorf("Embed() returned the same vector for different texts")
+	}
+}
+
+func TestNewGeminiEmbedder_NoModel(t *testing.T) {
+	if _, err := vectormemory.NewGeminiEmbedder(t.Context(), vectormemory.GeminiEmbedderConfig{}); err == nil {
+		t.Error("NewGeminiEmbedder() succeeded, want error")
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vectormemory provides a [memory.Service] that searches memories by
+// the cosine similarity of their embeddings.
+package vectormemory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+const (
+	defaultChunkSize = 200
+	defaultTopK      = 10
+	defaultBatchSize = 100
+)
+
+// Config configures the service returned by [NewService].
+type Config struct {
+	// Embedder computes the vectors of the stored chunks and of the queries.
+	// Required.
+	Embedder Embedder
+	// ChunkSize is the maximum number of words in a chunk. Events with longer
+	// text are split into several chunks. Defaults to 200.
+	ChunkSize int
+	// TopK is the maximum number of memories returned by Search.
+	// Defaults to 10.
+	TopK int
+	// MinScore is the similarity threshold. Only memories with a cosine
+	// similarity greater than MinScore are returned by Search.
+	MinScore float32
+	// BatchSize is the maximum number of texts passed to a single Embed
+	// call. Defaults to 100.
+	BatchSize int
+}
+
+// NewService returns a new in-memory vector implementation of the memory
+// service. Thread-safe.
+func NewService(cfg Config) (memory.Service, error) {
+	if cfg.Embedder == nil {
+		return nil, fmt.Errorf("embedder is required")
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = defaultChunkSize
+	}
+	if cfg.TopK <= 0 {
+		cfg.TopK = defaultTopK
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	return &vectorService{
+		cfg:   cfg,
+		store: make(map[key]map[string][]chunk),
+	}, nil
+}
+
+type key struct {
+	appName, userID string
+}
+
+type chunk struct {
+	content   *genai.Content
+	author    string
+	timestamp time.Time
+
+	vector []float32
+	norm   float64
+}
+
+// vectorService is an in-memory vector implementation of memory.Service.
+type vectorService struct {
+	cfg Config
+
+	mu sync.RWMutex
+	// store maps app and user to the chunks of each of their sessions.
+	store map[key]map[string][]chunk
+}
+
+func (s *vectorService) AddSession(ctx context.Context, curSession session.Session) error {
+	var chunks []chunk
+	var texts []string
+
+	for event := range curSession.Events().All() {
+		content := event.LLMResponse.Content
+		if content == nil {
+			continue
+		}
+
+		var sb strings.Builder
+		for _, part := range content.Parts {
+			if part.Text == "" {
+				continue
+			}
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(part.Text)
+		}
+		parts := splitChunks(sb.String(), s.cfg.ChunkSize)
+		for _, text := range parts {
+			c := chunk{
+				content:   content,
+				author:    event.Author,
+				timestamp: event.Timestamp,
+			}
+			if len(parts) > 1 {
+				c.content = genai.NewContentFromText(text, genai.Role(content.Role))
+			}
+			chunks = append(chunks, c)
+			texts = append(texts, text)
+		}
+	}
+
+	for start := 0; start < len(texts); start += s.cfg.BatchSize {
+		end := min(start+s.cfg.BatchSize, len(texts))
+		vectors, err := s.cfg.Embedder.Embed(ctx, texts[start:end], TaskDocument)
+		if err != nil {
+			return fmt.Errorf("failed to embed session %q: %w", curSession.ID(), err)
+		}
+		if len(vectors) != end-start {
+			return fmt.Errorf("failed to embed session %q: got %d vectors for %d chunks", curSession.ID(), len(vectors), end-start)
+		}
+		for i, v := range vectors {
+			chunks[start+i].vector = v
+			chunks[start+i].norm = norm(v)
+		}
+	}
+
+	k := key{
+		appName: curSession.AppName(),
+		userID:  curSession.UserID(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions, ok := s.store[k]
+	if !ok {
+		sessions = map[string][]chunk{}
+		s.store[k] = sessions
+	}
+	// Replace the previous chunks so that a session can be added again.
+	sessions[curSession.ID()] = chunks
+	return nil
+}
+
+func (s *vectorService) Search(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return &memory.SearchResponse{}, nil
+	}
+
+	k := key{
+		appName: req.AppName,
+		userID:  req.UserID,
+	}
+
+	s.mu.RLock()
+	_, ok := s.store[k]
+	s.mu.RUnlock()
+	if !ok {
+		return &memory.SearchResponse{}, nil
+	}
+
+	vectors, err := s.cfg.Embedder.Embed(ctx, []string{req.Query}, TaskQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("failed to embed query: got %d vectors", len(vectors))
+	}
+	query := vectors[0]
+	queryNorm := norm(query)
+
+	type match struct {
+		chunk *chunk
+		score float32
+	}
+	var matches []match
+
+	s.mu.RLock()
+	for _, chunks := range s.store[k] {
+		for i := range chunks {
+			score := cosine(query, queryNorm, chunks[i].vector, chunks[i].norm)
+			if score > s.cfg.MinScore {
+				matches = append(matches, match{chunk: &chunks[i], score: score})
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return a.chunk.timestamp.Compare(b.chunk.timestamp)
+	})
+	if len(matches) > s.cfg.TopK {
+		matches = matches[:s.cfg.TopK]
+	}
+
+	res := &memory.SearchResponse{}
+	for _, m := range matches {
+		res.Memories = append(res.Memories, memory.Entry{
+			Content:   m.chunk.content,
+			Author:    m.chunk.author,
+			Timestamp: m.chunk.timestamp,
+		})
+	}
+	return res, nil
+}
+
+// splitChunks splits text into chunks of at most size words.
+func splitChunks(text string, size int) []string {
+	words := strings.Fields(text)
+	var chunks []string
+	for chunk := range slices.Chunk(words, size) {
+		chunks = append(chunks, strings.Join(chunk, " "))
+	}
+	return chunks
+}
+
+func norm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
+
+// cosine returns the cosine similarity of a and b given their norms.
+// Vectors of different dimensions or with zero norm have zero similarity.
+func cosine(a []float32, normA float64, b []float32, normB float64) float32 {
+	if len(a) != len(b) || normA == 0 || normB == 0 {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return float32(dot / (normA * normB))
+}
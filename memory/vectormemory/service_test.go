@@ -0,0 +1,251 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectormemory_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/memory/vectormemory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestService_Search(t *testing.T) {
+	ts := func(day int) time.Time {
+		return time.Date(2025, 10, day, 10, 0, 0, 0, time.UTC)
+	}
+	event := func(author, text string, day int) *session.Event {
+		return &session.Event{
+			Author:      author,
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+			Timestamp:   ts(day),
+		}
+	}
+	entry := func(author, text string, day int) memory.Entry {
+		return memory.Entry{Content: genai.NewContentFromText(text, genai.RoleUser), Author: author, Timestamp: ts(day)}
+	}
+	sessions := []session.Session{
+		makeSession("app1", "user1", "sess1", []*session.Event{
+			event("user1", "My favourite colour is blue", 1),
+			event("bot", "Noted, blue it is", 2),
+			{Author: "bot", Timestamp: ts(3)},
+		}),
+		makeSession("app1", "user1", "sess2", []*session.Event{
+			event("user1", "I live in Zurich", 4),
+		}),
+		makeSession("app1", "user2", "sess3", []*session.Event{
+			event("user2", "My favourite colour is red", 5),
+		}),
+	}
+
+	tests := []struct {
+		name     string
+		cfg      vectormemory.Config
+		sessions []session.Session
+		req      *memory.SearchRequest
+		want     []memory.Entry
+	}{
+		{
+			name:     "ranked by similarity",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "what is my favourite colour"},
+			want: []memory.Entry{
+				entry("user1", "My favourite colour is blue", 1),
+				entry("bot", "Noted, blue it is", 2),
+			},
+		},
+		{
+			name:     "top k",
+			cfg:      vectormemory.Config{TopK: 1},
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "what is my favourite colour"},
+			want: []memory.Entry{
+				entry("user1", "My favourite colour is blue", 1),
+			},
+		},
+		{
+			name:     "min score",
+			cfg:      vectormemory.Config{MinScore: 0.5},
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "favourite colour blue"},
+			want: []memory.Entry{
+				entry("user1", "My favourite colour is blue", 1),
+			},
+		},
+		{
+			name: "long events are chunked",
+			cfg:  vectormemory.Config{ChunkSize: 3},
+			sessions: []session.Session{
+				makeSession("app1", "user1", "sess1", []*session.Event{
+					event("user1", "I live in Zurich and my dog is called Rex", 1),
+				}),
+			},
+			req: &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "dog"},
+			want: []memory.Entry{
+				entry("user1", "dog is called", 1),
+			},
+		},
+		{
+			name:     "no leakage for different app",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app2", UserID: "user1", Query: "favourite colour"},
+		},
+		{
+			name:     "no leakage for different user",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user2", Query: "zurich"},
+		},
+		{
+			name:     "empty query",
+			sessions: sessions,
+			req:      &memory.SearchRequest{AppName: "app1", UserID: "user1"},
+		},
+		{
+			name: "lookup on empty store",
+			req:  &memory.SearchRequest{AppName: "app1", UserID: "user1", Query: "colour"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Embedder = vectormemory.NewHashEmbedder(512)
+			s, err := vectormemory.NewService(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, sess := range tt.sessions {
+				if err := s.AddSession(t.Context(), sess); err != nil {
+					t.Fatalf("AddSession() error = %v", err)
+				}
+			}
+
+			got, err := s.Search(t.Context(), tt.req)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if diff := cmp.Diff(&memory.SearchResponse{Memories: tt.want}, got); diff != "" {
+				t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestService_AddSessionReplaces(t *testing.T) {
+	s, err := vectormemory.NewService(vectormemory.Config{Embedder: vectormemory.NewHashEmbedder(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"I live in Zurich", "I moved to Geneva"} {
+		sess := makeSession("app", "user", "sess", []*session.Event{
+			{LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)}},
+		})
+		if err := s.AddSession(t.Context(), sess); err != nil {
+			t.Fatalf("AddSession() error = %v", err)
+		}
+	}
+
+	got, err := s.Search(t.Context(), &memory.SearchRequest{AppName: "app", UserID: "user", Query: "Zurich"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := &memory.SearchResponse{}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestService_Batches(t *testing.T) {
+	embedder := &countingEmbedder{Embedder: vectormemory.NewHashEmbedder(0)}
+	s, err := vectormemory.NewService(vectormemory.Config{Embedder: embedder, BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []*session.Event
+	for _, text := range []string{"one", "two", "three", "four", "five"} {
+		events = append(events, &session.Event{LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)}})
+	}
+	if err := s.AddSession(t.Context(), makeSession("app", "user", "sess", events)); err != nil {
+		t.Fatalf("AddSession() error = %v", err)
+	}
+	if diff := cmp.Diff([]int{2, 2, 1}, embedder.batches); diff != "" {
+		t.Errorf("Embed() batch sizes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestService_EmbedError(t *testing.T) {
+	errEmbed := errors.New("quota exceeded")
+	s, err := vectormemory.NewService(vectormemory.Config{Embedder: &countingEmbedder{err: errEmbed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess := makeSession("app", "user", "sess", []*session.Event{
+		{LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello", genai.RoleUser)}},
+	})
+	if err := s.AddSession(t.Context(), sess); !errors.Is(err, errEmbed) {
+		t.Errorf("AddSession() error = %v, want %v", err, errEmbed)
+	}
+}
+
+func TestNewService_NoEmbedder(t *testing.T) {
+	if _, err := vectormemory.NewService(vectormemory.Config{}); err == nil {
+		t.Error("NewService() succeeded, want error")
+	}
+}
+
+type countingEmbedder struct {
+	vectormemory.Embedder
+	err     error
+	batches []int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string, task vectormemory.TaskType) ([][]float32, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.batches = append(e.batches, len(texts))
+	return e.Embedder.Embed(ctx, texts, task)
+}
+
+func makeSession(appName, userID, sessionID string, events []*session.Event) session.Session {
+	return &testSession{
+		appName:   appName,
+		userID:    userID,
+		sessionID: sessionID,
+		events:    events,
+	}
+}
+
+type testSession struct {
+	appName, userID, sessionID string
+	events                     []*session.Event
+}
+
+func (s *testSession) ID() string                    { return s.sessionID }
+func (s *testSession) AppName() string               { return s.appName }
+func (s *testSession) UserID() string                { return s.userID }
+func (s *testSession) Events() session.Events        { return s }
+func (s *testSession) All() iter.Seq[*session.Event] { return slices.Values(s.events) }
+func (s *testSession) Len() int                      { return len(s.events) }
+func (s *testSession) At(i int) *session.Event       { return s.events[i] }
+func (s *testSession) State() session.State          { panic("not implemented") }
+func (s *testSession) LastUpdateTime() time.Time     { panic("not implemented") }
@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anthropic implements the [model.LLM] interface for the Claude
+// models, with the Anthropic Messages API.
+package anthropic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the Anthropic API.
+	DefaultBaseURL = "https://api.anthropic.com"
+	// DefaultMaxTokens is the maximum number of tokens to generate when
+	// the request doesn't set MaxOutputTokens, as the API requires it.
+	DefaultMaxTokens = 4096
+
+	apiVersion = "2023-06-01"
+)
+
+// ClientConfig configures the access to the Messages API.
+type ClientConfig struct {
+	// BaseURL is the base URL of the API. Defaults to DefaultBaseURL.
+	BaseURL string
+	// APIKey is sent in the x-api-key header. Defaults to the
+	// ANTHROPIC_API_KEY environment variable.
+	APIKey string
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+type anthropicModel struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewModel returns [model.LLM], backed by the Anthropic Messages API.
+//
+// The modelName specifies which model to target (e.g.,
+// "claude-sonnet-4-5"). The cfg is optional.
+func NewModel(ctx context.Context, modelName string, cfg *ClientConfig) (model.LLM, error) {
+	if modelName == "" {
+		return nil, fmt.Errorf("model name is required")
+	}
+	m := &anthropicModel{
+		name:       modelName,
+		baseURL:    DefaultBaseURL,
+		apiKey:     os.Getenv("ANTHROPIC_API_KEY"),
+		httpClient: http.DefaultClient,
+	}
+	if cfg != nil {
+		if cfg.BaseURL != "" {
+			m.baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+		}
+		if cfg.APIKey != "" {
+			m.apiKey = cfg.APIKey
+		}
+		if cfg.HTTPClient != nil {
+			m.httpClient = cfg.HTTPClient
+		}
+	}
+	return m, nil
+}
+
+func (m *anthropicModel) Name() string {
+	return m.name
+}
+
+// GenerateContent calls the underlying model.
+func (m *anthropicModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		msgReq, err := toMessagesRequest(m.name, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		msgReq.Stream = stream
+
+		body, err := m.send(ctx, msgReq)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		if !stream {
+			var resp message
+			if err := json.NewDecoder(body).Decode(&resp); err != nil {
+				yield(nil, fmt.Errorf("failed to decode response: %w", err))
+				return
+			}
+			llmResp, err := toLLMResponse(&resp)
+			yield(llmResp, err)
+			return
+		}
+
+		for resp, err := range readStream(body) {
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// send posts the request to the messages endpoint and returns the response
+// body.
+func (m *anthropicModel) send(ctx context.Context, msgReq *messagesRequest) (io.ReadCloser, error) {
+	data, err := json.Marshal(msgReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/v1/messages", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Anthropic-Version", apiVersion)
+	if m.apiKey != "" {
+		httpReq.Header.Set("X-Api-Key", m.apiKey)
+	}
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call model: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, newAPIError(httpResp)
+	}
+	return httpResp.Body, nil
+}
+
+// APIError is returned when the API responds with an error.
+type APIError struct {
+	// StatusCode is 0 for the errors sent in the stream.
+	StatusCode int
+	Type       string
+	Message    string
+	// RetryDelay is the delay requested by the Retry-After header.
+	RetryDelay time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("anthropic API error (%s): %s", e.Type, e.Message)
+	}
+	if e.Type != "" {
+		return fmt.Sprintf("anthropic API error %d (%s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("anthropic API error %d: %s", e.StatusCode, e.Message)
+}
+
+// HTTPStatusCode implements [model.HTTPStatusError].
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// RetryAfter implements [model.RetryAfterError].
+func (e *APIError) RetryAfter() time.Duration {
+	return e.RetryDelay
+}
+
+func newAPIError(httpResp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	apiErr := &APIError{
+		StatusCode: httpResp.StatusCode,
+		Message:    strings.TrimSpace(string(data)),
+		RetryDelay: model.ParseRetryAfter(httpResp.Header.Get("Retry-After")),
+	}
+	var body errorResponse
+	if err := json.Unmarshal(data, &body); err == nil && body.Error != nil {
+		apiErr.Type = body.Error.Type
+		apiErr.Message = body.Error.Message
+	}
+	return apiErr
+}
+
+// readStream yields the partial text responses of the server-sent events
+// stream, followed by the aggregated response once the message stops.
+func readStream(body io.Reader) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		aggregator := &streamAggregator{}
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			var event streamEvent
+			if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+				yield(nil, fmt.Errorf("failed to decode stream event: %w", err))
+				return
+			}
+			if event.Type == "error" && event.Error != nil {
+				yield(nil, &APIError{Type: event.Error.Type, Message: event.Error.Message})
+				return
+			}
+			partial, err := aggregator.add(&event)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if partial != nil && !yield(partial, nil) {
+				return
+			}
+			if event.Type == "message_stop" {
+				break
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read stream: %w", err))
+			return
+		}
+		resp, err := aggregator.close()
+		yield(resp, err)
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/httprr"
+	"google.golang.org/adk/model"
+)
+
+//go:generate go test -httprecord=testdata/.*\.httprr
+
+var weatherTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{{
+		Name:        "get_weather",
+		Description: "Returns the current weather in the city.",
+		Parameters: &genai.Schema{
+			Type:       genai.TypeObject,
+			Properties: map[string]*genai.Schema{"city": {Type: genai.TypeString}},
+			Required:   []string{"city"},
+		},
+	}},
+}
+
+func TestModel_Generate(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelName string
+		req       *model.LLMRequest
+		want      *model.LLMResponse
+		wantErr   bool
+	}{
+		{
+			name:      "ok",
+			modelName: "claude-sonnet-4-5",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? One word."),
+				Config: &genai.GenerateContentConfig{
+					SystemInstruction: genai.NewContentFromText("You are a geography expert.", genai.RoleUser),
+					Temperature:       new(float32),
+					MaxOutputTokens:   256,
+				},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("Paris", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     25,
+					CandidatesTokenCount: 4,
+					TotalTokenCount:      29,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "function_call",
+			modelName: "claude-sonnet-4-5",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the weather in Paris?"),
+				Config: &genai.GenerateContentConfig{
+					Tools:       []*genai.Tool{weatherTool},
+					Temperature: new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: &genai.Content{
+					Role: genai.RoleModel,
+					Parts: []*genai.Part{
+						genai.NewPartFromText("I'll check the weather in Paris."),
+						{FunctionCall: &genai.FunctionCall{
+							ID:   "toolu_01D7FLrfh4GYq7yT1ULFeyMV",
+							Name: "get_weather",
+							Args: map[string]any{"city": "Paris"},
+						}},
+					},
+				},
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     412,
+					CandidatesTokenCount: 62,
+					TotalTokenCount:      474,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "function_response",
+			modelName: "claude-sonnet-4-5",
+			req: &model.LLMRequest{
+				Contents: []*genai.Content{
+					genai.NewContentFromText("What is the weather in Paris?", genai.RoleUser),
+					{
+						Role: genai.RoleModel,
+						Parts: []*genai.Part{
+							genai.NewPartFromText("I'll check the weather in Paris."),
+							{FunctionCall: &genai.FunctionCall{
+								ID:   "toolu_01D7FLrfh4GYq7yT1ULFeyMV",
+								Name: "get_weather",
+								Args: map[string]any{"city": "Paris"},
+							}},
+						},
+					},
+					{
+						Role: genai.RoleUser,
+						Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+							ID:       "toolu_01D7FLrfh4GYq7yT1ULFeyMV",
+							Name:     "get_weather",
+							Response: map[string]any{"weather": "sunny, 22°C"},
+						}}},
+					},
+				},
+				Config: &genai.GenerateContentConfig{
+					Tools:       []*genai.Tool{weatherTool},
+					Temperature: new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("It is sunny in Paris, with a temperature of 22°C.", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     498,
+					CandidatesTokenCount: 19,
+					TotalTokenCount:      517,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "image",
+			modelName: "claude-sonnet-4-5",
+			req: &model.LLMRequest{
+				Contents: []*genai.Content{{
+					Role: genai.RoleUser,
+					Parts: []*genai.Part{
+						genai.NewPartFromBytes(redPixelPNG, "image/png"),
+						genai.NewPartFromText("What color is this image? One word."),
+					},
+				}},
+				Config: &genai.GenerateContentConfig{Temperature: new(float32)},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("Red", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     24,
+					CandidatesTokenCount: 4,
+					TotalTokenCount:      28,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "max_tokens",
+			modelName: "claude-sonnet-4-5",
+			req: &model.LLMRequest{
+				Contents: genai.Text("Describe Paris."),
+				Config: &genai.GenerateContentConfig{
+					Temperature:     new(float32),
+					MaxOutputTokens: 5,
+				},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("Paris, the capital", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     11,
+					CandidatesTokenCount: 5,
+					TotalTokenCount:      16,
+				},
+				FinishReason: genai.FinishReasonMaxTokens,
+			},
+		},
+		{
+			name:      "unknown_model",
+			modelName: "claude-unknown",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? One word."),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testModel, err := NewModel(t.Context(), tt.modelName, newTestClientConfig(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for got, err := range testModel.GenerateContent(t.Context(), tt.req, false) {
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Model.Generate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("Model.Generate() = %v, want %v\ndiff(-want +got):\n%v", got, tt.want, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_GenerateStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *model.LLMRequest
+		wantPartial []string
+		want        *model.LLMResponse
+	}{
+		{
+			name: "ok",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? Answer in a sentence."),
+				Config:   &genai.GenerateContentConfig{Temperature: new(float32)},
+			},
+			wantPartial: []string{"The capital of France", " is Paris."},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("The capital of France is Paris.", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     20,
+					CandidatesTokenCount: 10,
+					TotalTokenCount:      30,
+				},
+				FinishReason: genai.FinishReasonStop,
+				TurnComplete: true,
+			},
+		},
+		{
+			name: "function_call",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the weather in Paris?"),
+				Config: &genai.GenerateContentConfig{
+					Tools:       []*genai.Tool{weatherTool},
+					Temperature: new(float32),
+				},
+			},
+			wantPartial: []string{"I'll check the weather", " in Paris."},
+			want: &model.LLMResponse{
+				Content: &genai.Content{
+					Role: genai.RoleModel,
+					Parts: []*genai.Part{
+						genai.NewPartFromText("I'll check the weather in Paris."),
+						{FunctionCall: &genai.FunctionCall{
+							ID:   "toolu_01T1x1fJ34qAmk2tNTrN7Up6",
+							Name: "get_weather",
+							Args: map[string]any{"city": "Paris"},
+						}},
+					},
+				},
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     412,
+					CandidatesTokenCount: 62,
+					TotalTokenCount:      474,
+				},
+				FinishReason: genai.FinishReasonStop,
+				TurnComplete: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testModel, err := NewModel(t.Context(), "claude-sonnet-4-5", newTestClientConfig(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotPartial []string
+			var got *model.LLMResponse
+			for resp, err := range testModel.GenerateContent(t.Context(), tt.req, true) {
+				if err != nil {
+					t.Fatalf("Model.GenerateStream() error = %v", err)
+				}
+				if resp.Partial {
+					gotPartial = append(gotPartial, resp.Content.Parts[0].Text)
+					continue
+				}
+				if got != nil {
+					t.Fatalf("Model.GenerateStream() yielded more than one final response: %v, %v", got, resp)
+				}
+				got = resp
+			}
+			if diff := cmp.Diff(tt.wantPartial, gotPartial); diff != "" {
+				t.Errorf("Model.GenerateStream() partial texts mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Model.GenerateStream() = %v, want %v\ndiff(-want +got):\n%v", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestToMessagesRequest(t *testing.T) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			genai.NewContentFromText("Hi.", genai.RoleUser),
+			genai.NewContentFromText("What is the weather in Paris?", genai.RoleUser),
+			{
+				Role: genai.RoleModel,
+				Parts: []*genai.Part{
+					{Text: "The user asks for the weather.", Thought: true, ThoughtSignature: []byte("sig")},
+					{Text: "Unsigned thought.", Thought: true},
+					{FunctionCall: &genai.FunctionCall{ID: "toolu_1", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+				},
+			},
+			{
+				Role: genai.RoleUser,
+				Parts: []*genai.Part{
+					genai.NewPartFromText("Be brief."),
+					{FunctionResponse: &genai.FunctionResponse{ID: "toolu_1", Name: "get_weather", Response: map[string]any{"error": "unavailable"}}},
+				},
+			},
+		},
+		Config: &genai.GenerateContentConfig{
+			TopK:          genai.Ptr[float32](5),
+			StopSequences: []string{"END"},
+		},
+	}
+	got, err := toMessagesRequest("claude-sonnet-4-5", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &messagesRequest{
+		Model:     "claude-sonnet-4-5",
+		MaxTokens: DefaultMaxTokens,
+		Messages: []messageParam{
+			{Role: "user", Content: []contentBlock{
+				{Type: "text", Text: "Hi."},
+				{Type: "text", Text: "What is the weather in Paris?"},
+			}},
+			{Role: "assistant", Content: []contentBlock{
+				{Type: "thinking", Thinking: "The user asks for the weather.", Signature: "sig"},
+				{Type: "tool_use", ID: "toolu_1", Name: "get_weather", Input: map[string]any{"city": "Paris"}},
+			}},
+			{Role: "user", Content: []contentBlock{
+				{Type: "tool_result", ToolUseID: "toolu_1", Content: `{"error":"unavailable"}`, IsError: true},
+				{Type: "text", Text: "Be brief."},
+			}},
+		},
+		TopK:          genai.Ptr[int32](5),
+		StopSequences: []string{"END"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("toMessagesRequest() mismatch (-want +got):\n%s", diff)
+	}
+
+	unsupported := []*model.LLMRequest{
+		{Contents: []*genai.Content{{Role: "system", Parts: []*genai.Part{genai.NewPartFromText("hi")}}}},
+		{Contents: []*genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{genai.NewPartFromBytes([]byte("wav"), "audio/wav")}}}},
+		{Config: &genai.GenerateContentConfig{Tools: []*genai.Tool{{GoogleSearch: &genai.GoogleSearch{}}}}},
+		{Config: &genai.GenerateContentConfig{ResponseSchema: &genai.Schema{Type: genai.TypeString}}},
+	}
+	for _, req := range unsupported {
+		if _, err := toMessagesRequest("claude-sonnet-4-5", req); err == nil {
+			t.Errorf("toMessagesRequest(%v) succeeded, want error", req)
+		}
+	}
+}
+
+func TestToFinishReason(t *testing.T) {
+	for reason, want := range map[string]genai.FinishReason{
+		"end_turn":      genai.FinishReasonStop,
+		"stop_sequence": genai.FinishReasonStop,
+		"tool_use":      genai.FinishReasonStop,
+		"max_tokens":    genai.FinishReasonMaxTokens,
+		"refusal":       genai.FinishReasonSafety,
+		"unknown":       genai.FinishReasonOther,
+	} {
+		if got := toFinishReason(reason); got != want {
+			t.Errorf("toFinishReason(%q) = %q, want %q", reason, got, want)
+		}
+	}
+}
+
+// redPixelPNG is a 1x1 red PNG image.
+var redPixelPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00\x90wS\xde\x00\x00\x00\x0cIDATx\x9cc\xf8\xcf\xc0\x00\x00\x03\x01\x01\x00\xc9\xfe\x92\xef\x00\x00\x00\x00IEND\xaeB`\x82")
+
+// newTestClientConfig returns the ClientConfig configured for record and
+// replay of the test.
+func newTestClientConfig(t *testing.T) *ClientConfig {
+	t.Helper()
+	rrfile := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".httprr")
+	rr, err := httprr.Open(rrfile, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr.ScrubReq(func(req *http.Request) error {
+		req.Header.Del("X-Api-Key")
+		return nil
+	})
+	apiKey := ""
+	if recording, _ := httprr.Recording(rrfile); !recording {
+		apiKey = "fakekey"
+	}
+	return &ClientConfig{
+		HTTPClient: &http.Client{Transport: rr},
+		APIKey:     apiKey,
+	}
+}
@@ -0,0 +1,496 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// messagesRequest is the body of the messages requests.
+type messagesRequest struct {
+	Model         string         `json:"model"`
+	MaxTokens     int32          `json:"max_tokens"`
+	System        string         `json:"system,omitempty"`
+	Messages      []messageParam `json:"messages"`
+	Tools         []toolParam    `json:"tools,omitempty"`
+	Temperature   *float32       `json:"temperature,omitempty"`
+	TopP          *float32       `json:"top_p,omitempty"`
+	TopK          *int32         `json:"top_k,omitempty"`
+	StopSequences []string       `json:"stop_sequences,omitempty"`
+	Stream        bool           `json:"stream,omitempty"`
+}
+
+type messageParam struct {
+	Role    string         `json:"role"`
+	Content []contentBlock `json:"content"`
+}
+
+// contentBlock is a block of the message content. The fields set depend on
+// the Type of the block.
+type contentBlock struct {
+	Type string `json:"type"`
+
+	// text
+	Text string `json:"text,omitempty"`
+	// image and document
+	Source *blockSource `json:"source,omitempty"`
+	// tool_use
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Input any    `json:"input,omitempty"`
+	// tool_result
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+	// thinking
+	Thinking  string `json:"thinking,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+type blockSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type,omitempty"`
+	Data      string `json:"data,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+type toolParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+// message is the body of the messages responses.
+type message struct {
+	ID         string         `json:"id"`
+	Model      string         `json:"model"`
+	Content    []contentBlock `json:"content"`
+	StopReason string         `json:"stop_reason"`
+	Usage      *usage         `json:"usage"`
+}
+
+type usage struct {
+	InputTokens              int32 `json:"input_tokens"`
+	OutputTokens             int32 `json:"output_tokens"`
+	CacheCreationInputTokens int32 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int32 `json:"cache_read_input_tokens"`
+}
+
+type errorResponse struct {
+	Error *apiError `json:"error"`
+}
+
+type apiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// streamEvent is an event of the streamed responses.
+type streamEvent struct {
+	Type         string        `json:"type"`
+	Message      *message      `json:"message"`
+	Index        int           `json:"index"`
+	ContentBlock *contentBlock `json:"content_block"`
+	Delta        *streamDelta  `json:"delta"`
+	Usage        *usage        `json:"usage"`
+	Error        *apiError     `json:"error"`
+}
+
+type streamDelta struct {
+	Type        string `json:"type"`
+	Text        string `json:"text"`
+	PartialJSON string `json:"partial_json"`
+	Thinking    string `json:"thinking"`
+	Signature   string `json:"signature"`
+	StopReason  string `json:"stop_reason"`
+}
+
+// toMessagesRequest converts the LLM request to a messages request.
+func toMessagesRequest(modelName string, req *model.LLMRequest) (*messagesRequest, error) {
+	msgReq := &messagesRequest{Model: modelName, MaxTokens: DefaultMaxTokens}
+
+	if cfg := req.Config; cfg != nil {
+		if cfg.ResponseSchema != nil || cfg.ResponseJsonSchema != nil {
+			return nil, fmt.Errorf("response schema is not supported")
+		}
+		msgReq.System = contentText(cfg.SystemInstruction)
+		if cfg.MaxOutputTokens > 0 {
+			msgReq.MaxTokens = cfg.MaxOutputTokens
+		}
+		msgReq.Temperature = cfg.Temperature
+		msgReq.TopP = cfg.TopP
+		if cfg.TopK != nil {
+			topK := int32(*cfg.TopK)
+			msgReq.TopK = &topK
+		}
+		msgReq.StopSequences = cfg.StopSequences
+
+		for _, t := range cfg.Tools {
+			if t == nil {
+				continue
+			}
+			if len(t.FunctionDeclarations) == 0 {
+				return nil, fmt.Errorf("unsupported tool: only function declarations are supported")
+			}
+			for _, decl := range t.FunctionDeclarations {
+				msgReq.Tools = append(msgReq.Tools, toToolParam(decl))
+			}
+		}
+	}
+
+	for _, content := range req.Contents {
+		if content == nil {
+			continue
+		}
+		var role string
+		switch content.Role {
+		case genai.RoleUser, "":
+			role = "user"
+		case genai.RoleModel:
+			role = "assistant"
+		default:
+			return nil, fmt.Errorf("unsupported content role %q", content.Role)
+		}
+		blocks, err := toContentBlocks(content)
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			continue
+		}
+		// The roles of the messages must alternate.
+		if n := len(msgReq.Messages); n > 0 && msgReq.Messages[n-1].Role == role {
+			last := &msgReq.Messages[n-1]
+			last.Content = append(last.Content, blocks...)
+			continue
+		}
+		msgReq.Messages = append(msgReq.Messages, messageParam{Role: role, Content: blocks})
+	}
+	for i := range msgReq.Messages {
+		sortToolResultsFirst(msgReq.Messages[i].Content)
+	}
+	return msgReq, nil
+}
+
+// toContentBlocks converts the parts of the content to content blocks.
+func toContentBlocks(content *genai.Content) ([]contentBlock, error) {
+	var blocks []contentBlock
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			input := part.FunctionCall.Args
+			if input == nil {
+				input = map[string]any{}
+			}
+			blocks = append(blocks, contentBlock{Type: "tool_use", ID: part.FunctionCall.ID, Name: part.FunctionCall.Name, Input: input})
+		case part.FunctionResponse != nil:
+			data, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response of function %q: %w", part.FunctionResponse.Name, err)
+			}
+			_, isError := part.FunctionResponse.Response["error"]
+			blocks = append(blocks, contentBlock{Type: "tool_result", ToolUseID: part.FunctionResponse.ID, Content: string(data), IsError: isError})
+		case part.Thought:
+			// The thinking blocks are only sent back with their signature,
+			// the API rejects them otherwise.
+			if part.Text != "" && len(part.ThoughtSignature) > 0 {
+				blocks = append(blocks, contentBlock{Type: "thinking", Thinking: part.Text, Signature: string(part.ThoughtSignature)})
+			}
+		case part.Text != "":
+			blocks = append(blocks, contentBlock{Type: "text", Text: part.Text})
+		case part.InlineData != nil:
+			block, err := mediaBlock(part.InlineData.MIMEType, &blockSource{
+				Type:      "base64",
+				MediaType: part.InlineData.MIMEType,
+				Data:      base64.StdEncoding.EncodeToString(part.InlineData.Data),
+			})
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+		case part.FileData != nil:
+			block, err := mediaBlock(part.FileData.MIMEType, &blockSource{Type: "url", URL: part.FileData.FileURI})
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+func mediaBlock(mimeType string, source *blockSource) (contentBlock, error) {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return contentBlock{Type: "image", Source: source}, nil
+	case mimeType == "application/pdf":
+		return contentBlock{Type: "document", Source: source}, nil
+	default:
+		return contentBlock{}, fmt.Errorf("unsupported data MIME type %q", mimeType)
+	}
+}
+
+// sortToolResultsFirst moves the tool results to the beginning of the
+// content, as the API requires.
+func sortToolResultsFirst(blocks []contentBlock) {
+	n := 0
+	for i, block := range blocks {
+		if block.Type != "tool_result" {
+			continue
+		}
+		copy(blocks[n+1:i+1], blocks[n:i])
+		blocks[n] = block
+		n++
+	}
+}
+
+func toToolParam(decl *genai.FunctionDeclaration) toolParam {
+	var schema any = map[string]any{"type": "object", "properties": map[string]any{}}
+	switch {
+	case decl.ParametersJsonSchema != nil:
+		schema = decl.ParametersJsonSchema
+	case decl.Parameters != nil:
+		schema = schemaToJSON(decl.Parameters)
+	}
+	return toolParam{Name: decl.Name, Description: decl.Description, InputSchema: schema}
+}
+
+// schemaToJSON converts the Gemini schema to a JSON schema.
+func schemaToJSON(s *genai.Schema) map[string]any {
+	out := map[string]any{}
+	if s.Type != genai.TypeUnspecified {
+		typ := strings.ToLower(string(s.Type))
+		if s.Nullable != nil && *s.Nullable {
+			out["type"] = []string{typ, "null"}
+		} else {
+			out["type"] = typ
+		}
+	}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if s.Format != "" {
+		out["format"] = s.Format
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Items != nil {
+		out["items"] = schemaToJSON(s.Items)
+	}
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			props[name] = schemaToJSON(prop)
+		}
+		out["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	if len(s.AnyOf) > 0 {
+		anyOf := make([]any, len(s.AnyOf))
+		for i, sub := range s.AnyOf {
+			anyOf[i] = schemaToJSON(sub)
+		}
+		out["anyOf"] = anyOf
+	}
+	return out
+}
+
+func contentText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toLLMResponse converts the message of the response.
+func toLLMResponse(msg *message) (*model.LLMResponse, error) {
+	content := &genai.Content{Role: genai.RoleModel}
+	for _, block := range msg.Content {
+		switch block.Type {
+		case "text":
+			content.Parts = append(content.Parts, genai.NewPartFromText(block.Text))
+		case "thinking":
+			content.Parts = append(content.Parts, &genai.Part{Text: block.Thinking, Thought: true, ThoughtSignature: []byte(block.Signature)})
+		case "tool_use":
+			args, ok := block.Input.(map[string]any)
+			if !ok && block.Input != nil {
+				return nil, fmt.Errorf("invalid input of tool use %q: %v", block.Name, block.Input)
+			}
+			if args == nil {
+				args = map[string]any{}
+			}
+			content.Parts = append(content.Parts, &genai.Part{FunctionCall: &genai.FunctionCall{
+				ID:   block.ID,
+				Name: block.Name,
+				Args: args,
+			}})
+		}
+	}
+	return &model.LLMResponse{
+		Content:       content,
+		UsageMetadata: toUsageMetadata(msg.Usage),
+		FinishReason:  toFinishReason(msg.StopReason),
+	}, nil
+}
+
+func toFinishReason(reason string) genai.FinishReason {
+	switch reason {
+	case "":
+		return genai.FinishReasonUnspecified
+	case "end_turn", "stop_sequence", "tool_use", "pause_turn":
+		return genai.FinishReasonStop
+	case "max_tokens", "model_context_window_exceeded":
+		return genai.FinishReasonMaxTokens
+	case "refusal":
+		return genai.FinishReasonSafety
+	default:
+		return genai.FinishReasonOther
+	}
+}
+
+func toUsageMetadata(u *usage) *genai.GenerateContentResponseUsageMetadata {
+	if u == nil {
+		return nil
+	}
+	// The input tokens exclude the cached tokens.
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        prompt,
+		CachedContentTokenCount: u.CacheReadInputTokens,
+		CandidatesTokenCount:    u.OutputTokens,
+		TotalTokenCount:         prompt + u.OutputTokens,
+	}
+}
+
+// streamAggregator aggregates the events of the stream into the message.
+type streamAggregator struct {
+	msg         *message
+	partialJSON map[int]*strings.Builder
+	stopped     bool
+}
+
+// add aggregates the event, returning a partial response with its text if
+// any.
+func (a *streamAggregator) add(event *streamEvent) (*model.LLMResponse, error) {
+	switch event.Type {
+	case "message_start":
+		if event.Message == nil {
+			return nil, fmt.Errorf("message_start event without message")
+		}
+		a.msg = event.Message
+		a.msg.Content = nil
+		a.partialJSON = make(map[int]*strings.Builder)
+		return nil, nil
+	case "ping":
+		return nil, nil
+	}
+	if a.msg == nil {
+		return nil, fmt.Errorf("%s event before message_start", event.Type)
+	}
+
+	switch event.Type {
+	case "content_block_start":
+		if event.ContentBlock == nil || event.Index != len(a.msg.Content) {
+			return nil, fmt.Errorf("invalid content_block_start event at index %d", event.Index)
+		}
+		a.msg.Content = append(a.msg.Content, *event.ContentBlock)
+	case "content_block_delta":
+		if event.Delta == nil || event.Index < 0 || event.Index >= len(a.msg.Content) {
+			return nil, fmt.Errorf("invalid content_block_delta event at index %d", event.Index)
+		}
+		block := &a.msg.Content[event.Index]
+		switch event.Delta.Type {
+		case "text_delta":
+			block.Text += event.Delta.Text
+			return &model.LLMResponse{
+				Content: genai.NewContentFromText(event.Delta.Text, genai.RoleModel),
+				Partial: true,
+			}, nil
+		case "thinking_delta":
+			block.Thinking += event.Delta.Thinking
+			return &model.LLMResponse{
+				Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{Text: event.Delta.Thinking, Thought: true}}},
+				Partial: true,
+			}, nil
+		case "signature_delta":
+			block.Signature += event.Delta.Signature
+		case "input_json_delta":
+			b, ok := a.partialJSON[event.Index]
+			if !ok {
+				b = &strings.Builder{}
+				a.partialJSON[event.Index] = b
+			}
+			b.WriteString(event.Delta.PartialJSON)
+		}
+	case "content_block_stop":
+		b, ok := a.partialJSON[event.Index]
+		if !ok || event.Index >= len(a.msg.Content) {
+			return nil, nil
+		}
+		var input map[string]any
+		if s := strings.TrimSpace(b.String()); s != "" {
+			if err := json.Unmarshal([]byte(s), &input); err != nil {
+				return nil, fmt.Errorf("failed to parse input of tool use %q: %w", a.msg.Content[event.Index].Name, err)
+			}
+		}
+		a.msg.Content[event.Index].Input = input
+	case "message_delta":
+		if event.Delta != nil && event.Delta.StopReason != "" {
+			a.msg.StopReason = event.Delta.StopReason
+		}
+		if event.Usage != nil {
+			if a.msg.Usage == nil {
+				a.msg.Usage = &usage{}
+			}
+			// The usage of the delta is cumulative.
+			a.msg.Usage.OutputTokens = event.Usage.OutputTokens
+			if event.Usage.InputTokens > 0 {
+				a.msg.Usage.InputTokens = event.Usage.InputTokens
+			}
+		}
+	case "message_stop":
+		a.stopped = true
+	}
+	return nil, nil
+}
+
+// close returns the aggregated response of the stream.
+func (a *streamAggregator) close() (*model.LLMResponse, error) {
+	if a.msg == nil || !a.stopped {
+		return nil, fmt.Errorf("stream ended before message_stop")
+	}
+	resp, err := toLLMResponse(a.msg)
+	if err != nil {
+		return nil, err
+	}
+	resp.TurnComplete = true
+	return resp, nil
+}
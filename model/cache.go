@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "time"
+
+// ContextCacheConfig configures the context caching: the models supporting
+// it store the stable prefix of the requests, i.e. the system instruction,
+// the tools and the first contents, and reuse it across the requests of an
+// agent instead of sending it again.
+//
+// A cache is created when the same prefix is sent twice in a row, and
+// replaced when the prefix changes, expires or was used in CacheIntervals
+// invocations.
+type ContextCacheConfig struct {
+	// CacheIntervals is the maximum number of invocations a cache is used
+	// in before being refreshed. Defaults to 10.
+	CacheIntervals int
+	// TTL is the time to live of the caches. Defaults to 30 minutes.
+	TTL time.Duration
+	// MinTokens is the minimum prompt token count of the previous request
+	// to create a cache, as small prompts do not benefit from caching.
+	MinTokens int32
+}
+
+// CacheMetadata describes a context cache.
+type CacheMetadata struct {
+	// CacheName is the resource name of the cache. It is empty if no cache
+	// was created yet, the metadata then only holds the fingerprint of the
+	// cacheable prefix of the request.
+	CacheName string `json:"cacheName,omitempty"`
+	// ExpireTime is the expiration time of the cache.
+	ExpireTime time.Time `json:"expireTime,omitzero"`
+	// Fingerprint identifies the cached prefix: the system instruction, the
+	// tools and the first ContentsCount contents.
+	Fingerprint string `json:"fingerprint"`
+	// InvocationsUsed is the number of invocations the cache was used in.
+	InvocationsUsed int `json:"invocationsUsed,omitempty"`
+	// ContentsCount is the number of contents of the cached prefix.
+	ContentsCount int `json:"contentsCount"`
+	// CreateTime is the creation time of the cache.
+	CreateTime time.Time `json:"createTime,omitzero"`
+}
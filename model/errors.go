@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"google.golang.org/genai"
+)
+
+// TruncatedResponseError is returned when the response of the model was
+// truncated, e.g. because it reached the max output tokens.
+type TruncatedResponseError struct {
+	// FinishReason is the reason the model stopped generating. It is empty
+	// if the stream of responses ended without a final response.
+	FinishReason genai.FinishReason
+	// Response is the truncated response.
+	Response *LLMResponse
+}
+
+func (e *TruncatedResponseError) Error() string {
+	if e.FinishReason == "" {
+		return "model response truncated: the stream ended with partial responses"
+	}
+	return fmt.Sprintf("model response truncated: finish reason %s", e.FinishReason)
+}
+
+// HTTPStatusError is implemented by the errors of the model calls failed
+// with an HTTP error status.
+type HTTPStatusError interface {
+	error
+	HTTPStatusCode() int
+}
+
+// IsRetryableError reports whether the error of a model call is transient,
+// e.g. a rate limit or a server error, so the call may succeed if retried.
+//
+// The errors with an HTTP status, i.e. [genai.APIError] and the errors
+// implementing [HTTPStatusError], are retryable for the statuses 408, 429
+// and 5xx. The network timeouts are retryable, the cancellations are not.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) {
+		return isRetryableStatus(genaiErr.Code)
+	}
+	var genaiErrPtr *genai.APIError
+	if errors.As(err, &genaiErrPtr) {
+		return isRetryableStatus(genaiErrPtr.Code)
+	}
+	var statusErr HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableStatus(statusErr.HTTPStatusCode())
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakellm provides a scripted fake [model.LLM] to test agents
+// deterministically, without calling a real model.
+//
+// The tests declare the ordered turns of the conversation: the expectations
+// on each request sent to the model, and the responses to return.
+//
+//	llm := fakellm.New(t,
+//		fakellm.Turn{
+//			Expect:    []fakellm.Matcher{fakellm.LastUserText("Weather in Paris?"), fakellm.HasTools("get_weather")},
+//			Responses: fakellm.FunctionCall("get_weather", map[string]any{"city": "Paris"}),
+//		},
+//		fakellm.Turn{
+//			Expect:    []fakellm.Matcher{fakellm.LastFunctionResponse("get_weather", map[string]any{"weather": "sunny"})},
+//			Responses: fakellm.Stream("It is ", "sunny."),
+//		},
+//	)
+//
+// The requests not matching the expectations of their turn, the calls
+// beyond the script and the turns not called by the end of the test are
+// reported as test errors.
+package fakellm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// ErrUnexpectedCall is returned by the calls beyond the script or not
+// matching the expectations of their turn.
+var ErrUnexpectedCall = errors.New("unexpected model call")
+
+// Turn is a scripted model call.
+type Turn struct {
+	// Expect are the expectations on the request. All of them must match.
+	Expect []Matcher
+	// Responses are yielded in order. The partial responses are only
+	// yielded to the streaming calls.
+	Responses []*model.LLMResponse
+	// Err is yielded after the responses, if set.
+	Err error
+}
+
+// Model is a fake [model.LLM] responding with the scripted turns, in order.
+// It is safe for concurrent use, e.g. by parallel agents, although the
+// order of the concurrent calls is then not deterministic.
+type Model struct {
+	t     testing.TB
+	turns []Turn
+
+	mu       sync.Mutex
+	requests []*model.LLMRequest
+}
+
+// New creates a [Model] responding with the turns. The turns not called
+// are reported as errors of t when the test ends.
+func New(t testing.TB, turns ...Turn) *Model {
+	t.Helper()
+	m := &Model{t: t, turns: turns}
+	t.Cleanup(func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if called := len(m.requests); called < len(m.turns) {
+			t.Errorf("fakellm: %d of %d scripted model calls were made, the turns %d to %d were not called", called, len(m.turns), called+1, len(m.turns))
+		}
+	})
+	return m
+}
+
+// Name implements [model.LLM].
+func (m *Model) Name() string {
+	return "fakellm"
+}
+
+// GenerateContent implements [model.LLM]. It checks the request against
+// the next turn and yields its responses.
+func (m *Model) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.mu.Lock()
+	i := len(m.requests)
+	m.requests = append(m.requests, req)
+	m.mu.Unlock()
+
+	return func(yield func(*model.LLMResponse, error) bool) {
+		if i >= len(m.turns) {
+			m.t.Errorf("fakellm: unexpected model call %d, the script has %d turns; request:\n%s", i+1, len(m.turns), FormatRequest(req))
+			yield(nil, fmt.Errorf("call %d: %w", i+1, ErrUnexpectedCall))
+			return
+		}
+		turn := m.turns[i]
+		var mismatches []string
+		for _, match := range turn.Expect {
+			if err := match(req); err != nil {
+				mismatches = append(mismatches, err.Error())
+			}
+		}
+		if len(mismatches) > 0 {
+			m.t.Errorf("fakellm: model call %d does not match turn %d:\n%s\nrequest:\n%s", i+1, i+1, strings.Join(mismatches, "\n"), FormatRequest(req))
+			yield(nil, fmt.Errorf("call %d: %w", i+1, ErrUnexpectedCall))
+			return
+		}
+		for _, resp := range turn.Responses {
+			if resp.Partial && !stream {
+				continue
+			}
+			if !yield(resp, nil) {
+				return
+			}
+		}
+		if turn.Err != nil {
+			yield(nil, turn.Err)
+		}
+	}
+}
+
+// Requests returns the requests received so far.
+func (m *Model) Requests() []*model.LLMRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*model.LLMRequest(nil), m.requests...)
+}
+
+// FormatRequest returns a readable summary of the request: its system
+// instruction, tools and contents.
+func FormatRequest(req *model.LLMRequest) string {
+	var sb strings.Builder
+	if instruction := systemInstruction(req); instruction != "" {
+		fmt.Fprintf(&sb, "  instruction: %q\n", instruction)
+	}
+	if tools := toolNames(req); len(tools) > 0 {
+		fmt.Fprintf(&sb, "  tools: %s\n", strings.Join(tools, ", "))
+	}
+	for _, content := range req.Contents {
+		fmt.Fprintf(&sb, "  %s\n", formatContent(content))
+	}
+	return sb.String()
+}
+
+func formatContent(content *genai.Content) string {
+	if content == nil {
+		return "<nil>"
+	}
+	var parts []string
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			parts = append(parts, fmt.Sprintf("call %s(%v)", part.FunctionCall.Name, part.FunctionCall.Args))
+		case part.FunctionResponse != nil:
+			parts = append(parts, fmt.Sprintf("response %s: %v", part.FunctionResponse.Name, part.FunctionResponse.Response))
+		case part.InlineData != nil:
+			parts = append(parts, fmt.Sprintf("data %s (%d bytes)", part.InlineData.MIMEType, len(part.InlineData.Data)))
+		case part.Thought:
+			parts = append(parts, fmt.Sprintf("thought %q", part.Text))
+		default:
+			parts = append(parts, fmt.Sprintf("%q", part.Text))
+		}
+	}
+	return content.Role + ": " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakellm_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/fakellm"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestModel_Agent(t *testing.T) {
+	type weatherArgs struct {
+		City string `json:"city"`
+	}
+	type weatherResult struct {
+		Weather string `json:"weather"`
+	}
+	weather, err := functiontool.New(functiontool.Config{
+		Name:        "get_weather",
+		Description: "returns the weather in a city",
+	}, func(tool.Context, weatherArgs) (weatherResult, error) {
+		return weatherResult{Weather: "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, streaming := range []agent.StreamingMode{agent.StreamingModeNone, agent.StreamingModeSSE} {
+		t.Run(string(streaming), func(t *testing.T) {
+			llm := fakellm.New(t,
+				fakellm.Turn{
+					Expect: []fakellm.Matcher{
+						fakellm.Instruction("You report the weather."),
+						fakellm.Tools("get_weather"),
+						fakellm.LastUserText("Weather in Paris?"),
+					},
+					Responses: fakellm.FunctionCall("get_weather", map[string]any{"city": "Paris"}),
+				},
+				fakellm.Turn{
+					Expect: []fakellm.Matcher{
+						fakellm.LastFunctionResponse("get_weather", map[string]any{"weather": "sunny"}),
+					},
+					Responses: fakellm.Stream("It is ", "sunny."),
+				},
+			)
+			a, err := llmagent.New(llmagent.Config{
+				Name:        "weather_agent",
+				Model:       llm,
+				Instruction: "You report the weather.",
+				Tools:       []tool.Tool{weather},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			runner := testutil.NewTestAgentRunner(t, a)
+			var got []string
+			for ev, err := range runner.RunContentWithConfig(t, "session", genai.NewContentFromText("Weather in Paris?", genai.RoleUser), agent.RunConfig{StreamingMode: streaming}) {
+				if err != nil {
+					t.Fatalf("Run() failed: %v", err)
+				}
+				got = append(got, eventSummary(ev))
+			}
+
+			want := []string{
+				"weather_agent: call get_weather",
+				"weather_agent: response get_weather",
+				"weather_agent: It is sunny.",
+			}
+			if streaming == agent.StreamingModeSSE {
+				want = []string{
+					"weather_agent: call get_weather",
+					"weather_agent: response get_weather",
+					"weather_agent: It is  (partial)",
+					"weather_agent: sunny. (partial)",
+					"weather_agent: It is sunny.",
+				}
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+			}
+			if got := len(llm.Requests()); got != 2 {
+				t.Errorf("Requests() returned %d requests, want 2", got)
+			}
+		})
+	}
+}
+
+func eventSummary(ev *session.Event) string {
+	var parts []string
+	for _, part := range ev.Content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			parts = append(parts, "call "+part.FunctionCall.Name)
+		case part.FunctionResponse != nil:
+			parts = append(parts, "response "+part.FunctionResponse.Name)
+		default:
+			parts = append(parts, part.Text)
+		}
+	}
+	s := ev.Author + ": " + strings.Join(parts, ", ")
+	if ev.Partial {
+		s += " (partial)"
+	}
+	return s
+}
+
+// recordingT records the errors reported by the fake model.
+type recordingT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *recordingT) end() {
+	for _, f := range t.cleanups {
+		f()
+	}
+}
+
+func TestModel_ReportsMismatches(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{genai.NewContentFromText("Hello", genai.RoleUser)},
+		Config: &genai.GenerateContentConfig{
+			SystemInstruction: genai.NewContentFromText("Be nice.", genai.RoleUser),
+			Tools:             []*genai.Tool{{FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "search"}}}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		turns      []fakellm.Turn
+		calls      int
+		want       []string
+		wantErr    error
+		wantErrors []string
+	}{
+		{
+			name: "matching calls",
+			turns: []fakellm.Turn{{
+				Expect: []fakellm.Matcher{
+					fakellm.Contents(genai.NewContentFromText("Hello", genai.RoleUser)),
+					fakellm.ContainsText("ell"),
+					fakellm.Instruction("nice"),
+					fakellm.HasTools("search"),
+				},
+				Responses: fakellm.Text("Hi"),
+			}},
+			calls: 1,
+			want:  []string{"Hi"},
+		},
+		{
+			name:    "scripted error",
+			turns:   []fakellm.Turn{{Responses: fakellm.Stream("H"), Err: errUnavailable}},
+			calls:   1,
+			want:    []string{"H"},
+			wantErr: errUnavailable,
+		},
+		{
+			name: "mismatch",
+			turns: []fakellm.Turn{{
+				Expect:    []fakellm.Matcher{fakellm.LastUserText("Bye"), fakellm.Tools("search", "fetch")},
+				Responses: fakellm.Text("Hi"),
+			}},
+			calls:   1,
+			wantErr: fakellm.ErrUnexpectedCall,
+			wantErrors: []string{
+				"fakellm: model call 1 does not match turn 1:",
+				"last content mismatch (-want +got)",
+				`"Bye"`,
+				"tools mismatch (-want +got)",
+				`"fetch"`,
+				`  instruction: "Be nice."`,
+				`  tools: search`,
+				`  user: "Hello"`,
+			},
+		},
+		{
+			name:    "unexpected call",
+			calls:   1,
+			wantErr: fakellm.ErrUnexpectedCall,
+			wantErrors: []string{
+				"fakellm: unexpected model call 1, the script has 0 turns",
+				`  user: "Hello"`,
+			},
+		},
+		{
+			name:       "turns not called",
+			turns:      []fakellm.Turn{{Responses: fakellm.Text("Hi")}, {Responses: fakellm.Text("Hi")}},
+			wantErrors: []string{"fakellm: 0 of 2 scripted model calls were made, the turns 1 to 2 were not called"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			llm := fakellm.New(rt, tt.turns...)
+			var got []string
+			var gotErr error
+			for range tt.calls {
+				for resp, err := range llm.GenerateContent(t.Context(), req, false) {
+					if err != nil {
+						gotErr = err
+						continue
+					}
+					got = append(got, resp.Content.Parts[0].Text)
+				}
+			}
+			rt.end()
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GenerateContent() responses mismatch (-want +got):\n%s", diff)
+			}
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("GenerateContent() error = %v, want %v", gotErr, tt.wantErr)
+			}
+			reported := strings.Join(rt.errors, "\n")
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(reported, want) {
+					t.Errorf("reported errors do not contain %q:\n%s", want, reported)
+				}
+			}
+			if len(tt.wantErrors) == 0 && reported != "" {
+				t.Errorf("unexpected reported errors:\n%s", reported)
+			}
+		})
+	}
+}
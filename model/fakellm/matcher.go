@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakellm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// Matcher checks a request sent to the model. It returns an error
+// describing the mismatch, if any.
+type Matcher func(req *model.LLMRequest) error
+
+// The IDs of the function calls and responses are generated, so they are
+// ignored when comparing the contents.
+var contentOpts = cmp.Options{
+	cmpopts.IgnoreFields(genai.FunctionCall{}, "ID"),
+	cmpopts.IgnoreFields(genai.FunctionResponse{}, "ID"),
+	cmpopts.EquateEmpty(),
+}
+
+// Contents matches the requests with exactly the contents.
+func Contents(want ...*genai.Content) Matcher {
+	return func(req *model.LLMRequest) error {
+		if diff := cmp.Diff(want, req.Contents, contentOpts); diff != "" {
+			return fmt.Errorf("contents mismatch (-want +got):\n%s", diff)
+		}
+		return nil
+	}
+}
+
+// LastContent matches the requests whose last content is want.
+func LastContent(want *genai.Content) Matcher {
+	return func(req *model.LLMRequest) error {
+		var got *genai.Content
+		if len(req.Contents) > 0 {
+			got = req.Contents[len(req.Contents)-1]
+		}
+		if diff := cmp.Diff(want, got, contentOpts); diff != "" {
+			return fmt.Errorf("last content mismatch (-want +got):\n%s", diff)
+		}
+		return nil
+	}
+}
+
+// LastUserText matches the requests whose last content is the user text.
+func LastUserText(text string) Matcher {
+	return LastContent(genai.NewContentFromText(text, genai.RoleUser))
+}
+
+// LastFunctionResponse matches the requests whose last content is the
+// response of the function.
+func LastFunctionResponse(name string, response map[string]any) Matcher {
+	return LastContent(genai.NewContentFromFunctionResponse(name, response, genai.RoleUser))
+}
+
+// ContainsText matches the requests with a content holding the text.
+func ContainsText(text string) Matcher {
+	return func(req *model.LLMRequest) error {
+		for _, content := range req.Contents {
+			if content == nil {
+				continue
+			}
+			for _, part := range content.Parts {
+				if strings.Contains(part.Text, text) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("no content contains %q", text)
+	}
+}
+
+// Instruction matches the requests whose system instruction contains the
+// text.
+func Instruction(text string) Matcher {
+	return func(req *model.LLMRequest) error {
+		if got := systemInstruction(req); !strings.Contains(got, text) {
+			return fmt.Errorf("instruction %q does not contain %q", got, text)
+		}
+		return nil
+	}
+}
+
+// HasTools matches the requests declaring the tools, among others.
+func HasTools(names ...string) Matcher {
+	return func(req *model.LLMRequest) error {
+		got := toolNames(req)
+		var missing []string
+		for _, name := range names {
+			if !slices.Contains(got, name) {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("tools %v are missing, got %v", missing, got)
+		}
+		return nil
+	}
+}
+
+// Tools matches the requests declaring exactly the tools.
+func Tools(names ...string) Matcher {
+	return func(req *model.LLMRequest) error {
+		want := slices.Sorted(slices.Values(names))
+		if diff := cmp.Diff(want, toolNames(req), cmpopts.EquateEmpty()); diff != "" {
+			return fmt.Errorf("tools mismatch (-want +got):\n%s", diff)
+		}
+		return nil
+	}
+}
+
+func systemInstruction(req *model.LLMRequest) string {
+	if req.Config == nil || req.Config.SystemInstruction == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range req.Config.SystemInstruction.Parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// toolNames returns the sorted names of the functions declared to the model.
+func toolNames(req *model.LLMRequest) []string {
+	var names []string
+	if req.Config != nil {
+		for _, tool := range req.Config.Tools {
+			for _, decl := range tool.FunctionDeclarations {
+				names = append(names, decl.Name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakellm
+
+import (
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// Text returns the response of the model with the text.
+func Text(text string) []*model.LLMResponse {
+	return []*model.LLMResponse{{
+		Content:      genai.NewContentFromText(text, genai.RoleModel),
+		TurnComplete: true,
+	}}
+}
+
+// FunctionCall returns the response of the model calling the function.
+func FunctionCall(name string, args map[string]any) []*model.LLMResponse {
+	return FunctionCalls(&genai.FunctionCall{Name: name, Args: args})
+}
+
+// FunctionCalls returns the response of the model calling the functions
+// in parallel.
+func FunctionCalls(calls ...*genai.FunctionCall) []*model.LLMResponse {
+	content := &genai.Content{Role: genai.RoleModel}
+	for _, call := range calls {
+		content.Parts = append(content.Parts, &genai.Part{FunctionCall: call})
+	}
+	return []*model.LLMResponse{{Content: content, TurnComplete: true}}
+}
+
+// Stream returns the partial responses of the model streaming the text
+// chunks, followed by the final response with the whole text. The
+// non-streaming calls only get the final response.
+func Stream(chunks ...string) []*model.LLMResponse {
+	var responses []*model.LLMResponse
+	for _, chunk := range chunks {
+		responses = append(responses, &model.LLMResponse{
+			Content: genai.NewContentFromText(chunk, genai.RoleModel),
+			Partial: true,
+		})
+	}
+	return append(responses, Text(strings.Join(chunks, ""))...)
+}
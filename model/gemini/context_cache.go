@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+const (
+	defaultCacheIntervals = 10
+	defaultCacheTTL       = 30 * time.Minute
+	// cacheExpiryMargin avoids using a cache expiring during the request.
+	cacheExpiryMargin = time.Minute
+)
+
+// cacheClient is implemented by genai.Caches.
+type cacheClient interface {
+	Create(ctx context.Context, model string, config *genai.CreateCachedContentConfig) (*genai.CachedContent, error)
+	Delete(ctx context.Context, name string, config *genai.DeleteCachedContentConfig) (*genai.DeleteCachedContentResponse, error)
+}
+
+// contextCache creates and reuses the Gemini cached contents of the stable
+// prefix of the requests: the system instruction, the tools and the
+// contents before the last user turn.
+type contextCache struct {
+	client cacheClient
+	model  string
+	now    func() time.Time
+}
+
+// apply makes the request use a cache, or creates one if the cacheable
+// prefix of the request was already sent by the previous request. It
+// returns the request to send, a copy of req if a cache is used, and the
+// metadata of the cache used, or the fingerprint of the cacheable prefix if
+// no cache is used. The caching errors are logged, the request is then sent
+// without cache.
+func (c *contextCache) apply(ctx context.Context, req *model.LLMRequest) (*model.LLMRequest, *model.CacheMetadata) {
+	// reference: adk-python src/google/adk/models/gemini_context_cache_manager.py
+	prev := req.CacheMetadata
+	if prev != nil && prev.CacheName != "" {
+		if c.valid(req, prev) {
+			return useCache(req, prev), prev
+		}
+		if _, err := c.client.Delete(ctx, prev.CacheName, nil); err != nil {
+			log.Printf("failed to delete context cache %s: %v", prev.CacheName, err)
+		}
+	}
+
+	count := cacheableContentsCount(req.Contents)
+	metadata := &model.CacheMetadata{
+		Fingerprint:   fingerprint(req, count),
+		ContentsCount: count,
+	}
+	// Only a prefix sent twice in a row is cached, as the prefix of some
+	// agents changes at every request, e.g. with a templated instruction.
+	stable := prev != nil && prev.ContentsCount <= len(req.Contents) &&
+		fingerprint(req, prev.ContentsCount) == prev.Fingerprint
+	if !stable || req.CacheableContentsTokenCount < req.CacheConfig.MinTokens {
+		return req, metadata
+	}
+
+	ttl := req.CacheConfig.TTL
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	now := c.now()
+	cached, err := c.client.Create(ctx, c.model, &genai.CreateCachedContentConfig{
+		HTTPOptions:       req.Config.HTTPOptions,
+		TTL:               ttl,
+		DisplayName:       "adk-cache-" + metadata.Fingerprint,
+		Contents:          req.Contents[:count],
+		SystemInstruction: req.Config.SystemInstruction,
+		Tools:             req.Config.Tools,
+		ToolConfig:        req.Config.ToolConfig,
+	})
+	if err != nil {
+		log.Printf("failed to create context cache: %v", err)
+		return req, metadata
+	}
+	metadata.CacheName = cached.Name
+	metadata.ExpireTime = cached.ExpireTime
+	if metadata.ExpireTime.IsZero() {
+		metadata.ExpireTime = now.Add(ttl)
+	}
+	metadata.CreateTime = now
+	return useCache(req, metadata), metadata
+}
+
+// valid reports whether the cache can be used for the request: it is not
+// expired nor used in too many invocations, and its prefix is unchanged.
+func (c *contextCache) valid(req *model.LLMRequest, metadata *model.CacheMetadata) bool {
+	intervals := req.CacheConfig.CacheIntervals
+	if intervals <= 0 {
+		intervals = defaultCacheIntervals
+	}
+	return c.now().Add(cacheExpiryMargin).Before(metadata.ExpireTime) &&
+		metadata.InvocationsUsed <= intervals &&
+		metadata.ContentsCount <= len(req.Contents) &&
+		fingerprint(req, metadata.ContentsCount) == metadata.Fingerprint
+}
+
+// useCache returns a copy of the request referencing the cache instead of
+// holding the cached prefix. The request is left unchanged, so the callbacks
+// and plugins still see the full request.
+func useCache(req *model.LLMRequest, metadata *model.CacheMetadata) *model.LLMRequest {
+	cacheReq := *req
+	cfg := *req.Config
+	cfg.CachedContent = metadata.CacheName
+	cfg.SystemInstruction = nil
+	cfg.Tools = nil
+	cfg.ToolConfig = nil
+	cacheReq.Config = &cfg
+	cacheReq.Contents = req.Contents[metadata.ContentsCount:]
+	return &cacheReq
+}
+
+// cacheableContentsCount returns the number of contents before the last
+// batch of user contents, which changes at every turn.
+func cacheableContentsCount(contents []*genai.Content) int {
+	i := len(contents)
+	for i > 0 && contents[i-1] != nil && contents[i-1].Role == genai.RoleUser {
+		i--
+	}
+	return i
+}
+
+// fingerprint hashes the system instruction, the tools and the first count
+// contents of the request.
+func fingerprint(req *model.LLMRequest, count int) string {
+	prefix := struct {
+		SystemInstruction *genai.Content    `json:"systemInstruction,omitempty"`
+		Tools             []*genai.Tool     `json:"tools,omitempty"`
+		ToolConfig        *genai.ToolConfig `json:"toolConfig,omitempty"`
+		Contents          []*genai.Content  `json:"contents,omitempty"`
+	}{
+		SystemInstruction: req.Config.SystemInstruction,
+		Tools:             req.Config.Tools,
+		ToolConfig:        req.Config.ToolConfig,
+		Contents:          req.Contents[:count],
+	}
+	data, err := json.Marshal(prefix)
+	if err != nil {
+		// The fingerprint then never matches, so nothing is cached.
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+type fakeCacheClient struct {
+	created []*genai.CreateCachedContentConfig
+	deleted []string
+}
+
+func (c *fakeCacheClient) Create(ctx context.Context, model string, config *genai.CreateCachedContentConfig) (*genai.CachedContent, error) {
+	c.created = append(c.created, config)
+	return &genai.CachedContent{Name: "cachedContents/new"}, nil
+}
+
+func (c *fakeCacheClient) Delete(ctx context.Context, name string, config *genai.DeleteCachedContentConfig) (*genai.DeleteCachedContentResponse, error) {
+	c.deleted = append(c.deleted, name)
+	return &genai.DeleteCachedContentResponse{}, nil
+}
+
+func TestContextCache_Apply(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newRequest := func(instruction string) *model.LLMRequest {
+		return &model.LLMRequest{
+			Contents: []*genai.Content{
+				genai.NewContentFromText("hi", genai.RoleUser),
+				genai.NewContentFromText("hello", genai.RoleModel),
+				genai.NewContentFromText("how are you?", genai.RoleUser),
+			},
+			Config: &genai.GenerateContentConfig{
+				SystemInstruction: genai.NewContentFromText(instruction, genai.RoleUser),
+			},
+			CacheConfig:                 &model.ContextCacheConfig{CacheIntervals: 3, TTL: time.Hour, MinTokens: 100},
+			CacheableContentsTokenCount: 200,
+		}
+	}
+	fp := fingerprint(newRequest("be nice"), 2)
+	newCache := &model.CacheMetadata{
+		CacheName:     "cachedContents/new",
+		ExpireTime:    now.Add(time.Hour),
+		Fingerprint:   fp,
+		ContentsCount: 2,
+		CreateTime:    now,
+	}
+	oldCache := func(modify func(*model.CacheMetadata)) *model.CacheMetadata {
+		m := &model.CacheMetadata{
+			CacheName:       "cachedContents/old",
+			ExpireTime:      now.Add(10 * time.Minute),
+			Fingerprint:     fp,
+			InvocationsUsed: 1,
+			ContentsCount:   2,
+			CreateTime:      now.Add(-time.Hour),
+		}
+		if modify != nil {
+			modify(m)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name         string
+		instruction  string
+		prev         *model.CacheMetadata
+		tokenCount   int32
+		wantMetadata *model.CacheMetadata
+		wantCreated  bool
+		wantDeleted  []string
+	}{
+		{
+			name:         "first request records fingerprint",
+			wantMetadata: &model.CacheMetadata{Fingerprint: fp, ContentsCount: 2},
+		},
+		{
+			name:         "create on stable prefix",
+			prev:         &model.CacheMetadata{Fingerprint: fp, ContentsCount: 2},
+			wantMetadata: newCache,
+			wantCreated:  true,
+		},
+		{
+			name:         "stable prefix from shorter request",
+			prev:         &model.CacheMetadata{Fingerprint: fingerprint(newRequest("be nice"), 0), ContentsCount: 0},
+			wantMetadata: newCache,
+			wantCreated:  true,
+		},
+		{
+			name:         "no cache below MinTokens",
+			prev:         &model.CacheMetadata{Fingerprint: fp, ContentsCount: 2},
+			tokenCount:   99,
+			wantMetadata: &model.CacheMetadata{Fingerprint: fp, ContentsCount: 2},
+		},
+		{
+			name:         "no cache on changed prefix",
+			instruction:  "be brief",
+			prev:         &model.CacheMetadata{Fingerprint: fp, ContentsCount: 2},
+			wantMetadata: &model.CacheMetadata{Fingerprint: fingerprint(newRequest("be brief"), 2), ContentsCount: 2},
+		},
+		{
+			name:         "reuse valid cache",
+			prev:         oldCache(nil),
+			wantMetadata: oldCache(nil),
+		},
+		{
+			name:         "reuse cache at CacheIntervals",
+			prev:         oldCache(func(m *model.CacheMetadata) { m.InvocationsUsed = 3 }),
+			wantMetadata: oldCache(func(m *model.CacheMetadata) { m.InvocationsUsed = 3 }),
+		},
+		{
+			name:         "invalidate on fingerprint change",
+			instruction:  "be brief",
+			prev:         oldCache(nil),
+			wantMetadata: &model.CacheMetadata{Fingerprint: fingerprint(newRequest("be brief"), 2), ContentsCount: 2},
+			wantDeleted:  []string{"cachedContents/old"},
+		},
+		{
+			name:         "invalidate on expiry",
+			prev:         oldCache(func(m *model.CacheMetadata) { m.ExpireTime = now.Add(30 * time.Second) }),
+			wantMetadata: newCache,
+			wantCreated:  true,
+			wantDeleted:  []string{"cachedContents/old"},
+		},
+		{
+			name:         "invalidate after CacheIntervals",
+			prev:         oldCache(func(m *model.CacheMetadata) { m.InvocationsUsed = 4 }),
+			wantMetadata: newCache,
+			wantCreated:  true,
+			wantDeleted:  []string{"cachedContents/old"},
+		},
+		{
+			name:         "invalidate below MinTokens without recreating",
+			prev:         oldCache(func(m *model.CacheMetadata) { m.InvocationsUsed = 4 }),
+			tokenCount:   10,
+			wantMetadata: &model.CacheMetadata{Fingerprint: fp, ContentsCount: 2},
+			wantDeleted:  []string{"cachedContents/old"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeCacheClient{}
+			c := &contextCache{client: client, model: "gemini-2.5-flash", now: func() time.Time { return now }}
+			instruction := tt.instruction
+			if instruction == "" {
+				instruction = "be nice"
+			}
+			req := newRequest(instruction)
+			req.CacheMetadata = tt.prev
+			if tt.tokenCount != 0 {
+				req.CacheableContentsTokenCount = tt.tokenCount
+			}
+			orig := newRequest(instruction)
+			orig.CacheMetadata = tt.prev
+			orig.CacheableContentsTokenCount = req.CacheableContentsTokenCount
+
+			gotReq, gotMetadata := c.apply(t.Context(), req)
+
+			if diff := cmp.Diff(tt.wantMetadata, gotMetadata); diff != "" {
+				t.Errorf("apply() metadata mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDeleted, client.deleted); diff != "" {
+				t.Errorf("deleted caches mismatch (-want +got):\n%s", diff)
+			}
+			if gotCreated := len(client.created) > 0; gotCreated != tt.wantCreated {
+				t.Errorf("cache created = %v, want %v", gotCreated, tt.wantCreated)
+			}
+			if tt.wantCreated {
+				want := &genai.CreateCachedContentConfig{
+					TTL:               time.Hour,
+					DisplayName:       "adk-cache-" + gotMetadata.Fingerprint,
+					Contents:          orig.Contents[:2],
+					SystemInstruction: orig.Config.SystemInstruction,
+				}
+				if diff := cmp.Diff(want, client.created[0]); diff != "" {
+					t.Errorf("created cache mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(orig, req); diff != "" {
+				t.Errorf("apply() modified the request (-want +got):\n%s", diff)
+			}
+
+			wantReq := orig
+			if gotMetadata.CacheName != "" {
+				wantReq = newRequest(instruction)
+				wantReq.CacheMetadata = tt.prev
+				wantReq.CacheableContentsTokenCount = req.CacheableContentsTokenCount
+				wantReq.Contents = wantReq.Contents[2:]
+				wantReq.Config.SystemInstruction = nil
+				wantReq.Config.CachedContent = gotMetadata.CacheName
+			}
+			if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+				t.Errorf("apply() request mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"runtime"
 	"strings"
+	"time"
 
 	"google.golang.org/genai"
 
@@ -36,6 +37,7 @@ type geminiModel struct {
 	client             *genai.Client
 	name               string
 	versionHeaderValue string
+	contextCache       *contextCache
 }
 
 // NewModel returns [model.LLM], backed by the Gemini API.
@@ -59,6 +61,7 @@ func NewModel(ctx context.Context, modelName string, cfg *genai.ClientConfig) (m
 		name:               modelName,
 		client:             client,
 		versionHeaderValue: headerValue,
+		contextCache:       &contextCache{client: client.Caches, model: modelName, now: time.Now},
 	}, nil
 }
 
@@ -80,14 +83,59 @@ func (m *geminiModel) GenerateContent(ctx context.Context, req *model.LLMRequest
 	}
 	m.addHeaders(req.Config.HTTPOptions.Headers)
 
-	if stream {
-		return m.generateStream(ctx, req)
+	var cacheMetadata *model.CacheMetadata
+	if req.CacheConfig != nil {
+		req, cacheMetadata = m.contextCache.apply(ctx, req)
 	}
 
+	var responses iter.Seq2[*model.LLMResponse, error]
+	if stream {
+		responses = m.generateStream(ctx, req)
+	} else {
+		responses = func(yield func(*model.LLMResponse, error) bool) {
+			resp, err := m.generate(ctx, req)
+			yield(resp, err)
+		}
+	}
+	if cacheMetadata == nil {
+		return responses
+	}
 	return func(yield func(*model.LLMResponse, error) bool) {
-		resp, err := m.generate(ctx, req)
-		yield(resp, err)
+		for resp, err := range responses {
+			if resp != nil {
+				resp.CacheMetadata = cacheMetadata
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// CountTokens implements [model.TokenCounter].
+//
+// The Gemini API only counts the contents, the tokens of the system
+// instruction and the tools are then estimated.
+func (m *geminiModel) CountTokens(ctx context.Context, req *model.LLMRequest) (int32, error) {
+	cfg := &genai.CountTokensConfig{HTTPOptions: &genai.HTTPOptions{Headers: make(http.Header)}}
+	m.addHeaders(cfg.HTTPOptions.Headers)
+	var estimated int32
+	if req.Config != nil {
+		if m.client.ClientConfig().Backend == genai.BackendVertexAI {
+			cfg.SystemInstruction = req.Config.SystemInstruction
+			cfg.Tools = req.Config.Tools
+		} else {
+			estimated = model.EstimateTokens(&model.LLMRequest{Config: &genai.GenerateContentConfig{
+				SystemInstruction: req.Config.SystemInstruction,
+				Tools:             req.Config.Tools,
+			}})
+		}
+	}
+	resp, err := m.client.Models.CountTokens(ctx, m.name, req.Contents, cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens: %w", err)
 	}
+	return resp.TotalTokens + estimated, nil
 }
 
 // addHeaders sets the x-goog-api-client and user-agent headers
@@ -243,3 +243,43 @@ func (h *headerInterceptor) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 	return h.base.RoundTrip(req)
 }
+
+func TestModel_CountTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelName string
+		req       *model.LLMRequest
+		want      int32
+	}{
+		{
+			name:      "ok",
+			modelName: "gemini-2.0-flash",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? One word."),
+				Config: &genai.GenerateContentConfig{
+					SystemInstruction: genai.NewContentFromText("Answer briefly.", genai.RoleUser),
+				},
+			},
+			// 10 tokens counted for the contents, 4 estimated for the
+			// system instruction unsupported by the Gemini API.
+			want: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpRecordFilename := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".httprr")
+
+			testModel, err := NewModel(t.Context(), tt.modelName, testutil.NewGeminiTestClientConfig(t, httpRecordFilename))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := testModel.(model.TokenCounter).CountTokens(t.Context(), tt.req)
+			if err != nil {
+				t.Fatalf("CountTokens() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CountTokens() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+)
+
+var _ model.LiveLLM = (*geminiModel)(nil)
+
+// Connect opens a Gemini Live API session.
+func (m *geminiModel) Connect(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	cfg := liveConnectConfig(req)
+	if cfg.HTTPOptions == nil {
+		cfg.HTTPOptions = &genai.HTTPOptions{}
+	}
+	if cfg.HTTPOptions.Headers == nil {
+		cfg.HTTPOptions.Headers = make(http.Header)
+	}
+	m.addHeaders(cfg.HTTPOptions.Headers)
+
+	session, err := m.client.Live.Connect(ctx, m.name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to model: %w", err)
+	}
+	return &liveConnection{session: session}, nil
+}
+
+// liveConnectConfig merges the generation config of the request into its
+// LiveConnectConfig.
+func liveConnectConfig(req *model.LLMRequest) *genai.LiveConnectConfig {
+	cfg := &genai.LiveConnectConfig{}
+	if req.LiveConnectConfig != nil {
+		*cfg = *req.LiveConnectConfig
+	}
+	gc := req.Config
+	if gc == nil {
+		return cfg
+	}
+	cfg.SystemInstruction = gc.SystemInstruction
+	cfg.Tools = gc.Tools
+	cfg.Temperature = gc.Temperature
+	cfg.TopP = gc.TopP
+	cfg.TopK = gc.TopK
+	cfg.MaxOutputTokens = gc.MaxOutputTokens
+	cfg.Seed = gc.Seed
+	if cfg.HTTPOptions == nil && gc.HTTPOptions != nil {
+		httpOptions := *gc.HTTPOptions
+		httpOptions.Headers = gc.HTTPOptions.Headers.Clone()
+		cfg.HTTPOptions = &httpOptions
+	}
+	if cfg.ThinkingConfig == nil {
+		cfg.ThinkingConfig = gc.ThinkingConfig
+	}
+	if cfg.SpeechConfig == nil {
+		cfg.SpeechConfig = gc.SpeechConfig
+	}
+	return cfg
+}
+
+// liveSession is the subset of [genai.Session] used by liveConnection.
+type liveSession interface {
+	SendClientContent(input genai.LiveClientContentInput) error
+	SendRealtimeInput(input genai.LiveRealtimeInput) error
+	SendToolResponse(input genai.LiveToolResponseInput) error
+	Receive() (*genai.LiveServerMessage, error)
+	Close() error
+}
+
+// liveConnection implements [model.LiveConnection] over a Gemini Live API
+// session.
+type liveConnection struct {
+	session liveSession
+	closed  atomic.Bool
+}
+
+// SendHistory sends the text contents of the history, the audio is not
+// replayed.
+func (c *liveConnection) SendHistory(ctx context.Context, history []*genai.Content) error {
+	// reference: adk-python src/google/adk/models/gemini_llm_connection.py GeminiLlmConnection.send_history
+	var turns []*genai.Content
+	for _, content := range history {
+		if content != nil && len(content.Parts) > 0 && content.Parts[0].Text != "" {
+			turns = append(turns, content)
+		}
+	}
+	if len(turns) == 0 {
+		return nil
+	}
+	turnComplete := turns[len(turns)-1].Role == genai.RoleUser
+	return c.session.SendClientContent(genai.LiveClientContentInput{
+		Turns:        turns,
+		TurnComplete: &turnComplete,
+	})
+}
+
+func (c *liveConnection) SendContent(ctx context.Context, content *genai.Content) error {
+	if responses := utils.FunctionResponses(content); len(responses) > 0 {
+		return c.session.SendToolResponse(genai.LiveToolResponseInput{FunctionResponses: responses})
+	}
+	return c.session.SendClientContent(genai.LiveClientContentInput{
+		Turns:        []*genai.Content{content},
+		TurnComplete: genai.Ptr(true),
+	})
+}
+
+func (c *liveConnection) SendRealtime(ctx context.Context, input genai.LiveRealtimeInput) error {
+	return c.session.SendRealtimeInput(input)
+}
+
+// Receive converts the server messages to responses. The text of the model
+// is yielded as partial responses, followed by a response with the full text
+// once the model is done with it.
+func (c *liveConnection) Receive(ctx context.Context) iter.Seq2[*model.LLMResponse, error] {
+	// reference: adk-python src/google/adk/models/gemini_llm_connection.py GeminiLlmConnection.receive
+	return func(yield func(*model.LLMResponse, error) bool) {
+		var b liveResponseBuilder
+		for {
+			msg, err := c.session.Receive()
+			if err != nil {
+				if c.closed.Load() || ctx.Err() != nil || websocket.IsCloseError(err, websocket.CloseNormalClosure) || errors.Is(err, net.ErrClosed) {
+					return
+				}
+				yield(nil, fmt.Errorf("failed to receive from model: %w", err))
+				return
+			}
+			for _, resp := range b.responses(msg) {
+				if !yield(resp, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *liveConnection) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	return c.session.Close()
+}
+
+// liveResponseBuilder accumulates the text and the transcriptions streamed by
+// the model.
+type liveResponseBuilder struct {
+	text                strings.Builder
+	inputTranscription  strings.Builder
+	outputTranscription strings.Builder
+}
+
+func (b *liveResponseBuilder) responses(msg *genai.LiveServerMessage) []*model.LLMResponse {
+	var out []*model.LLMResponse
+	if msg.UsageMetadata != nil {
+		out = append(out, &model.LLMResponse{UsageMetadata: usageMetadata(msg.UsageMetadata)})
+	}
+	if sc := msg.ServerContent; sc != nil {
+		if sc.ModelTurn != nil && len(sc.ModelTurn.Parts) > 0 {
+			resp := &model.LLMResponse{
+				Content:           sc.ModelTurn,
+				GroundingMetadata: sc.GroundingMetadata,
+				Interrupted:       sc.Interrupted,
+			}
+			switch first := sc.ModelTurn.Parts[0]; {
+			case first.Text != "":
+				b.text.WriteString(first.Text)
+				resp.Partial = true
+			case first.InlineData != nil:
+				// Audio chunks are only forwarded, not stored in the session.
+				resp.Partial = true
+			default:
+				out = b.appendText(out)
+			}
+			out = append(out, resp)
+		}
+		if t := sc.InputTranscription; t != nil {
+			out = appendTranscription(out, &b.inputTranscription, t, setInputTranscription)
+		}
+		if t := sc.OutputTranscription; t != nil {
+			out = appendTranscription(out, &b.outputTranscription, t, setOutputTranscription)
+		}
+		if sc.TurnComplete {
+			out = b.appendText(out)
+			// The transcriptions are not always marked as finished.
+			if b.inputTranscription.Len() > 0 {
+				out = appendTranscription(out, &b.inputTranscription, &genai.Transcription{Finished: true}, setInputTranscription)
+			}
+			if b.outputTranscription.Len() > 0 {
+				out = appendTranscription(out, &b.outputTranscription, &genai.Transcription{Finished: true}, setOutputTranscription)
+			}
+			out = append(out, &model.LLMResponse{
+				TurnComplete: true,
+				Interrupted:  sc.Interrupted,
+			})
+		} else if sc.Interrupted {
+			out = b.appendText(out)
+			out = append(out, &model.LLMResponse{Interrupted: true})
+		}
+	}
+	if msg.ToolCall != nil && len(msg.ToolCall.FunctionCalls) > 0 {
+		out = b.appendText(out)
+		content := &genai.Content{Role: genai.RoleModel}
+		for _, fc := range msg.ToolCall.FunctionCalls {
+			content.Parts = append(content.Parts, &genai.Part{FunctionCall: fc})
+		}
+		out = append(out, &model.LLMResponse{Content: content})
+	}
+	return out
+}
+
+// appendText appends the full text accumulated so far, if any.
+func (b *liveResponseBuilder) appendText(out []*model.LLMResponse) []*model.LLMResponse {
+	if b.text.Len() == 0 {
+		return out
+	}
+	out = append(out, &model.LLMResponse{
+		Content: genai.NewContentFromText(b.text.String(), genai.RoleModel),
+	})
+	b.text.Reset()
+	return out
+}
+
+// appendTranscription appends the transcription chunk as a partial response,
+// followed by the full transcription once it is finished.
+func appendTranscription(out []*model.LLMResponse, acc *strings.Builder, t *genai.Transcription, set func(*model.LLMResponse, *genai.Transcription)) []*model.LLMResponse {
+	if t.Text != "" {
+		acc.WriteString(t.Text)
+		resp := &model.LLMResponse{Partial: true}
+		set(resp, &genai.Transcription{Text: t.Text})
+		out = append(out, resp)
+	}
+	if t.Finished {
+		resp := &model.LLMResponse{}
+		set(resp, &genai.Transcription{Text: acc.String(), Finished: true})
+		out = append(out, resp)
+		acc.Reset()
+	}
+	return out
+}
+
+func setInputTranscription(resp *model.LLMResponse, t *genai.Transcription) {
+	resp.InputTranscription = t
+}
+
+func setOutputTranscription(resp *model.LLMResponse, t *genai.Transcription) {
+	resp.OutputTranscription = t
+}
+
+func usageMetadata(u *genai.UsageMetadata) *genai.GenerateContentResponseUsageMetadata {
+	return &genai.GenerateContentResponseUsageMetadata{
+		CacheTokensDetails:         u.CacheTokensDetails,
+		CachedContentTokenCount:    u.CachedContentTokenCount,
+		CandidatesTokenCount:       u.ResponseTokenCount,
+		CandidatesTokensDetails:    u.ResponseTokensDetails,
+		PromptTokenCount:           u.PromptTokenCount,
+		PromptTokensDetails:        u.PromptTokensDetails,
+		ThoughtsTokenCount:         u.ThoughtsTokenCount,
+		ToolUsePromptTokenCount:    u.ToolUsePromptTokenCount,
+		ToolUsePromptTokensDetails: u.ToolUsePromptTokensDetails,
+		TotalTokenCount:            u.TotalTokenCount,
+		TrafficType:                u.TrafficType,
+	}
+}
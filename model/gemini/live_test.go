@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+func TestLiveConnection_Receive(t *testing.T) {
+	text := func(s string) *genai.Content {
+		return genai.NewContentFromText(s, genai.RoleModel)
+	}
+	audio := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{InlineData: &genai.Blob{MIMEType: "audio/pcm", Data: []byte{1, 2}}}}}
+	fc := &genai.FunctionCall{ID: "call1", Name: "weather"}
+
+	for _, tc := range []struct {
+		name     string
+		messages []*genai.LiveServerMessage
+		want     []*model.LLMResponse
+	}{
+		{
+			name: "text turn",
+			messages: []*genai.LiveServerMessage{
+				{ServerContent: &genai.LiveServerContent{ModelTurn: text("Hello ")}},
+				{ServerContent: &genai.LiveServerContent{ModelTurn: text("world")}},
+				{ServerContent: &genai.LiveServerContent{TurnComplete: true}},
+			},
+			want: []*model.LLMResponse{
+				{Content: text("Hello "), Partial: true},
+				{Content: text("world"), Partial: true},
+				{Content: text("Hello world")},
+				{TurnComplete: true},
+			},
+		},
+		{
+			name: "audio and transcriptions",
+			messages: []*genai.LiveServerMessage{
+				{ServerContent: &genai.LiveServerContent{InputTranscription: &genai.Transcription{Text: "Hi"}}},
+				{ServerContent: &genai.LiveServerContent{InputTranscription: &genai.Transcription{Text: " there", Finished: true}}},
+				{ServerContent: &genai.LiveServerContent{ModelTurn: audio, OutputTranscription: &genai.Transcription{Text: "Hello"}}},
+				{ServerContent: &genai.LiveServerContent{TurnComplete: true}},
+			},
+			want: []*model.LLMResponse{
+				{InputTranscription: &genai.Transcription{Text: "Hi"}, Partial: true},
+				{InputTranscription: &genai.Transcription{Text: " there"}, Partial: true},
+				{InputTranscription: &genai.Transcription{Text: "Hi there", Finished: true}},
+				{Content: audio, Partial: true},
+				{OutputTranscription: &genai.Transcription{Text: "Hello"}, Partial: true},
+				{OutputTranscription: &genai.Transcription{Text: "Hello", Finished: true}},
+				{TurnComplete: true},
+			},
+		},
+		{
+			name: "tool call after text",
+			messages: []*genai.LiveServerMessage{
+				{ServerContent: &genai.LiveServerContent{ModelTurn: text("Let me check.")}},
+				{ToolCall: &genai.LiveServerToolCall{FunctionCalls: []*genai.FunctionCall{fc}}},
+			},
+			want: []*model.LLMResponse{
+				{Content: text("Let me check."), Partial: true},
+				{Content: text("Let me check.")},
+				{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{FunctionCall: fc}}}},
+			},
+		},
+		{
+			name: "interrupted",
+			messages: []*genai.LiveServerMessage{
+				{ServerContent: &genai.LiveServerContent{ModelTurn: text("Once upon")}},
+				{ServerContent: &genai.LiveServerContent{Interrupted: true}},
+			},
+			want: []*model.LLMResponse{
+				{Content: text("Once upon"), Partial: true},
+				{Content: text("Once upon")},
+				{Interrupted: true},
+			},
+		},
+		{
+			name: "usage metadata",
+			messages: []*genai.LiveServerMessage{
+				{UsageMetadata: &genai.UsageMetadata{PromptTokenCount: 3, ResponseTokenCount: 5, TotalTokenCount: 8}},
+			},
+			want: []*model.LLMResponse{
+				{UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 3, CandidatesTokenCount: 5, TotalTokenCount: 8}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := &liveConnection{session: &fakeLiveSession{messages: tc.messages}}
+			var got []*model.LLMResponse
+			for resp, err := range conn.Receive(t.Context()) {
+				if err != nil {
+					t.Fatalf("Receive() error = %v", err)
+				}
+				got = append(got, resp)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Receive() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLiveConnection_Send(t *testing.T) {
+	ctx := context.Background()
+	session := &fakeLiveSession{}
+	conn := &liveConnection{session: session}
+
+	history := []*genai.Content{
+		genai.NewContentFromText("Hi", genai.RoleUser),
+		{Role: genai.RoleModel, Parts: []*genai.Part{{InlineData: &genai.Blob{MIMEType: "audio/pcm"}}}},
+		genai.NewContentFromText("Hello", genai.RoleModel),
+	}
+	if err := conn.SendHistory(ctx, history); err != nil {
+		t.Fatalf("SendHistory() error = %v", err)
+	}
+	fr := &genai.FunctionResponse{ID: "call1", Name: "weather", Response: map[string]any{"forecast": "sunny"}}
+	if err := conn.SendContent(ctx, &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: fr}}}); err != nil {
+		t.Fatalf("SendContent() error = %v", err)
+	}
+	if err := conn.SendContent(ctx, genai.NewContentFromText("Thanks", genai.RoleUser)); err != nil {
+		t.Fatalf("SendContent() error = %v", err)
+	}
+
+	want := []any{
+		genai.LiveClientContentInput{Turns: []*genai.Content{history[0], history[2]}, TurnComplete: genai.Ptr(false)},
+		genai.LiveToolResponseInput{FunctionResponses: []*genai.FunctionResponse{fr}},
+		genai.LiveClientContentInput{Turns: []*genai.Content{genai.NewContentFromText("Thanks", genai.RoleUser)}, TurnComplete: genai.Ptr(true)},
+	}
+	if diff := cmp.Diff(want, session.sent); diff != "" {
+		t.Errorf("sent messages mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+	if session.closeCount != 1 {
+		t.Errorf("session closed %d times, want 1", session.closeCount)
+	}
+}
+
+// fakeLiveSession replays the messages, then reports the connection as closed.
+type fakeLiveSession struct {
+	messages   []*genai.LiveServerMessage
+	sent       []any
+	closeCount int
+}
+
+func (s *fakeLiveSession) SendClientContent(input genai.LiveClientContentInput) error {
+	s.sent = append(s.sent, input)
+	return nil
+}
+
+func (s *fakeLiveSession) SendRealtimeInput(input genai.LiveRealtimeInput) error {
+	s.sent = append(s.sent, input)
+	return nil
+}
+
+func (s *fakeLiveSession) SendToolResponse(input genai.LiveToolResponseInput) error {
+	s.sent = append(s.sent, input)
+	return nil
+}
+
+func (s *fakeLiveSession) Receive() (*genai.LiveServerMessage, error) {
+	if len(s.messages) == 0 {
+		return nil, errors.Join(errors.New("read failed"), net.ErrClosed)
+	}
+	msg := s.messages[0]
+	s.messages = s.messages[1:]
+	return msg, nil
+}
+
+func (s *fakeLiveSession) Close() error {
+	s.closeCount++
+	return nil
+}
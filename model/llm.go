@@ -28,11 +28,52 @@ type LLM interface {
 	GenerateContent(ctx context.Context, req *LLMRequest, stream bool) iter.Seq2[*LLMResponse, error]
 }
 
+// LiveLLM is implemented by the LLMs supporting live bidirectional
+// streaming. It is required to run an agent with runner.Runner.RunLive.
+type LiveLLM interface {
+	LLM
+	// Connect opens a live connection to the model. The connection is
+	// configured by the Config and LiveConnectConfig of the request, the
+	// Contents are not sent: use [LiveConnection.SendHistory].
+	Connect(ctx context.Context, req *LLMRequest) (LiveConnection, error)
+}
+
+// LiveConnection is a live bidirectional connection to the model.
+//
+// The Send methods must not be called concurrently.
+type LiveConnection interface {
+	// SendHistory sends the conversation history to the model. The model
+	// responds only if the last content is from the user.
+	SendHistory(ctx context.Context, history []*genai.Content) error
+	// SendContent sends a complete turn to the model. Contents holding
+	// function responses answer the function calls of the model.
+	SendContent(ctx context.Context, content *genai.Content) error
+	// SendRealtime sends realtime input to the model, e.g. an audio chunk
+	// or an activity signal.
+	SendRealtime(ctx context.Context, input genai.LiveRealtimeInput) error
+	// Receive yields the model responses until the connection is closed.
+	Receive(ctx context.Context) iter.Seq2[*LLMResponse, error]
+	// Close closes the connection.
+	Close() error
+}
+
 // LLMRequest is the raw LLM request.
 type LLMRequest struct {
 	Model    string
 	Contents []*genai.Content
 	Config   *genai.GenerateContentConfig
+	// LiveConnectConfig holds the settings specific to the live
+	// connections, see [LiveLLM].
+	LiveConnectConfig *genai.LiveConnectConfig `json:"-"`
+	// CacheConfig enables the context caching of the models supporting it,
+	// see [ContextCacheConfig].
+	CacheConfig *ContextCacheConfig `json:"-"`
+	// CacheMetadata describes the context cache of the previous request of
+	// the agent, if any.
+	CacheMetadata *CacheMetadata `json:"-"`
+	// CacheableContentsTokenCount is the prompt token count of the previous
+	// request of the agent, used to check the MinTokens of the CacheConfig.
+	CacheableContentsTokenCount int32 `json:"-"`
 
 	Tools map[string]any `json:"-"`
 }
@@ -47,7 +88,8 @@ type LLMResponse struct {
 	CustomMetadata    map[string]any
 	LogprobsResult    *genai.LogprobsResult
 	// Partial indicates whether the content is part of a unfinished content stream.
-	// Only used for streaming mode and when the content is plain text.
+	// Only used for streaming mode and when the content is plain text, or
+	// audio in live mode.
 	// The Runner fully processes only the final non-partial event, partial
 	// events are simply forwarded downstream (eg. to UI for display).
 	Partial bool
@@ -61,4 +103,14 @@ type LLMResponse struct {
 	ErrorMessage string
 	FinishReason genai.FinishReason
 	AvgLogprobs  float64
+
+	// InputTranscription is the transcription of the user audio.
+	// Only used in live mode.
+	InputTranscription *genai.Transcription
+	// OutputTranscription is the transcription of the model audio.
+	// Only used in live mode.
+	OutputTranscription *genai.Transcription
+	// CacheMetadata describes the context cache used for the request, or
+	// the fingerprint of its cacheable prefix if no cache was used.
+	CacheMetadata *CacheMetadata
 }
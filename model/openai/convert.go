@@ -0,0 +1,543 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// chatRequest is the body of the chat completions requests.
+type chatRequest struct {
+	Model            string          `json:"model"`
+	Messages         []chatMessage   `json:"messages"`
+	Tools            []chatTool      `json:"tools,omitempty"`
+	ResponseFormat   *responseFormat `json:"response_format,omitempty"`
+	Temperature      *float32        `json:"temperature,omitempty"`
+	TopP             *float32        `json:"top_p,omitempty"`
+	MaxTokens        int32           `json:"max_tokens,omitempty"`
+	Stop             []string        `json:"stop,omitempty"`
+	Seed             *int32          `json:"seed,omitempty"`
+	PresencePenalty  *float32        `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float32        `json:"frequency_penalty,omitempty"`
+	Stream           bool            `json:"stream,omitempty"`
+	StreamOptions    *streamOptions  `json:"stream_options,omitempty"`
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatMessage is a message of the conversation. The Content is either a
+// string or a list of contentParts.
+type chatMessage struct {
+	Role       string     `json:"role"`
+	Content    any        `json:"content"`
+	ToolCalls  []toolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+type contentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *imageURL `json:"image_url,omitempty"`
+}
+
+type imageURL struct {
+	URL string `json:"url"`
+}
+
+type toolCall struct {
+	// Index identifies the tool call in the stream chunks.
+	Index    *int         `json:"index,omitempty"`
+	ID       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function functionCall `json:"function"`
+}
+
+type functionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type chatTool struct {
+	Type     string             `json:"type"`
+	Function functionDefinition `json:"function"`
+}
+
+type functionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string `json:"name"`
+	Schema any    `json:"schema"`
+}
+
+// chatResponse is the body of the chat completions responses, and of the
+// chunks of the streamed responses.
+type chatResponse struct {
+	ID      string       `json:"id"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   *chatUsage   `json:"usage"`
+	Error   *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+type chatChoice struct {
+	Message      *responseMessage `json:"message"`
+	Delta        *responseMessage `json:"delta"`
+	FinishReason string           `json:"finish_reason"`
+}
+
+type responseMessage struct {
+	Content string `json:"content"`
+	// ReasoningContent holds the reasoning of the model, for the servers
+	// exposing it, e.g. vLLM and Ollama.
+	ReasoningContent string     `json:"reasoning_content"`
+	Refusal          string     `json:"refusal"`
+	ToolCalls        []toolCall `json:"tool_calls"`
+}
+
+type chatUsage struct {
+	PromptTokens        int32 `json:"prompt_tokens"`
+	CompletionTokens    int32 `json:"completion_tokens"`
+	TotalTokens         int32 `json:"total_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int32 `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+	CompletionTokensDetails *struct {
+		ReasoningTokens int32 `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+}
+
+// toChatRequest converts the LLM request to a chat completions request.
+func toChatRequest(modelName string, req *model.LLMRequest) (*chatRequest, error) {
+	chatReq := &chatRequest{Model: modelName}
+
+	if cfg := req.Config; cfg != nil {
+		if text := contentText(cfg.SystemInstruction); text != "" {
+			chatReq.Messages = append(chatReq.Messages, chatMessage{Role: "system", Content: text})
+		}
+		chatReq.Temperature = cfg.Temperature
+		chatReq.TopP = cfg.TopP
+		chatReq.MaxTokens = cfg.MaxOutputTokens
+		chatReq.Stop = cfg.StopSequences
+		chatReq.Seed = cfg.Seed
+		chatReq.PresencePenalty = cfg.PresencePenalty
+		chatReq.FrequencyPenalty = cfg.FrequencyPenalty
+
+		for _, t := range cfg.Tools {
+			if t == nil {
+				continue
+			}
+			if len(t.FunctionDeclarations) == 0 {
+				return nil, fmt.Errorf("unsupported tool: only function declarations are supported")
+			}
+			for _, decl := range t.FunctionDeclarations {
+				chatReq.Tools = append(chatReq.Tools, toChatTool(decl))
+			}
+		}
+
+		switch {
+		case cfg.ResponseJsonSchema != nil:
+			chatReq.ResponseFormat = &responseFormat{Type: "json_schema", JSONSchema: &jsonSchema{Name: "response", Schema: cfg.ResponseJsonSchema}}
+		case cfg.ResponseSchema != nil:
+			chatReq.ResponseFormat = &responseFormat{Type: "json_schema", JSONSchema: &jsonSchema{Name: "response", Schema: schemaToJSON(cfg.ResponseSchema)}}
+		case cfg.ResponseMIMEType == "application/json":
+			chatReq.ResponseFormat = &responseFormat{Type: "json_object"}
+		}
+	}
+
+	for _, content := range req.Contents {
+		if content == nil {
+			continue
+		}
+		var (
+			messages []chatMessage
+			err      error
+		)
+		switch content.Role {
+		case genai.RoleUser, "":
+			messages, err = toUserMessages(content)
+		case genai.RoleModel:
+			messages, err = toAssistantMessages(content)
+		default:
+			err = fmt.Errorf("unsupported content role %q", content.Role)
+		}
+		if err != nil {
+			return nil, err
+		}
+		chatReq.Messages = append(chatReq.Messages, messages...)
+	}
+	return chatReq, nil
+}
+
+// toUserMessages converts the user content to a message, preceded by a tool
+// message for each of its function responses.
+func toUserMessages(content *genai.Content) ([]chatMessage, error) {
+	var (
+		messages []chatMessage
+		parts    []contentPart
+		hasImage bool
+	)
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionResponse != nil:
+			data, err := json.Marshal(part.FunctionResponse.Response)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response of function %q: %w", part.FunctionResponse.Name, err)
+			}
+			messages = append(messages, chatMessage{Role: "tool", Content: string(data), ToolCallID: part.FunctionResponse.ID})
+		case part.Text != "" && !part.Thought:
+			parts = append(parts, contentPart{Type: "text", Text: part.Text})
+		case part.InlineData != nil:
+			if !strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+				return nil, fmt.Errorf("unsupported inline data MIME type %q", part.InlineData.MIMEType)
+			}
+			url := fmt.Sprintf("data:%s;base64,%s", part.InlineData.MIMEType, base64.StdEncoding.EncodeToString(part.InlineData.Data))
+			parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: url}})
+			hasImage = true
+		case part.FileData != nil:
+			if !strings.HasPrefix(part.FileData.MIMEType, "image/") {
+				return nil, fmt.Errorf("unsupported file data MIME type %q", part.FileData.MIMEType)
+			}
+			parts = append(parts, contentPart{Type: "image_url", ImageURL: &imageURL{URL: part.FileData.FileURI}})
+			hasImage = true
+		}
+	}
+	switch {
+	case hasImage:
+		messages = append(messages, chatMessage{Role: "user", Content: parts})
+	case len(parts) > 0:
+		// Plain text content is sent as a string, as not all the compatible
+		// servers accept the content parts.
+		texts := make([]string, len(parts))
+		for i, p := range parts {
+			texts[i] = p.Text
+		}
+		messages = append(messages, chatMessage{Role: "user", Content: strings.Join(texts, "\n")})
+	}
+	return messages, nil
+}
+
+// toAssistantMessages converts the model content to an assistant message.
+func toAssistantMessages(content *genai.Content) ([]chatMessage, error) {
+	var (
+		texts     []string
+		toolCalls []toolCall
+	)
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			args := []byte("{}")
+			if part.FunctionCall.Args != nil {
+				var err error
+				if args, err = json.Marshal(part.FunctionCall.Args); err != nil {
+					return nil, fmt.Errorf("failed to marshal arguments of function %q: %w", part.FunctionCall.Name, err)
+				}
+			}
+			toolCalls = append(toolCalls, toolCall{
+				ID:       part.FunctionCall.ID,
+				Type:     "function",
+				Function: functionCall{Name: part.FunctionCall.Name, Arguments: string(args)},
+			})
+		case part.Text != "" && !part.Thought:
+			texts = append(texts, part.Text)
+		}
+	}
+	if len(texts) == 0 && len(toolCalls) == 0 {
+		return nil, nil
+	}
+	msg := chatMessage{Role: "assistant", ToolCalls: toolCalls}
+	if len(texts) > 0 {
+		msg.Content = strings.Join(texts, "")
+	}
+	return []chatMessage{msg}, nil
+}
+
+func toChatTool(decl *genai.FunctionDeclaration) chatTool {
+	var params any = map[string]any{"type": "object", "properties": map[string]any{}}
+	switch {
+	case decl.ParametersJsonSchema != nil:
+		params = decl.ParametersJsonSchema
+	case decl.Parameters != nil:
+		params = schemaToJSON(decl.Parameters)
+	}
+	return chatTool{
+		Type: "function",
+		Function: functionDefinition{
+			Name:        decl.Name,
+			Description: decl.Description,
+			Parameters:  params,
+		},
+	}
+}
+
+// schemaToJSON converts the Gemini schema to a JSON schema.
+func schemaToJSON(s *genai.Schema) map[string]any {
+	out := map[string]any{}
+	if s.Type != genai.TypeUnspecified {
+		typ := strings.ToLower(string(s.Type))
+		if s.Nullable != nil && *s.Nullable {
+			out["type"] = []string{typ, "null"}
+		} else {
+			out["type"] = typ
+		}
+	}
+	if s.Title != "" {
+		out["title"] = s.Title
+	}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	if s.Format != "" {
+		out["format"] = s.Format
+	}
+	if s.Pattern != "" {
+		out["pattern"] = s.Pattern
+	}
+	if len(s.Enum) > 0 {
+		out["enum"] = s.Enum
+	}
+	if s.Default != nil {
+		out["default"] = s.Default
+	}
+	if s.Minimum != nil {
+		out["minimum"] = *s.Minimum
+	}
+	if s.Maximum != nil {
+		out["maximum"] = *s.Maximum
+	}
+	if s.MinLength != nil {
+		out["minLength"] = *s.MinLength
+	}
+	if s.MaxLength != nil {
+		out["maxLength"] = *s.MaxLength
+	}
+	if s.MinItems != nil {
+		out["minItems"] = *s.MinItems
+	}
+	if s.MaxItems != nil {
+		out["maxItems"] = *s.MaxItems
+	}
+	if s.Items != nil {
+		out["items"] = schemaToJSON(s.Items)
+	}
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for name, prop := range s.Properties {
+			props[name] = schemaToJSON(prop)
+		}
+		out["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		out["required"] = s.Required
+	}
+	if len(s.AnyOf) > 0 {
+		anyOf := make([]any, len(s.AnyOf))
+		for i, sub := range s.AnyOf {
+			anyOf[i] = schemaToJSON(sub)
+		}
+		out["anyOf"] = anyOf
+	}
+	return out
+}
+
+func contentText(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var texts []string
+	for _, part := range content.Parts {
+		if part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// toLLMResponse converts the first choice of the chat completions response.
+func toLLMResponse(resp *chatResponse) (*model.LLMResponse, error) {
+	if len(resp.Choices) == 0 || resp.Choices[0].Message == nil {
+		return nil, fmt.Errorf("empty response")
+	}
+	choice := resp.Choices[0]
+	return newLLMResponse(choice.Message, choice.FinishReason, resp.Usage)
+}
+
+func newLLMResponse(msg *responseMessage, finishReason string, usage *chatUsage) (*model.LLMResponse, error) {
+	content := &genai.Content{Role: genai.RoleModel}
+	if msg.ReasoningContent != "" {
+		content.Parts = append(content.Parts, &genai.Part{Text: msg.ReasoningContent, Thought: true})
+	}
+	if msg.Content != "" {
+		content.Parts = append(content.Parts, genai.NewPartFromText(msg.Content))
+	}
+	if msg.Refusal != "" {
+		content.Parts = append(content.Parts, genai.NewPartFromText(msg.Refusal))
+	}
+	for _, tc := range msg.ToolCalls {
+		args := map[string]any{}
+		if strings.TrimSpace(tc.Function.Arguments) != "" {
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments of function call %q: %w", tc.Function.Name, err)
+			}
+		}
+		content.Parts = append(content.Parts, &genai.Part{FunctionCall: &genai.FunctionCall{
+			ID:   tc.ID,
+			Name: tc.Function.Name,
+			Args: args,
+		}})
+	}
+	return &model.LLMResponse{
+		Content:       content,
+		UsageMetadata: toUsageMetadata(usage),
+		FinishReason:  toFinishReason(finishReason),
+	}, nil
+}
+
+func toFinishReason(reason string) genai.FinishReason {
+	switch reason {
+	case "":
+		return genai.FinishReasonUnspecified
+	case "stop", "tool_calls", "function_call":
+		return genai.FinishReasonStop
+	case "length":
+		return genai.FinishReasonMaxTokens
+	case "content_filter":
+		return genai.FinishReasonSafety
+	default:
+		return genai.FinishReasonOther
+	}
+}
+
+func toUsageMetadata(usage *chatUsage) *genai.GenerateContentResponseUsageMetadata {
+	if usage == nil {
+		return nil
+	}
+	md := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     usage.PromptTokens,
+		CandidatesTokenCount: usage.CompletionTokens,
+		TotalTokenCount:      usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		md.CachedContentTokenCount = usage.PromptTokensDetails.CachedTokens
+	}
+	if usage.CompletionTokensDetails != nil {
+		md.ThoughtsTokenCount = usage.CompletionTokensDetails.ReasoningTokens
+	}
+	return md
+}
+
+// streamAggregator aggregates the deltas of the streamed chunks.
+type streamAggregator struct {
+	text         strings.Builder
+	reasoning    strings.Builder
+	toolCalls    []toolCall
+	finishReason string
+	usage        *chatUsage
+}
+
+// add aggregates the chunk, returning a partial response with its text if
+// any.
+func (a *streamAggregator) add(chunk *chatResponse) (*model.LLMResponse, error) {
+	if chunk.Usage != nil {
+		a.usage = chunk.Usage
+	}
+	if len(chunk.Choices) == 0 {
+		return nil, nil
+	}
+	choice := chunk.Choices[0]
+	if choice.FinishReason != "" {
+		a.finishReason = choice.FinishReason
+	}
+	delta := choice.Delta
+	if delta == nil {
+		return nil, nil
+	}
+
+	for _, tc := range delta.ToolCalls {
+		index := len(a.toolCalls)
+		if tc.Index != nil {
+			index = *tc.Index
+		} else if tc.ID == "" && index > 0 {
+			// Continuation of the last tool call.
+			index--
+		}
+		if index < 0 || index > len(a.toolCalls) {
+			return nil, fmt.Errorf("invalid tool call index %d in stream", index)
+		}
+		if index == len(a.toolCalls) {
+			a.toolCalls = append(a.toolCalls, toolCall{})
+		}
+		call := &a.toolCalls[index]
+		if tc.ID != "" {
+			call.ID = tc.ID
+		}
+		call.Function.Name += tc.Function.Name
+		call.Function.Arguments += tc.Function.Arguments
+	}
+
+	var parts []*genai.Part
+	if delta.ReasoningContent != "" {
+		a.reasoning.WriteString(delta.ReasoningContent)
+		parts = append(parts, &genai.Part{Text: delta.ReasoningContent, Thought: true})
+	}
+	if delta.Content != "" {
+		a.text.WriteString(delta.Content)
+		parts = append(parts, genai.NewPartFromText(delta.Content))
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+	return &model.LLMResponse{
+		Content: &genai.Content{Role: genai.RoleModel, Parts: parts},
+		Partial: true,
+	}, nil
+}
+
+// close returns the aggregated response of the stream.
+func (a *streamAggregator) close() (*model.LLMResponse, error) {
+	if a.text.Len() == 0 && a.reasoning.Len() == 0 && len(a.toolCalls) == 0 && a.finishReason == "" {
+		return nil, fmt.Errorf("empty response")
+	}
+	resp, err := newLLMResponse(&responseMessage{
+		Content:          a.text.String(),
+		ReasoningContent: a.reasoning.String(),
+		ToolCalls:        a.toolCalls,
+	}, a.finishReason, a.usage)
+	if err != nil {
+		return nil, err
+	}
+	resp.TurnComplete = true
+	return resp, nil
+}
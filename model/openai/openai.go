@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openai implements the [model.LLM] interface for the models served
+// with the OpenAI Chat Completions API, e.g. by OpenAI, vLLM, Ollama or
+// LM Studio.
+package openai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// DefaultBaseURL is the base URL of the OpenAI API.
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// ClientConfig configures the access to the Chat Completions API.
+type ClientConfig struct {
+	// BaseURL is the base URL of the API, e.g. "http://localhost:11434/v1"
+	// for Ollama. Defaults to DefaultBaseURL.
+	BaseURL string
+	// APIKey is sent as bearer token. Defaults to the OPENAI_API_KEY
+	// environment variable. Local servers usually don't require it.
+	APIKey string
+	// HTTPClient sends the requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+type openaiModel struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewModel returns [model.LLM], backed by the OpenAI Chat Completions API.
+//
+// The modelName specifies which model to target (e.g., "gpt-4o-mini"). The
+// cfg is optional.
+func NewModel(ctx context.Context, modelName string, cfg *ClientConfig) (model.LLM, error) {
+	if modelName == "" {
+		return nil, fmt.Errorf("model name is required")
+	}
+	m := &openaiModel{
+		name:       modelName,
+		baseURL:    DefaultBaseURL,
+		apiKey:     os.Getenv("OPENAI_API_KEY"),
+		httpClient: http.DefaultClient,
+	}
+	if cfg != nil {
+		if cfg.BaseURL != "" {
+			m.baseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+		}
+		if cfg.APIKey != "" {
+			m.apiKey = cfg.APIKey
+		}
+		if cfg.HTTPClient != nil {
+			m.httpClient = cfg.HTTPClient
+		}
+	}
+	return m, nil
+}
+
+func (m *openaiModel) Name() string {
+	return m.name
+}
+
+// GenerateContent calls the underlying model.
+func (m *openaiModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		chatReq, err := toChatRequest(m.name, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		chatReq.Stream = stream
+		if stream {
+			chatReq.StreamOptions = &streamOptions{IncludeUsage: true}
+		}
+
+		body, err := m.send(ctx, chatReq)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		if !stream {
+			var resp chatResponse
+			if err := json.NewDecoder(body).Decode(&resp); err != nil {
+				yield(nil, fmt.Errorf("failed to decode response: %w", err))
+				return
+			}
+			llmResp, err := toLLMResponse(&resp)
+			yield(llmResp, err)
+			return
+		}
+
+		for resp, err := range readStream(body) {
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
+// send posts the request to the chat completions endpoint and returns the
+// response body.
+func (m *openaiModel) send(ctx context.Context, chatReq *chatRequest) (io.ReadCloser, error) {
+	data, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+	httpResp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call model: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		return nil, newAPIError(httpResp)
+	}
+	return httpResp.Body, nil
+}
+
+// APIError is returned when the API responds with an error status.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+	// RetryDelay is the delay requested by the Retry-After header.
+	RetryDelay time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("openai API error %d (%s): %s", e.StatusCode, e.Type, e.Message)
+	}
+	return fmt.Sprintf("openai API error %d: %s", e.StatusCode, e.Message)
+}
+
+// HTTPStatusCode implements [model.HTTPStatusError].
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// RetryAfter implements [model.RetryAfterError].
+func (e *APIError) RetryAfter() time.Duration {
+	return e.RetryDelay
+}
+
+func newAPIError(httpResp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(httpResp.Body, 1<<20))
+	apiErr := &APIError{
+		StatusCode: httpResp.StatusCode,
+		Message:    strings.TrimSpace(string(data)),
+		RetryDelay: model.ParseRetryAfter(httpResp.Header.Get("Retry-After")),
+	}
+	var body struct {
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			// Code is a string for OpenAI, a number for some compatible
+			// servers.
+			Code any `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err == nil && body.Error != nil {
+		apiErr.Message = body.Error.Message
+		apiErr.Type = body.Error.Type
+		if body.Error.Code != nil {
+			apiErr.Code = fmt.Sprint(body.Error.Code)
+		}
+	}
+	return apiErr
+}
+
+// readStream yields the partial text responses of the server-sent events
+// stream, followed by the aggregated response once the stream ends.
+func readStream(body io.Reader) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		aggregator := &streamAggregator{}
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == "[DONE]" {
+				break
+			}
+			var chunk chatResponse
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				yield(nil, fmt.Errorf("failed to decode stream chunk: %w", err))
+				return
+			}
+			if chunk.Error != nil {
+				yield(nil, &APIError{Type: chunk.Error.Type, Message: chunk.Error.Message})
+				return
+			}
+			partial, err := aggregator.add(&chunk)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if partial != nil && !yield(partial, nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to read stream: %w", err))
+			return
+		}
+		resp, err := aggregator.close()
+		yield(resp, err)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/httprr"
+	"google.golang.org/adk/model"
+)
+
+//go:generate go test -httprecord=testdata/.*\.httprr
+
+func TestModel_Generate(t *testing.T) {
+	weatherTool := &genai.Tool{
+		FunctionDeclarations: []*genai.FunctionDeclaration{{
+			Name:        "get_weather",
+			Description: "Returns the current weather in the city.",
+			Parameters: &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: map[string]*genai.Schema{"city": {Type: genai.TypeString}},
+				Required:   []string{"city"},
+			},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		modelName string
+		req       *model.LLMRequest
+		want      *model.LLMResponse
+		wantErr   bool
+	}{
+		{
+			name:      "ok",
+			modelName: "gpt-4o-mini",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? One word."),
+				Config: &genai.GenerateContentConfig{
+					SystemInstruction: genai.NewContentFromText("You are a geography expert.", genai.RoleUser),
+					Temperature:       new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("Paris", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     27,
+					CandidatesTokenCount: 1,
+					TotalTokenCount:      28,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "function_call",
+			modelName: "gpt-4o-mini",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the weather in Paris?"),
+				Config: &genai.GenerateContentConfig{
+					Tools:       []*genai.Tool{weatherTool},
+					Temperature: new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: &genai.Content{
+					Role: genai.RoleModel,
+					Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{
+						ID:   "call_V4kGMkbJq1yGXJpsvNOhbL6Q",
+						Name: "get_weather",
+						Args: map[string]any{"city": "Paris"},
+					}}},
+				},
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     56,
+					CandidatesTokenCount: 15,
+					TotalTokenCount:      71,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "function_response",
+			modelName: "gpt-4o-mini",
+			req: &model.LLMRequest{
+				Contents: []*genai.Content{
+					genai.NewContentFromText("What is the weather in Paris?", genai.RoleUser),
+					{
+						Role: genai.RoleModel,
+						Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{
+							ID:   "call_V4kGMkbJq1yGXJpsvNOhbL6Q",
+							Name: "get_weather",
+							Args: map[string]any{"city": "Paris"},
+						}}},
+					},
+					{
+						Role: genai.RoleUser,
+						Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+							ID:       "call_V4kGMkbJq1yGXJpsvNOhbL6Q",
+							Name:     "get_weather",
+							Response: map[string]any{"weather": "sunny, 22°C"},
+						}}},
+					},
+				},
+				Config: &genai.GenerateContentConfig{
+					Tools:       []*genai.Tool{weatherTool},
+					Temperature: new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("It is sunny in Paris, with a temperature of 22°C.", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     89,
+					CandidatesTokenCount: 14,
+					TotalTokenCount:      103,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "response_schema",
+			modelName: "gpt-4o-mini",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France?"),
+				Config: &genai.GenerateContentConfig{
+					ResponseMIMEType: "application/json",
+					ResponseSchema: &genai.Schema{
+						Type:       genai.TypeObject,
+						Properties: map[string]*genai.Schema{"capital": {Type: genai.TypeString}},
+						Required:   []string{"capital"},
+					},
+					Temperature: new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText(`{"capital":"Paris"}`, genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     45,
+					CandidatesTokenCount: 7,
+					TotalTokenCount:      52,
+				},
+				FinishReason: genai.FinishReasonStop,
+			},
+		},
+		{
+			name:      "unknown_model",
+			modelName: "gpt-unknown",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? One word."),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testModel, err := NewModel(t.Context(), tt.modelName, newTestClientConfig(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for got, err := range testModel.GenerateContent(t.Context(), tt.req, false) {
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Model.Generate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("Model.Generate() = %v, want %v\ndiff(-want +got):\n%v", got, tt.want, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestModel_GenerateStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *model.LLMRequest
+		wantPartial []string
+		want        *model.LLMResponse
+	}{
+		{
+			name: "ok",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the capital of France? Answer in a sentence."),
+				Config:   &genai.GenerateContentConfig{Temperature: new(float32)},
+			},
+			wantPartial: []string{"The", " capital", " of", " France", " is", " Paris", "."},
+			want: &model.LLMResponse{
+				Content: genai.NewContentFromText("The capital of France is Paris.", genai.RoleModel),
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     19,
+					CandidatesTokenCount: 7,
+					TotalTokenCount:      26,
+				},
+				FinishReason: genai.FinishReasonStop,
+				TurnComplete: true,
+			},
+		},
+		{
+			name: "function_call",
+			req: &model.LLMRequest{
+				Contents: genai.Text("What is the weather in Paris?"),
+				Config: &genai.GenerateContentConfig{
+					Tools: []*genai.Tool{{
+						FunctionDeclarations: []*genai.FunctionDeclaration{{
+							Name:                 "get_weather",
+							Description:          "Returns the current weather in the city.",
+							ParametersJsonSchema: map[string]any{"type": "object", "properties": map[string]any{"city": map[string]any{"type": "string"}}},
+						}},
+					}},
+					Temperature: new(float32),
+				},
+			},
+			want: &model.LLMResponse{
+				Content: &genai.Content{
+					Role: genai.RoleModel,
+					Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{
+						ID:   "call_3ZpXfKJwYV8UfX1kqYb2xHc9",
+						Name: "get_weather",
+						Args: map[string]any{"city": "Paris"},
+					}}},
+				},
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     56,
+					CandidatesTokenCount: 15,
+					TotalTokenCount:      71,
+				},
+				FinishReason: genai.FinishReasonStop,
+				TurnComplete: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testModel, err := NewModel(t.Context(), "gpt-4o-mini", newTestClientConfig(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotPartial []string
+			var got *model.LLMResponse
+			for resp, err := range testModel.GenerateContent(t.Context(), tt.req, true) {
+				if err != nil {
+					t.Fatalf("Model.GenerateStream() error = %v", err)
+				}
+				if resp.Partial {
+					gotPartial = append(gotPartial, resp.Content.Parts[0].Text)
+					continue
+				}
+				if got != nil {
+					t.Fatalf("Model.GenerateStream() yielded more than one final response: %v, %v", got, resp)
+				}
+				got = resp
+			}
+			if diff := cmp.Diff(tt.wantPartial, gotPartial); diff != "" {
+				t.Errorf("Model.GenerateStream() partial texts mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Model.GenerateStream() = %v, want %v\ndiff(-want +got):\n%v", got, tt.want, diff)
+			}
+		})
+	}
+}
+
+func TestToChatRequest(t *testing.T) {
+	req := &model.LLMRequest{
+		Contents: []*genai.Content{
+			{
+				Role: genai.RoleUser,
+				Parts: []*genai.Part{
+					genai.NewPartFromText("Describe the image."),
+					genai.NewPartFromBytes([]byte("png"), "image/png"),
+				},
+			},
+			{
+				Role: genai.RoleModel,
+				Parts: []*genai.Part{
+					{Text: "thinking", Thought: true},
+					genai.NewPartFromText("A cat."),
+				},
+			},
+		},
+		Config: &genai.GenerateContentConfig{
+			MaxOutputTokens:  100,
+			StopSequences:    []string{"END"},
+			ResponseMIMEType: "application/json",
+		},
+	}
+	got, err := toChatRequest("gpt-4o-mini", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &chatRequest{
+		Model: "gpt-4o-mini",
+		Messages: []chatMessage{
+			{Role: "user", Content: []contentPart{
+				{Type: "text", Text: "Describe the image."},
+				{Type: "image_url", ImageURL: &imageURL{URL: "data:image/png;base64,cG5n"}},
+			}},
+			{Role: "assistant", Content: "A cat."},
+		},
+		ResponseFormat: &responseFormat{Type: "json_object"},
+		MaxTokens:      100,
+		Stop:           []string{"END"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("toChatRequest() mismatch (-want +got):\n%s", diff)
+	}
+
+	unsupported := []*model.LLMRequest{
+		{Contents: []*genai.Content{{Role: "system", Parts: []*genai.Part{genai.NewPartFromText("hi")}}}},
+		{Contents: []*genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{genai.NewPartFromBytes([]byte("wav"), "audio/wav")}}}},
+		{Config: &genai.GenerateContentConfig{Tools: []*genai.Tool{{GoogleSearch: &genai.GoogleSearch{}}}}},
+	}
+	for _, req := range unsupported {
+		if _, err := toChatRequest("gpt-4o-mini", req); err == nil {
+			t.Errorf("toChatRequest(%v) succeeded, want error", req)
+		}
+	}
+}
+
+func TestSchemaToJSON(t *testing.T) {
+	schema := &genai.Schema{
+		Type:        genai.TypeObject,
+		Description: "A person.",
+		Properties: map[string]*genai.Schema{
+			"name":     {Type: genai.TypeString, MinLength: genai.Ptr[int64](1)},
+			"nickname": {Type: genai.TypeString, Nullable: genai.Ptr(true)},
+			"pets": {
+				Type:  genai.TypeArray,
+				Items: &genai.Schema{Type: genai.TypeString, Enum: []string{"cat", "dog"}},
+			},
+		},
+		Required: []string{"name"},
+	}
+	want := map[string]any{
+		"type":        "object",
+		"description": "A person.",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string", "minLength": int64(1)},
+			"nickname": map[string]any{"type": []string{"string", "null"}},
+			"pets": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": "string", "enum": []string{"cat", "dog"}},
+			},
+		},
+		"required": []string{"name"},
+	}
+	if diff := cmp.Diff(want, schemaToJSON(schema)); diff != "" {
+		t.Errorf("schemaToJSON() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// newTestClientConfig returns the ClientConfig configured for record and
+// replay of the test.
+func newTestClientConfig(t *testing.T) *ClientConfig {
+	t.Helper()
+	rrfile := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".httprr")
+	rr, err := httprr.Open(rrfile, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr.ScrubReq(func(req *http.Request) error {
+		req.Header.Del("Authorization")
+		return nil
+	})
+	apiKey := ""
+	if recording, _ := httprr.Recording(rrfile); !recording {
+		apiKey = "fakekey"
+	}
+	return &ClientConfig{
+		HTTPClient: &http.Client{Transport: rr},
+		APIKey:     apiKey,
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit is the rate of the calls allowed by a [RateLimiter].
+type RateLimit struct {
+	// Rate is the number of calls per second. Zero means no limit.
+	Rate float64
+	// Burst is the maximum number of calls made at once when the calls
+	// were idle. Defaults to 1.
+	Burst int
+}
+
+// RateLimiter limits the rate of the model calls on the client side, so
+// the calls wait for their turn instead of failing with rate limit errors.
+//
+// It keeps a token bucket per model name. It is safe for concurrent use and
+// is meant to be shared by the agents calling the same models.
+type RateLimiter struct {
+	defaultLimit RateLimit
+	limits       map[string]RateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// NewRateLimiter creates a [RateLimiter] applying the limits of the models
+// by name, and the default limit to the other models.
+func NewRateLimiter(defaultLimit RateLimit, limits map[string]RateLimit) *RateLimiter {
+	return &RateLimiter{
+		defaultLimit: defaultLimit,
+		limits:       limits,
+		buckets:      make(map[string]*tokenBucket),
+	}
+}
+
+// Wait blocks until a call to the model is allowed, and returns the time
+// waited. It returns an error if the context is done first.
+func (l *RateLimiter) Wait(ctx context.Context, modelName string) (time.Duration, error) {
+	limit, ok := l.limits[modelName]
+	if !ok {
+		limit = l.defaultLimit
+	}
+	if limit.Rate <= 0 {
+		return 0, ctx.Err()
+	}
+
+	l.mu.Lock()
+	b, ok := l.buckets[modelName]
+	if !ok {
+		burst := float64(max(limit.Burst, 1))
+		b = &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+		l.buckets[modelName] = b
+	}
+	delay := b.reserve(time.Now())
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return 0, ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		b.tokens++
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+}
+
+// tokenBucket holds up to burst tokens, refilled at rate tokens per second.
+// The tokens go negative when the calls are reserved ahead of time.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token and returns the time until it is available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := model.NewRateLimiter(model.RateLimit{Rate: 20, Burst: 2}, map[string]model.RateLimit{
+		"unlimited": {},
+	})
+
+	for range 5 {
+		if wait, err := limiter.Wait(t.Context(), "unlimited"); wait != 0 || err != nil {
+			t.Fatalf("Wait(unlimited) = (%v, %v), want (0, nil)", wait, err)
+		}
+	}
+
+	// The burst is allowed at once, the next call waits for a token.
+	for range 2 {
+		if wait, err := limiter.Wait(t.Context(), "limited"); wait != 0 || err != nil {
+			t.Fatalf("Wait(limited) = (%v, %v), want (0, nil)", wait, err)
+		}
+	}
+	start := time.Now()
+	wait, err := limiter.Wait(t.Context(), "limited")
+	if err != nil {
+		t.Fatalf("Wait(limited) failed: %v", err)
+	}
+	if wait < 40*time.Millisecond || time.Since(start) < 40*time.Millisecond {
+		t.Errorf("Wait(limited) waited %v (reported %v), want about 50ms", time.Since(start), wait)
+	}
+
+	// The buckets are per model.
+	if wait, err := limiter.Wait(t.Context(), "other"); wait != 0 || err != nil {
+		t.Errorf("Wait(other) = (%v, %v), want (0, nil)", wait, err)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	limiter := model.NewRateLimiter(model.RateLimit{Rate: 0.001}, nil)
+	if _, err := limiter.Wait(t.Context(), "model"); err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Wait(ctx, "model"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// RetryPolicy configures the retries of the failed model calls, with an
+// exponential backoff.
+//
+// Only the calls failed before returning a final response are retried, i.e.
+// a streamed call is re-issued if it fails after partial responses.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of calls, including the first one.
+	// Defaults to 3.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry. Defaults to 1 second.
+	InitialDelay time.Duration
+	// MaxDelay is the maximum delay between the attempts. Defaults to 1
+	// minute. The call is not retried if the server asks to retry after a
+	// longer delay.
+	MaxDelay time.Duration
+	// Multiplier is the growth factor of the delay after each attempt.
+	// Defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of the delay randomly added or removed, so the
+	// clients failed at the same time do not retry at the same time.
+	// Defaults to 0.2, a negative value disables the jitter.
+	Jitter float64
+	// IsRetryable reports whether the call failed with the error is retried.
+	// Defaults to [IsRetryableError].
+	IsRetryable func(error) bool
+}
+
+// Delay returns the delay before the next attempt of a call failed with
+// err after the given number of attempts, and reports whether the call
+// should be retried at all.
+//
+// The delay requested by the server, see [RetryAfter], takes precedence
+// over the backoff.
+func (p *RetryPolicy) Delay(attempts int, err error) (time.Duration, bool) {
+	if p == nil || err == nil {
+		return 0, false
+	}
+	isRetryable := p.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsRetryableError
+	}
+	if attempts >= cmp.Or(p.MaxAttempts, 3) || !isRetryable(err) {
+		return 0, false
+	}
+	maxDelay := cmp.Or(p.MaxDelay, time.Minute)
+	if d := RetryAfter(err); d > 0 {
+		return d, d <= maxDelay
+	}
+	delay := float64(cmp.Or(p.InitialDelay, time.Second)) * math.Pow(cmp.Or(p.Multiplier, 2), float64(attempts-1))
+	if jitter := cmp.Or(p.Jitter, 0.2); jitter > 0 {
+		delay *= 1 + jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(min(delay, float64(maxDelay))), true
+}
+
+// RetryAfterError is implemented by the errors of the model calls carrying
+// the delay requested by the server before retrying, e.g. the Retry-After
+// HTTP header.
+type RetryAfterError interface {
+	error
+	RetryAfter() time.Duration
+}
+
+// RetryAfter returns the delay requested by the server before retrying the
+// call failed with err, or 0 if none.
+//
+// It supports the errors implementing [RetryAfterError] and the
+// [genai.APIError] errors with the google.rpc.RetryInfo details.
+func RetryAfter(err error) time.Duration {
+	var retryAfterErr RetryAfterError
+	if errors.As(err, &retryAfterErr) {
+		return retryAfterErr.RetryAfter()
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) {
+		return retryInfoDelay(genaiErr.Details)
+	}
+	var genaiErrPtr *genai.APIError
+	if errors.As(err, &genaiErrPtr) {
+		return retryInfoDelay(genaiErrPtr.Details)
+	}
+	return 0
+}
+
+func retryInfoDelay(details []map[string]any) time.Duration {
+	for _, detail := range details {
+		if typ, _ := detail["@type"].(string); !strings.HasSuffix(typ, "google.rpc.RetryInfo") {
+			continue
+		}
+		s, _ := detail["retryDelay"].(string)
+		if d, err := time.ParseDuration(s); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// ParseRetryAfter parses the value of the Retry-After HTTP header, either a
+// number of seconds or a date. It returns 0 if the value is invalid.
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return max(time.Duration(seconds*float64(time.Second)), 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+type retryAfterError time.Duration
+
+func (e retryAfterError) Error() string             { return "rate limited" }
+func (e retryAfterError) HTTPStatusCode() int       { return http.StatusTooManyRequests }
+func (e retryAfterError) RetryAfter() time.Duration { return time.Duration(e) }
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	errUnavailable := genai.APIError{Code: 503}
+	noJitter := &model.RetryPolicy{Jitter: -1}
+	tests := []struct {
+		name      string
+		policy    *model.RetryPolicy
+		attempts  int
+		err       error
+		want      time.Duration
+		wantRetry bool
+	}{
+		{"nil policy", nil, 1, errUnavailable, 0, false},
+		{"no error", noJitter, 1, nil, 0, false},
+		{"first retry", noJitter, 1, errUnavailable, time.Second, true},
+		{"backoff", noJitter, 2, errUnavailable, 2 * time.Second, true},
+		{"max attempts", noJitter, 3, errUnavailable, 0, false},
+		{"not retryable", noJitter, 1, genai.APIError{Code: 400}, 0, false},
+		{
+			name:      "custom backoff",
+			policy:    &model.RetryPolicy{MaxAttempts: 10, InitialDelay: 100 * time.Millisecond, Multiplier: 3, MaxDelay: time.Second, Jitter: -1},
+			attempts:  3,
+			err:       errUnavailable,
+			want:      900 * time.Millisecond,
+			wantRetry: true,
+		},
+		{
+			name:      "max delay",
+			policy:    &model.RetryPolicy{MaxAttempts: 10, MaxDelay: 5 * time.Second, Jitter: -1},
+			attempts:  5,
+			err:       errUnavailable,
+			want:      5 * time.Second,
+			wantRetry: true,
+		},
+		{
+			name:      "custom classifier",
+			policy:    &model.RetryPolicy{Jitter: -1, IsRetryable: func(err error) bool { return err.Error() == "flaky" }},
+			attempts:  1,
+			err:       errors.New("flaky"),
+			want:      time.Second,
+			wantRetry: true,
+		},
+		{"retry after", noJitter, 1, fmt.Errorf("failed to call model: %w", retryAfterError(7*time.Second)), 7 * time.Second, true},
+		{"retry after too long", noJitter, 1, retryAfterError(time.Hour), time.Hour, false},
+		{
+			name:   "retry info",
+			policy: noJitter,
+			err: genai.APIError{Code: 429, Details: []map[string]any{
+				{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+				{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "12s"},
+			}},
+			attempts:  1,
+			want:      12 * time.Second,
+			wantRetry: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotRetry := tt.policy.Delay(tt.attempts, tt.err)
+			if got != tt.want || gotRetry != tt.wantRetry {
+				t.Errorf("Delay(%d, %v) = (%v, %v), want (%v, %v)", tt.attempts, tt.err, got, gotRetry, tt.want, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DelayJitter(t *testing.T) {
+	policy := &model.RetryPolicy{Jitter: 0.5}
+	for range 100 {
+		got, _ := policy.Delay(1, genai.APIError{Code: 503})
+		if got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("Delay() = %v, want within [500ms, 1.5s]", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"0.5", 500 * time.Millisecond},
+		{"-1", 0},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0}, // in the past
+	}
+	for _, tt := range tests {
+		if got := model.ParseRetryAfter(tt.value); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if got := model.ParseRetryAfter(date); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("ParseRetryAfter(%q) = %v, want about 1h", date, got)
+	}
+}
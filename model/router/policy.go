@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// Policy chooses the models to try for a request.
+type Policy interface {
+	// Route returns the models to try for the request, in order. The
+	// models are the models of the router, the policy can reorder them.
+	Route(ctx context.Context, req *model.LLMRequest, models []model.LLM) []model.LLM
+}
+
+// PolicyFunc is an adapter to use a function as a [Policy].
+type PolicyFunc func(ctx context.Context, req *model.LLMRequest, models []model.LLM) []model.LLM
+
+// Route implements [Policy].
+func (f PolicyFunc) Route(ctx context.Context, req *model.LLMRequest, models []model.LLM) []model.LLM {
+	return f(ctx, req, models)
+}
+
+// Observer is implemented by the policies learning from the model calls.
+type Observer interface {
+	// Observe is called with the latency of the first response of each
+	// model call, or of its failure.
+	Observe(modelName string, latency time.Duration, err error)
+}
+
+// ByCost returns a policy trying the cheapest models first. The costs are
+// keyed by model name, the models without cost are tried last.
+func ByCost(costs map[string]float64) Policy {
+	return PolicyFunc(func(_ context.Context, _ *model.LLMRequest, models []model.LLM) []model.LLM {
+		slices.SortStableFunc(models, func(a, b model.LLM) int {
+			costA, okA := costs[a.Name()]
+			costB, okB := costs[b.Name()]
+			switch {
+			case okA && okB:
+				return cmp.Compare(costA, costB)
+			case okA:
+				return -1
+			case okB:
+				return 1
+			default:
+				return 0
+			}
+		})
+		return models
+	})
+}
+
+// ByRequestSize returns a policy skipping the models too small for the
+// request. The maximum request sizes are keyed by model name, in the unit
+// of [RequestSize]. The models without maximum size accept any request.
+func ByRequestSize(maxSizes map[string]int) Policy {
+	return PolicyFunc(func(_ context.Context, req *model.LLMRequest, models []model.LLM) []model.LLM {
+		size := RequestSize(req)
+		return slices.DeleteFunc(models, func(m model.LLM) bool {
+			maxSize, ok := maxSizes[m.Name()]
+			return ok && size > maxSize
+		})
+	})
+}
+
+// RequestSize estimates the size of the request, in bytes of its texts,
+// function calls and responses, and inline data.
+func RequestSize(req *model.LLMRequest) int {
+	contents := req.Contents
+	if req.Config != nil && req.Config.SystemInstruction != nil {
+		contents = append(slices.Clip(contents), req.Config.SystemInstruction)
+	}
+	size := 0
+	for _, content := range contents {
+		if content == nil {
+			continue
+		}
+		for _, part := range content.Parts {
+			size += len(part.Text)
+			if part.InlineData != nil {
+				size += len(part.InlineData.Data)
+			}
+			if part.FunctionCall != nil {
+				size += jsonSize(part.FunctionCall.Args)
+			}
+			if part.FunctionResponse != nil {
+				size += jsonSize(part.FunctionResponse.Response)
+			}
+		}
+	}
+	return size
+}
+
+func jsonSize(v any) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// failureLatency is the latency recorded by the LatencyPolicy for the failed
+// model calls.
+const failureLatency = 30 * time.Second
+
+// LatencyPolicy is a policy trying the fastest models first, by moving
+// average of the latencies of their first responses. The models without
+// latency yet are tried first. The failed calls count as slow calls.
+type LatencyPolicy struct {
+	mu        sync.Mutex
+	latencies map[string]time.Duration
+}
+
+// NewLatencyPolicy returns a new [LatencyPolicy].
+func NewLatencyPolicy() *LatencyPolicy {
+	return &LatencyPolicy{latencies: make(map[string]time.Duration)}
+}
+
+// Route implements [Policy].
+func (p *LatencyPolicy) Route(_ context.Context, _ *model.LLMRequest, models []model.LLM) []model.LLM {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slices.SortStableFunc(models, func(a, b model.LLM) int {
+		return cmp.Compare(p.latencies[a.Name()], p.latencies[b.Name()])
+	})
+	return models
+}
+
+// Observe implements [Observer].
+func (p *LatencyPolicy) Observe(modelName string, latency time.Duration, err error) {
+	if err != nil {
+		latency = failureLatency
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	avg, ok := p.latencies[modelName]
+	if !ok {
+		p.latencies[modelName] = latency
+		return
+	}
+	// Exponential moving average, weighting the latest latency by 1/4.
+	p.latencies[modelName] = avg + (latency-avg)/4
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package router provides a [model.LLM] routing the requests to a list of
+// underlying models, failing over to the next model on retryable errors.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// MetadataKey is the key of the LLMResponse.CustomMetadata holding the name
+// of the underlying model which generated the response.
+const MetadataKey = "router_model"
+
+// DefaultFailoverErrorCodes are the LLMResponse.ErrorCode values failing
+// over to the next model by default.
+var DefaultFailoverErrorCodes = []string{"RESOURCE_EXHAUSTED", "UNAVAILABLE", "INTERNAL", "MALFORMED_FUNCTION_CALL"}
+
+// Config is used to create a router model with [New].
+type Config struct {
+	// Name of the router model. Defaults to "router".
+	Name string
+	// Models are the underlying models, in order of preference.
+	Models []model.LLM
+	// Policy orders the models to try for each request. Defaults to the
+	// order of Models.
+	Policy Policy
+	// IsRetryable reports whether the request fails over to the next model
+	// on the error. Defaults to model.IsRetryableError.
+	IsRetryable func(error) bool
+	// FailoverErrorCodes are the LLMResponse.ErrorCode values failing over
+	// to the next model. Defaults to DefaultFailoverErrorCodes.
+	FailoverErrorCodes []string
+}
+
+// New returns a [model.LLM] sending the requests to the first model chosen
+// by the policy, and failing over to the next ones on retryable errors. The
+// name of the model which generated each response is recorded in its
+// CustomMetadata under MetadataKey.
+//
+// A request fails over as long as no response was yielded. Once a model
+// streamed a partial response, its errors are returned as is, so the
+// consumers never receive the partial responses of two models.
+func New(cfg Config) (model.LLM, error) {
+	if len(cfg.Models) == 0 {
+		return nil, fmt.Errorf("at least one model is required")
+	}
+	if slices.Contains(cfg.Models, nil) {
+		return nil, fmt.Errorf("models must not be nil")
+	}
+	r := &routerModel{
+		name:          cfg.Name,
+		models:        slices.Clone(cfg.Models),
+		policy:        cfg.Policy,
+		isRetryable:   cfg.IsRetryable,
+		failoverCodes: cfg.FailoverErrorCodes,
+	}
+	if r.name == "" {
+		r.name = "router"
+	}
+	if r.policy == nil {
+		r.policy = PolicyFunc(func(_ context.Context, _ *model.LLMRequest, models []model.LLM) []model.LLM {
+			return models
+		})
+	}
+	if r.isRetryable == nil {
+		r.isRetryable = model.IsRetryableError
+	}
+	if r.failoverCodes == nil {
+		r.failoverCodes = DefaultFailoverErrorCodes
+	}
+	return r, nil
+}
+
+type routerModel struct {
+	name          string
+	models        []model.LLM
+	policy        Policy
+	isRetryable   func(error) bool
+	failoverCodes []string
+}
+
+func (r *routerModel) Name() string {
+	return r.name
+}
+
+// GenerateContent calls the models chosen by the policy until one succeeds.
+func (r *routerModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		models := r.policy.Route(ctx, req, slices.Clone(r.models))
+		if len(models) == 0 {
+			yield(nil, fmt.Errorf("no model to route the request to"))
+			return
+		}
+		var errs []error
+		for i, m := range models {
+			err := r.generate(ctx, m, req, stream, i == len(models)-1, yield)
+			if err == nil {
+				return
+			}
+			errs = append(errs, fmt.Errorf("model %s: %w", m.Name(), err))
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		yield(nil, fmt.Errorf("all models failed: %w", errors.Join(errs...)))
+	}
+}
+
+// generate yields the responses of the model. It returns an error if the
+// request must fail over to the next model.
+func (r *routerModel) generate(ctx context.Context, m model.LLM, req *model.LLMRequest, stream, last bool, yield func(*model.LLMResponse, error) bool) error {
+	observer, _ := r.policy.(Observer)
+	start := time.Now()
+	observed := false
+	observe := func(err error) {
+		if observer != nil && !observed {
+			observed = true
+			observer.Observe(m.Name(), time.Since(start), err)
+		}
+	}
+
+	yielded := false
+	for resp, err := range m.GenerateContent(ctx, cloneRequest(req), stream) {
+		canFailover := !last && !yielded
+		if err != nil {
+			observe(err)
+			if canFailover && r.isRetryable(err) {
+				return err
+			}
+			if !yield(resp, err) {
+				return nil
+			}
+			continue
+		}
+		if resp == nil {
+			continue
+		}
+		if !resp.Partial && resp.ErrorCode != "" && slices.Contains(r.failoverCodes, resp.ErrorCode) {
+			err := fmt.Errorf("model responded with error code %s: %s", resp.ErrorCode, resp.ErrorMessage)
+			observe(err)
+			if canFailover {
+				return err
+			}
+		}
+		observe(nil)
+		yielded = true
+		if !yield(withModelName(resp, m.Name()), nil) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// cloneRequest returns a copy of the request the models can modify, e.g. to
+// append contents or set headers, without affecting the next attempts.
+func cloneRequest(req *model.LLMRequest) *model.LLMRequest {
+	clone := *req
+	clone.Contents = slices.Clone(req.Contents)
+	if req.Config != nil {
+		cfg := *req.Config
+		clone.Config = &cfg
+	}
+	return &clone
+}
+
+func withModelName(resp *model.LLMResponse, name string) *model.LLMResponse {
+	metadata := maps.Clone(resp.CustomMetadata)
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata[MetadataKey] = name
+	resp.CustomMetadata = metadata
+	return resp
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package router_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/router"
+)
+
+// fakeModel yields its responses, and records the contents of the
+// requests.
+type fakeModel struct {
+	name      string
+	responses []fakeResponse
+	requests  [][]*genai.Content
+}
+
+type fakeResponse struct {
+	resp *model.LLMResponse
+	err  error
+}
+
+func (m *fakeModel) Name() string { return m.name }
+
+func (m *fakeModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	m.requests = append(m.requests, req.Contents)
+	// Models may modify the request.
+	req.Contents = append(req.Contents, genai.NewContentFromText("continue", genai.RoleUser))
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for _, r := range m.responses {
+			if !yield(r.resp, r.err) {
+				return
+			}
+		}
+	}
+}
+
+func text(s string, partial bool) fakeResponse {
+	return fakeResponse{resp: &model.LLMResponse{Content: genai.NewContentFromText(s, genai.RoleModel), Partial: partial}}
+}
+
+func failure(err error) fakeResponse {
+	return fakeResponse{err: err}
+}
+
+var (
+	errRateLimited = genai.APIError{Code: 429, Message: "quota exceeded"}
+	errUnavailable = genai.APIError{Code: 503, Message: "unavailable"}
+	errBadRequest  = genai.APIError{Code: 400, Message: "bad request"}
+)
+
+func TestRouter_GenerateContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		primary     []fakeResponse
+		secondary   []fakeResponse
+		want        []string
+		wantErrCode int
+		wantCalled  []int
+	}{
+		{
+			name:       "primary succeeds",
+			primary:    []fakeResponse{text("hello", false)},
+			secondary:  []fakeResponse{text("unused", false)},
+			want:       []string{"primary: hello"},
+			wantCalled: []int{1, 0},
+		},
+		{
+			name:       "fails over on rate limit",
+			primary:    []fakeResponse{failure(errRateLimited)},
+			secondary:  []fakeResponse{text("hello", false)},
+			want:       []string{"secondary: hello"},
+			wantCalled: []int{1, 1},
+		},
+		{
+			name:    "fails over on error code",
+			primary: []fakeResponse{{resp: &model.LLMResponse{ErrorCode: "RESOURCE_EXHAUSTED"}}},
+			secondary: []fakeResponse{
+				text("hello", false),
+			},
+			want:       []string{"secondary: hello"},
+			wantCalled: []int{1, 1},
+		},
+		{
+			name:        "no failover on non-retryable error",
+			primary:     []fakeResponse{failure(errBadRequest)},
+			secondary:   []fakeResponse{text("unused", false)},
+			wantErrCode: 400,
+			wantCalled:  []int{1, 0},
+		},
+		{
+			name:        "all models fail",
+			primary:     []fakeResponse{failure(errRateLimited)},
+			secondary:   []fakeResponse{failure(errUnavailable)},
+			wantErrCode: 503,
+			wantCalled:  []int{1, 1},
+		},
+		{
+			name:        "no failover after partial responses",
+			primary:     []fakeResponse{text("hel", true), failure(errUnavailable)},
+			secondary:   []fakeResponse{text("hel", true), text("lo", true), text("hello", false)},
+			want:        []string{"primary: hel (partial)"},
+			wantErrCode: 503,
+			wantCalled:  []int{1, 0},
+		},
+		{
+			name:        "no failover after final response",
+			primary:     []fakeResponse{text("hello", false), failure(errUnavailable)},
+			secondary:   []fakeResponse{text("unused", false)},
+			want:        []string{"primary: hello"},
+			wantErrCode: 503,
+			wantCalled:  []int{1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeModel{name: "primary", responses: tt.primary}
+			secondary := &fakeModel{name: "secondary", responses: tt.secondary}
+			llm, err := router.New(router.Config{Models: []model.LLM{primary, secondary}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := &model.LLMRequest{Contents: genai.Text("hi")}
+			var got []string
+			var gotErr error
+			for resp, err := range llm.GenerateContent(t.Context(), req, true) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				s := resp.CustomMetadata[router.MetadataKey].(string) + ": " + resp.Content.Parts[0].Text
+				if resp.Partial {
+					s += " (partial)"
+				}
+				got = append(got, s)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GenerateContent() responses mismatch (-want +got):\n%s", diff)
+			}
+			gotCode := 0
+			if apiErr := (genai.APIError{}); errors.As(gotErr, &apiErr) {
+				gotCode = apiErr.Code
+			}
+			if gotCode != tt.wantErrCode {
+				t.Errorf("GenerateContent() error = %v, want code %d", gotErr, tt.wantErrCode)
+			}
+			if diff := cmp.Diff(tt.wantCalled, []int{len(primary.requests), len(secondary.requests)}); diff != "" {
+				t.Errorf("GenerateContent() model calls mismatch (-want +got):\n%s", diff)
+			}
+			// Each model receives the original request.
+			for _, m := range []*fakeModel{primary, secondary} {
+				for _, contents := range m.requests {
+					if diff := cmp.Diff(genai.Text("hi"), contents); diff != "" {
+						t.Errorf("%s request contents mismatch (-want +got):\n%s", m.name, diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	for _, cfg := range []router.Config{
+		{},
+		{Models: []model.LLM{nil}},
+	} {
+		if _, err := router.New(cfg); err == nil {
+			t.Errorf("New(%v) succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestPolicies(t *testing.T) {
+	small := &fakeModel{name: "small"}
+	medium := &fakeModel{name: "medium"}
+	large := &fakeModel{name: "large"}
+	models := []model.LLM{large, medium, small}
+
+	latency := router.NewLatencyPolicy()
+	latency.Observe("large", 3*time.Second, nil)
+	latency.Observe("small", time.Second, nil)
+	latency.Observe("small", 5*time.Second, nil) // averages to 2s
+	latency.Observe("medium", time.Second, errUnavailable)
+
+	tests := []struct {
+		name   string
+		policy router.Policy
+		req    *model.LLMRequest
+		want   []string
+	}{
+		{
+			name:   "by cost",
+			policy: router.ByCost(map[string]float64{"small": 0.1, "large": 1}),
+			req:    &model.LLMRequest{},
+			want:   []string{"small", "large", "medium"},
+		},
+		{
+			name:   "by request size",
+			policy: router.ByRequestSize(map[string]int{"small": 5, "medium": 100}),
+			req: &model.LLMRequest{
+				Contents: genai.Text("a request of 29 bytes of text"),
+			},
+			want: []string{"large", "medium"},
+		},
+		{
+			name:   "by latency",
+			policy: latency,
+			req:    &model.LLMRequest{},
+			want:   []string{"small", "large", "medium"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range tt.policy.Route(t.Context(), tt.req, append([]model.LLM(nil), models...)) {
+				got = append(got, m.Name())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Route() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRouter_ObservesLatency(t *testing.T) {
+	slow := &fakeModel{name: "slow", responses: []fakeResponse{failure(errUnavailable)}}
+	fast := &fakeModel{name: "fast", responses: []fakeResponse{text("hello", false)}}
+	policy := router.NewLatencyPolicy()
+	llm, err := router.New(router.Config{Models: []model.LLM{slow, fast}, Policy: policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		for _, err := range llm.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// The failure of the slow model routes the second request to the fast
+	// model first.
+	if got, want := []int{len(slow.requests), len(fast.requests)}, []int{1, 2}; !cmp.Equal(got, want) {
+		t.Errorf("model calls = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/genai"
+)
+
+// TokenCounter is implemented by the LLMs able to count the prompt tokens
+// of a request before sending it.
+type TokenCounter interface {
+	CountTokens(ctx context.Context, req *LLMRequest) (int32, error)
+}
+
+// CountTokens returns the prompt token count of the request, counted by the
+// model if it implements [TokenCounter] or estimated with [EstimateTokens]
+// otherwise.
+func CountTokens(ctx context.Context, llm LLM, req *LLMRequest) (int32, error) {
+	if counter, ok := llm.(TokenCounter); ok {
+		return counter.CountTokens(ctx, req)
+	}
+	return EstimateTokens(req), nil
+}
+
+// bytesPerToken is the average number of bytes of text per token.
+const bytesPerToken = 4
+
+// mediaTokens is the token count estimated for each inline or file data,
+// e.g. an image.
+const mediaTokens = 258
+
+// EstimateTokens estimates the prompt token count of the request locally,
+// from the size of its system instruction, tools and contents. It is meant
+// for budgeting, not for exact accounting.
+func EstimateTokens(req *LLMRequest) int32 {
+	var bytes, media int
+	addContent := func(content *genai.Content) {
+		if content == nil {
+			return
+		}
+		for _, part := range content.Parts {
+			switch {
+			case part.InlineData != nil || part.FileData != nil:
+				media++
+			case part.FunctionCall != nil:
+				bytes += jsonSize(part.FunctionCall)
+			case part.FunctionResponse != nil:
+				bytes += jsonSize(part.FunctionResponse)
+			default:
+				bytes += len(part.Text)
+			}
+		}
+	}
+	for _, content := range req.Contents {
+		addContent(content)
+	}
+	if req.Config != nil {
+		addContent(req.Config.SystemInstruction)
+		for _, tool := range req.Config.Tools {
+			bytes += jsonSize(tool)
+		}
+	}
+	return int32((bytes+bytesPerToken-1)/bytesPerToken + media*mediaTokens)
+}
+
+func jsonSize(v any) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *model.LLMRequest
+		want int32
+	}{
+		{
+			name: "empty",
+			req:  &model.LLMRequest{},
+			want: 0,
+		},
+		{
+			name: "text",
+			req:  &model.LLMRequest{Contents: genai.Text(strings.Repeat("a", 401))},
+			want: 101,
+		},
+		{
+			name: "system instruction and media",
+			req: &model.LLMRequest{
+				Contents: []*genai.Content{{Role: genai.RoleUser, Parts: []*genai.Part{
+					genai.NewPartFromBytes([]byte("image"), "image/png"),
+					genai.NewPartFromText(strings.Repeat("a", 40)),
+				}}},
+				Config: &genai.GenerateContentConfig{SystemInstruction: genai.NewContentFromText(strings.Repeat("a", 40), genai.RoleUser)},
+			},
+			want: 258 + 20,
+		},
+		{
+			name: "function call and tools",
+			req: &model.LLMRequest{
+				Contents: []*genai.Content{genai.NewContentFromFunctionCall("f", map[string]any{"a": 1}, genai.RoleModel)},
+				Config: &genai.GenerateContentConfig{Tools: []*genai.Tool{{
+					FunctionDeclarations: []*genai.FunctionDeclaration{{Name: "f"}},
+				}}},
+			},
+			// {"args":{"a":1},"name":"f"} and {"functionDeclarations":[{"name":"f"}]}
+			want: 7 + 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := model.EstimateTokens(tt.req); got != tt.want {
+				t.Errorf("EstimateTokens() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+type countingModel struct{}
+
+func (countingModel) Name() string { return "counting" }
+
+func (countingModel) GenerateContent(context.Context, *model.LLMRequest, bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(func(*model.LLMResponse, error) bool) {}
+}
+
+func (countingModel) CountTokens(context.Context, *model.LLMRequest) (int32, error) {
+	return 42, nil
+}
+
+type nonCountingModel struct{ countingModel }
+
+func (nonCountingModel) CountTokens() {}
+
+func TestCountTokens(t *testing.T) {
+	req := &model.LLMRequest{Contents: genai.Text("12345678")}
+	if got, err := model.CountTokens(t.Context(), countingModel{}, req); got != 42 || err != nil {
+		t.Errorf("CountTokens(countingModel) = (%d, %v), want (42, nil)", got, err)
+	}
+	if got, err := model.CountTokens(t.Context(), nonCountingModel{}, req); got != 2 || err != nil {
+		t.Errorf("CountTokens(nonCountingModel) = (%d, %v), want (2, nil)", got, err)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package planner provides planners which let LLM agents generate a plan
+// before acting.
+//
+// A planner is set on llmagent.Config.Planner. Before each model call the
+// planner can extend the request, e.g. with planning instructions or a
+// thinking configuration. After each model call it can rewrite the response
+// parts, e.g. to mark the planning and reasoning parts as thoughts so they
+// are not treated as the agent's answer.
+package planner
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// Planner guides how an LLM agent plans and acts.
+type Planner interface {
+	// BuildPlanningInstruction returns the planning instruction appended to
+	// the system instruction of the request. It returns an empty string if
+	// no instruction is needed.
+	//
+	// It can also adjust the request directly, e.g. to configure thinking.
+	BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error)
+	// ProcessPlanningResponse post-processes the parts of the model response.
+	// It returns the new parts of the response, or nil to keep the parts
+	// unchanged.
+	ProcessPlanningResponse(ctx agent.CallbackContext, parts []*genai.Part) ([]*genai.Part, error)
+}
+
+// NewBuiltInPlanner returns a planner which relies on the built-in thinking
+// feature of the model.
+//
+// The thinking config is set on every request of the agent, overriding the
+// ThinkingConfig from the agent's GenerateContentConfig. The model thoughts
+// are returned as parts with Thought set to true.
+func NewBuiltInPlanner(thinkingConfig *genai.ThinkingConfig) Planner {
+	return &builtInPlanner{thinkingConfig: thinkingConfig}
+}
+
+type builtInPlanner struct {
+	thinkingConfig *genai.ThinkingConfig
+}
+
+// BuildPlanningInstruction implements Planner. It applies the thinking
+// config to the request and returns no instruction.
+func (p *builtInPlanner) BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error) {
+	if p.thinkingConfig == nil {
+		return "", nil
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	req.Config.ThinkingConfig = p.thinkingConfig
+	return "", nil
+}
+
+// ProcessPlanningResponse implements Planner. The model marks its thoughts
+// already, so the parts are unchanged.
+func (p *builtInPlanner) ProcessPlanningResponse(ctx agent.CallbackContext, parts []*genai.Part) ([]*genai.Part, error) {
+	return nil, nil
+}
+
+var _ Planner = (*builtInPlanner)(nil)
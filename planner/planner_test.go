@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
+)
+
+func TestBuiltInPlanner(t *testing.T) {
+	thinkingConfig := &genai.ThinkingConfig{IncludeThoughts: true, ThinkingBudget: genai.Ptr[int32](1024)}
+	p := planner.NewBuiltInPlanner(thinkingConfig)
+
+	req := &model.LLMRequest{}
+	instruction, err := p.BuildPlanningInstruction(nil, req)
+	if err != nil {
+		t.Fatalf("BuildPlanningInstruction() failed: %v", err)
+	}
+	if instruction != "" {
+		t.Errorf("BuildPlanningInstruction() = %q, want empty", instruction)
+	}
+	if req.Config == nil || req.Config.ThinkingConfig != thinkingConfig {
+		t.Errorf("BuildPlanningInstruction() did not set the thinking config, got request config %+v", req.Config)
+	}
+
+	parts := []*genai.Part{genai.NewPartFromText("answer")}
+	got, err := p.ProcessPlanningResponse(nil, parts)
+	if err != nil || got != nil {
+		t.Errorf("ProcessPlanningResponse() = (%v, %v), want (nil, nil)", got, err)
+	}
+}
+
+func TestPlanReActPlanner_BuildPlanningInstruction(t *testing.T) {
+	p := planner.NewPlanReActPlanner()
+
+	instruction, err := p.BuildPlanningInstruction(nil, &model.LLMRequest{})
+	if err != nil {
+		t.Fatalf("BuildPlanningInstruction() failed: %v", err)
+	}
+	for _, tag := range []string{planner.PlanningTag, planner.ReplanningTag, planner.ReasoningTag, planner.ActionTag, planner.FinalAnswerTag} {
+		if !strings.Contains(instruction, tag) {
+			t.Errorf("BuildPlanningInstruction() does not mention %q", tag)
+		}
+	}
+}
+
+func TestPlanReActPlanner_ProcessPlanningResponse(t *testing.T) {
+	fc := func(name string) *genai.Part {
+		return genai.NewPartFromFunctionCall(name, map[string]any{})
+	}
+	thought := func(text string) *genai.Part {
+		return &genai.Part{Text: text, Thought: true}
+	}
+
+	for _, tc := range []struct {
+		name  string
+		parts []*genai.Part
+		want  []*genai.Part
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "planning and function calls",
+			parts: []*genai.Part{
+				genai.NewPartFromText(planner.PlanningTag + " 1. search"),
+				fc("search"),
+				fc("lookup"),
+				genai.NewPartFromText("hallucinated"),
+				fc("ignored"),
+			},
+			want: []*genai.Part{
+				thought(planner.PlanningTag + " 1. search"),
+				fc("search"),
+				fc("lookup"),
+			},
+		},
+		{
+			name: "function calls without name are dropped",
+			parts: []*genai.Part{
+				fc(""),
+				fc("search"),
+			},
+			want: []*genai.Part{
+				fc("search"),
+			},
+		},
+		{
+			name: "final answer",
+			parts: []*genai.Part{
+				genai.NewPartFromText(planner.ReasoningTag + " done. " + planner.FinalAnswerTag + " 42"),
+			},
+			want: []*genai.Part{
+				thought(planner.ReasoningTag + " done. " + planner.FinalAnswerTag),
+				genai.NewPartFromText(" 42"),
+			},
+		},
+		{
+			name: "plain text",
+			parts: []*genai.Part{
+				genai.NewPartFromText("hello"),
+			},
+			want: []*genai.Part{
+				genai.NewPartFromText("hello"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := planner.NewPlanReActPlanner().ProcessPlanningResponse(nil, tc.parts)
+			if err != nil {
+				t.Fatalf("ProcessPlanningResponse() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ProcessPlanningResponse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -26,9 +26,15 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/compaction"
+	"google.golang.org/adk/internal/agent/budget"
+	"google.golang.org/adk/internal/agent/checkpoint"
+	"google.golang.org/adk/internal/agent/limits"
 	"google.golang.org/adk/internal/agent/parentmap"
 	"google.golang.org/adk/internal/agent/runconfig"
 	artifactinternal "google.golang.org/adk/internal/artifact"
+	"google.golang.org/adk/internal/authinternal"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/llminternal"
 	imemory "google.golang.org/adk/internal/memory"
@@ -52,6 +58,29 @@ type Config struct {
 	ArtifactService artifact.Service
 	// optional
 	MemoryService memory.Service
+	// optional, adds the session to the MemoryService at the end of each
+	// invocation, e.g. to extract its facts with the memory/factmemory
+	// service.
+	AutoIngestMemory bool
+	// optional, stores the credentials obtained by tools for later
+	// invocations of the same user.
+	CredentialService auth.CredentialService
+	// optional, compacts the session events at the end of the invocations,
+	// so long sessions keep fitting in the model context window.
+	Compaction *compaction.Config
+	// optional, caches the stable prefix of the LLM requests, i.e. the
+	// instructions and tools, with the models supporting it.
+	ContextCacheConfig *model.ContextCacheConfig
+	// optional, retries the failed model calls of the LLM agents which do
+	// not set their own retry policy.
+	RetryPolicy *model.RetryPolicy
+	// optional, limits the rate of the model calls of the LLM agents which
+	// do not set their own rate limiter.
+	RateLimiter *model.RateLimiter
+	// optional, records agent state checkpoints in the session events, e.g.
+	// the active sub-agents of the workflow agents, so the invocations can
+	// be continued with [Runner.Resume].
+	Resumable bool
 	// optional
 	PluginConfig PluginConfig
 }
@@ -71,6 +100,16 @@ func New(cfg Config) (*Runner, error) {
 		return nil, fmt.Errorf("session service is required")
 	}
 
+	if cfg.AutoIngestMemory && cfg.MemoryService == nil {
+		return nil, fmt.Errorf("memory service is required to ingest memory")
+	}
+
+	if cfg.Compaction != nil {
+		if err := cfg.Compaction.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid compaction config: %w", err)
+		}
+	}
+
 	parents, err := parentmap.New(cfg.Agent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent tree: %w", err)
@@ -85,13 +124,20 @@ func New(cfg Config) (*Runner, error) {
 	}
 
 	return &Runner{
-		appName:         cfg.AppName,
-		rootAgent:       cfg.Agent,
-		sessionService:  cfg.SessionService,
-		artifactService: cfg.ArtifactService,
-		memoryService:   cfg.MemoryService,
-		parents:         parents,
-		pluginManager:   pluginManager,
+		appName:           cfg.AppName,
+		rootAgent:         cfg.Agent,
+		sessionService:    cfg.SessionService,
+		artifactService:   cfg.ArtifactService,
+		memoryService:     cfg.MemoryService,
+		autoIngestMemory:  cfg.AutoIngestMemory,
+		credentialService: cfg.CredentialService,
+		compaction:        cfg.Compaction,
+		contextCache:      cfg.ContextCacheConfig,
+		retryPolicy:       cfg.RetryPolicy,
+		rateLimiter:       cfg.RateLimiter,
+		resumable:         cfg.Resumable,
+		parents:           parents,
+		pluginManager:     pluginManager,
 	}, nil
 }
 
@@ -99,11 +145,18 @@ func New(cfg Config) (*Runner, error) {
 // processing, event generation, and interaction with various services like
 // artifact storage, session management, and memory.
 type Runner struct {
-	appName         string
-	rootAgent       agent.Agent
-	sessionService  session.Service
-	artifactService artifact.Service
-	memoryService   memory.Service
+	appName           string
+	rootAgent         agent.Agent
+	sessionService    session.Service
+	artifactService   artifact.Service
+	memoryService     memory.Service
+	autoIngestMemory  bool
+	credentialService auth.CredentialService
+	compaction        *compaction.Config
+	contextCache      *model.ContextCacheConfig
+	retryPolicy       *model.RetryPolicy
+	rateLimiter       *model.RateLimiter
+	resumable         bool
 
 	parents       parentmap.Map
 	pluginManager *plugininternal.PluginManager
@@ -113,6 +166,48 @@ type Runner struct {
 // For each user message it finds the proper agent within an agent tree to
 // continue the conversation within the session.
 func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return r.run(ctx, userID, sessionID, msg, cfg, nil, "")
+}
+
+// Resume continues the invocation of the session from its last agent state
+// checkpoint, e.g. after a process crash or when the long-running tools of
+// a paused invocation responded. The runner must be resumable.
+//
+// The msg is optional. If set, e.g. to the responses of the long-running
+// tools, it is appended to the session before resuming the invocation.
+func (r *Runner) Resume(ctx context.Context, userID, sessionID, invocationID string, msg *genai.Content, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	if !r.resumable {
+		return func(yield func(*session.Event, error) bool) {
+			yield(nil, fmt.Errorf("runner is not resumable"))
+		}
+	}
+	if invocationID == "" {
+		return func(yield func(*session.Event, error) bool) {
+			yield(nil, fmt.Errorf("invocation ID is required"))
+		}
+	}
+	return r.run(ctx, userID, sessionID, msg, cfg, nil, invocationID)
+}
+
+// RunLive runs the agent in live bidirectional streaming mode, yielding
+// events from agents. The user input, e.g. realtime audio or video, is sent
+// with the queue while the agent responds. The run ends when the queue is
+// closed.
+//
+// The models of the agents must implement [model.LiveLLM]. The
+// StreamingMode of the config is ignored.
+func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, queue *agent.LiveRequestQueue, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	if queue == nil {
+		return func(yield func(*session.Event, error) bool) {
+			yield(nil, fmt.Errorf("live request queue is required"))
+		}
+	}
+	cfg.StreamingMode = agent.StreamingModeBidi
+	return r.run(ctx, userID, sessionID, nil, cfg, queue, "")
+}
+
+// run runs a new invocation, or resumes the invocation if invocationID is set.
+func (r *Runner) run(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig, liveQueue *agent.LiveRequestQueue, invocationID string) iter.Seq2[*session.Event, error] {
 	// TODO(hakim): we need to validate whether cfg is compatible with the Agent.
 	//   see adk-python/src/google/adk/runners.py Runner._new_invocation_context.
 	// TODO: setup tracer.
@@ -129,17 +224,54 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 
 		storedSession := resp.Session
 
-		agentToRun, err := r.findAgentToRun(storedSession, msg)
+		userContent := msg
+		var agentToRun agent.Agent
+		if invocationID != "" {
+			agentToRun, userContent, err = r.findInvocationToResume(storedSession, invocationID)
+		} else {
+			agentToRun, err = r.findAgentToRun(storedSession, msg)
+		}
 		if err != nil {
 			yield(nil, err)
 			return
 		}
 
+		if r.resumable {
+			checkpoints := checkpoint.New()
+			if invocationID != "" {
+				checkpoints = checkpoint.FromEvents(r.rootAgent, invocationID, storedSession.Events())
+				if checkpoints.Ended(agentToRun.Name()) {
+					yield(nil, fmt.Errorf("invocation %q has already completed", invocationID))
+					return
+				}
+			}
+			ctx = checkpoint.ToContext(ctx, checkpoints)
+		}
+
 		ctx = parentmap.ToContext(ctx, r.parents)
 		ctx = runconfig.ToContext(ctx, &runconfig.RunConfig{
-			StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
+			StreamingMode:      runconfig.StreamingMode(cfg.StreamingMode),
+			LiveRequestQueue:   liveQueue,
+			ContextCacheConfig: r.contextCache,
+			RetryPolicy:        r.retryPolicy,
+			RateLimiter:        r.rateLimiter,
 		})
+		if cfg.Budget != nil && liveQueue == nil {
+			ctx = budget.ToContext(ctx, budget.New(*cfg.Budget))
+		}
+		// The agents run as tools count against the limits of the parent
+		// invocation.
+		if limits.FromContext(ctx) == nil && liveQueue == nil {
+			maxLLMCalls := cfg.MaxLLMCalls
+			if maxLLMCalls == 0 {
+				maxLLMCalls = agent.DefaultMaxLLMCalls
+			}
+			ctx = limits.ToContext(ctx, limits.New(maxLLMCalls, cfg.MaxToolIterations))
+		}
 		ctx = plugininternal.ToContext(ctx, r.pluginManager)
+		if r.credentialService != nil {
+			ctx = authinternal.ToContext(ctx, r.credentialService)
+		}
 
 		var artifacts agent.Artifacts
 		if r.artifactService != nil {
@@ -162,12 +294,13 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 		}
 
 		ctx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
-			Artifacts:   artifacts,
-			Memory:      memoryImpl,
-			Session:     sessioninternal.NewMutableSession(r.sessionService, storedSession),
-			Agent:       agentToRun,
-			UserContent: msg,
-			RunConfig:   &cfg,
+			Artifacts:    artifacts,
+			Memory:       memoryImpl,
+			Session:      sessioninternal.NewMutableSession(r.sessionService, storedSession),
+			Agent:        agentToRun,
+			UserContent:  userContent,
+			RunConfig:    &cfg,
+			InvocationID: invocationID,
 		})
 		ctx, err = r.appendMessageToSession(ctx, storedSession, msg, cfg.SaveInputBlobsAsArtifacts, r.pluginManager)
 		if err != nil {
@@ -229,10 +362,69 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.C
 			if !yield(event, nil) {
 				return
 			}
+			// The workflow agents, e.g. the loop agents, continue after their
+			// sub-agents end the invocation: stop at the exceeded limits.
+			if isLimitExceeded(event.ErrorCode) {
+				break
+			}
+		}
+
+		if r.compaction != nil {
+			// The events of the invocation are already committed, a failed
+			// compaction is retried at the end of the next invocation.
+			if err := r.compact(ctx, storedSession); err != nil {
+				log.Printf("Failed to compact session %s: %v", storedSession.ID(), err)
+			}
+		}
+		if r.autoIngestMemory {
+			if err := r.ingestMemory(ctx, storedSession); err != nil {
+				log.Printf("Failed to add session %s to memory: %v", storedSession.ID(), err)
+			}
 		}
 	}
 }
 
+// compact appends a compaction event to the session if the compaction config
+// triggers it.
+func (r *Runner) compact(ctx agent.InvocationContext, storedSession session.Session) error {
+	resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   storedSession.AppName(),
+		UserID:    storedSession.UserID(),
+		SessionID: storedSession.ID(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get session for compaction: %w", err)
+	}
+	c, err := r.compaction.Compact(ctx, resp.Session.Events())
+	if err != nil {
+		return fmt.Errorf("failed to compact session events: %w", err)
+	}
+	if c == nil {
+		return nil
+	}
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = "user"
+	event.Actions.Compaction = c
+	if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+		return fmt.Errorf("failed to add compaction event to session: %w", err)
+	}
+	return nil
+}
+
+// ingestMemory adds the session, with the events of the invocation, to the
+// memory service.
+func (r *Runner) ingestMemory(ctx agent.InvocationContext, storedSession session.Session) error {
+	resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   storedSession.AppName(),
+		UserID:    storedSession.UserID(),
+		SessionID: storedSession.ID(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get session for memory: %w", err)
+	}
+	return r.memoryService.AddSession(ctx, resp.Session)
+}
+
 func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSession session.Session, msg *genai.Content, saveInputBlobsAsArtifacts bool, pluginManager *plugininternal.PluginManager) (agent.InvocationContext, error) {
 	if msg == nil {
 		return ctx, nil
@@ -287,6 +479,44 @@ func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSessi
 	return ctx, nil
 }
 
+func isLimitExceeded(errorCode string) bool {
+	switch errorCode {
+	case agent.ErrorCodeBudgetExceeded, agent.ErrorCodeMaxLLMCallsExceeded, agent.ErrorCodeMaxToolIterationsExceeded:
+		return true
+	}
+	return false
+}
+
+// findInvocationToResume returns the agent which started the invocation and
+// the user content of the invocation.
+func (r *Runner) findInvocationToResume(storedSession session.Session, invocationID string) (agent.Agent, *genai.Content, error) {
+	var userContent *genai.Content
+	found := false
+	for event := range storedSession.Events().All() {
+		if event.InvocationID != invocationID {
+			continue
+		}
+		found = true
+		if event.Author == "user" {
+			if userContent == nil && len(utils.FunctionResponses(event.Content)) == 0 {
+				userContent = event.Content
+			}
+			continue
+		}
+		// The first agent event is authored by the agent which started the
+		// invocation.
+		if a := findAgent(r.rootAgent, event.Author); a != nil {
+			return a, userContent, nil
+		}
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("invocation %q not found in session %q", invocationID, storedSession.ID())
+	}
+	// The invocation stopped before any agent event.
+	a, err := r.findAgentToRun(storedSession, userContent)
+	return a, userContent, err
+}
+
 // findAgentToRun returns the agent that should handle the next request based on
 // session history.
 func (r *Runner) findAgentToRun(session session.Session, msg *genai.Content) (agent.Agent, error) {
@@ -19,16 +19,27 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/agent/workflowagents/loopagent"
+	"google.golang.org/adk/agent/workflowagents/sequentialagent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/fakellm"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
 )
 
 func TestRunner_findAgentToRun(t *testing.T) {
@@ -393,3 +404,676 @@ func createSession(t *testing.T, ctx context.Context, sessionID, appName, userID
 
 	return resp.Session
 }
+
+func TestRunner_RunLive(t *testing.T) {
+	ctx := context.Background()
+	appName, userID, sessionID := "testApp", "testUser", "testSession"
+
+	type weatherArgs struct{}
+	type weatherResult struct {
+		Forecast string `json:"forecast"`
+	}
+	weather, err := functiontool.New(functiontool.Config{
+		Name:        "weather",
+		Description: "returns the weather",
+	}, func(tool.Context, weatherArgs) (weatherResult, error) {
+		return weatherResult{Forecast: "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	liveModel := &fakeLiveModel{
+		respond: func(content *genai.Content) []*model.LLMResponse {
+			if len(content.Parts) > 0 && content.Parts[0].FunctionResponse != nil {
+				return []*model.LLMResponse{
+					{Content: genai.NewContentFromText("It is ", genai.RoleModel), Partial: true},
+					{Content: genai.NewContentFromText("sunny", genai.RoleModel), Partial: true},
+					{Content: genai.NewContentFromText("It is sunny", genai.RoleModel)},
+					{TurnComplete: true},
+				}
+			}
+			return []*model.LLMResponse{{
+				Content: &genai.Content{
+					Role:  genai.RoleModel,
+					Parts: []*genai.Part{{FunctionCall: &genai.FunctionCall{ID: "call1", Name: "weather", Args: map[string]any{}}}},
+				},
+			}}
+		},
+	}
+	liveAgent := must(llmagent.New(llmagent.Config{
+		Name:  "live_agent",
+		Model: liveModel,
+		Tools: []tool.Tool{weather},
+	}))
+
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatalf("sessionService.Create() error = %v", err)
+	}
+	r, err := New(Config{
+		AppName:        appName,
+		Agent:          liveAgent,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	queue := agent.NewLiveRequestQueue()
+	userContent := &genai.Content{Parts: []*genai.Part{genai.NewPartFromText("What is the weather?")}}
+	queue.SendContent(userContent)
+
+	var got []string
+	for ev, err := range r.RunLive(ctx, userID, sessionID, queue, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("RunLive() error = %v", err)
+		}
+		got = append(got, liveEventSummary(ev))
+		if ev.TurnComplete {
+			queue.Close()
+		}
+	}
+
+	want := []string{
+		"user: What is the weather?",
+		"live_agent: call weather",
+		"live_agent: response weather",
+		"live_agent: It is  (partial)",
+		"live_agent: sunny (partial)",
+		"live_agent: It is sunny",
+		"live_agent: turn complete",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RunLive() events mismatch (-want +got):\n%s", diff)
+	}
+	if userContent.Role != "" {
+		t.Errorf("RunLive() set the role of the sent content to %q, want it unchanged", userContent.Role)
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: appName, UserID: userID, SessionID: sessionID})
+	if err != nil {
+		t.Fatalf("sessionService.Get() error = %v", err)
+	}
+	var stored []string
+	for ev := range resp.Session.Events().All() {
+		stored = append(stored, liveEventSummary(ev))
+	}
+	wantStored := []string{
+		"user: What is the weather?",
+		"live_agent: call weather",
+		"live_agent: response weather",
+		"live_agent: It is sunny",
+		"live_agent: turn complete",
+	}
+	if diff := cmp.Diff(wantStored, stored); diff != "" {
+		t.Errorf("session events mismatch (-want +got):\n%s", diff)
+	}
+	if !liveModel.config.Load().(bool) {
+		t.Errorf("Connect() was not called with a live connect config")
+	}
+}
+
+func TestRunner_RunLive_ModelWithoutLiveSupport(t *testing.T) {
+	ctx := context.Background()
+	appName, userID, sessionID := "testApp", "testUser", "testSession"
+
+	testAgent := must(llmagent.New(llmagent.Config{
+		Name: "test_agent",
+		// Only the model.LLM methods are promoted, hiding Connect.
+		Model: struct{ model.LLM }{&fakeLiveModel{}},
+	}))
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatalf("sessionService.Create() error = %v", err)
+	}
+	r, err := New(Config{
+		AppName:        appName,
+		Agent:          testAgent,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var gotErr error
+	for _, err := range r.RunLive(ctx, userID, sessionID, agent.NewLiveRequestQueue(), agent.RunConfig{}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "does not support live streaming") {
+		t.Errorf("RunLive() error = %v, want unsupported live streaming error", gotErr)
+	}
+}
+
+func liveEventSummary(ev *session.Event) string {
+	var text string
+	switch {
+	case len(utils.FunctionCalls(ev.Content)) > 0:
+		text = "call " + utils.FunctionCalls(ev.Content)[0].Name
+	case len(utils.FunctionResponses(ev.Content)) > 0:
+		text = "response " + utils.FunctionResponses(ev.Content)[0].Name
+	case ev.Content != nil:
+		text = utils.TextParts(ev.Content)[0]
+	case ev.TurnComplete:
+		text = "turn complete"
+	}
+	if ev.Partial {
+		text += " (partial)"
+	}
+	return ev.Author + ": " + text
+}
+
+// fakeLiveModel is a model.LiveLLM replying to each content sent to the
+// connection with the responses returned by respond.
+type fakeLiveModel struct {
+	respond func(content *genai.Content) []*model.LLMResponse
+	// config records whether a live connect config was requested.
+	config atomic.Value
+}
+
+func (m *fakeLiveModel) Name() string { return "fake-live" }
+
+func (m *fakeLiveModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(nil, fmt.Errorf("not supported"))
+	}
+}
+
+func (m *fakeLiveModel) Connect(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	m.config.Store(req.LiveConnectConfig != nil)
+	return &fakeLiveConnection{respond: m.respond, responses: make(chan *model.LLMResponse, 10), closed: make(chan struct{})}, nil
+}
+
+type fakeLiveConnection struct {
+	respond   func(content *genai.Content) []*model.LLMResponse
+	responses chan *model.LLMResponse
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (c *fakeLiveConnection) SendHistory(ctx context.Context, history []*genai.Content) error {
+	return nil
+}
+
+func (c *fakeLiveConnection) SendContent(ctx context.Context, content *genai.Content) error {
+	for _, resp := range c.respond(content) {
+		c.responses <- resp
+	}
+	return nil
+}
+
+func (c *fakeLiveConnection) SendRealtime(ctx context.Context, input genai.LiveRealtimeInput) error {
+	return nil
+}
+
+func (c *fakeLiveConnection) Receive(ctx context.Context) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for {
+			select {
+			case <-c.closed:
+				return
+			case resp := <-c.responses:
+				if !yield(resp, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *fakeLiveConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
+
+func TestRunner_Resume(t *testing.T) {
+	ctx := context.Background()
+	const appName, userID, sessionID = "testApp", "testUser", "testSession"
+
+	step := func(name string) agent.Agent {
+		return must(agent.New(agent.Config{
+			Name: name,
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					ev := session.NewEvent(ctx.InvocationID())
+					ev.Content = genai.NewContentFromText(name, genai.RoleModel)
+					yield(ev, nil)
+				}
+			},
+		}))
+	}
+	loop := must(loopagent.New(loopagent.Config{
+		AgentConfig:   agent.Config{Name: "loop", SubAgents: []agent.Agent{step("step2")}},
+		MaxIterations: 2,
+	}))
+	root := must(sequentialagent.New(sequentialagent.Config{
+		AgentConfig: agent.Config{Name: "root", SubAgents: []agent.Agent{step("step1"), loop, step("step3")}},
+	}))
+
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(Config{AppName: appName, Agent: root, SessionService: sessionService, Resumable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The process stops in the second iteration of the loop.
+	var invocationID string
+	var got []string
+	steps2 := 0
+	for ev, err := range r.Run(ctx, userID, sessionID, genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		invocationID = ev.InvocationID
+		got = append(got, resumeEventSummary(ev))
+		if ev.Author == "step2" {
+			if steps2++; steps2 == 2 {
+				break
+			}
+		}
+	}
+	want := []string{
+		"root: checkpoint map[current_sub_agent:step1 times_looped:0]",
+		"step1: step1",
+		"root: checkpoint map[current_sub_agent:loop times_looped:0]",
+		"loop: checkpoint map[current_sub_agent:step2 times_looped:0]",
+		"step2: step2",
+		"loop: checkpoint map[current_sub_agent:step2 times_looped:1]",
+		"step2: step2",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+	}
+
+	got = nil
+	for ev, err := range r.Resume(ctx, userID, sessionID, invocationID, nil, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+		if ev.InvocationID != invocationID {
+			t.Errorf("Resume() event invocation ID = %q, want %q", ev.InvocationID, invocationID)
+		}
+		got = append(got, resumeEventSummary(ev))
+	}
+	want = []string{
+		"step2: step2",
+		"loop: end",
+		"root: checkpoint map[current_sub_agent:step3 times_looped:0]",
+		"step3: step3",
+		"root: end",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Resume() events mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, err := range r.Resume(ctx, userID, sessionID, invocationID, nil, agent.RunConfig{}) {
+		if err == nil {
+			t.Error("Resume() of a completed invocation succeeded, want error")
+		}
+	}
+	for _, err := range r.Resume(ctx, userID, sessionID, "unknown", nil, agent.RunConfig{}) {
+		if err == nil {
+			t.Error("Resume() of an unknown invocation succeeded, want error")
+		}
+	}
+}
+
+func TestRunner_Resume_LongRunningTool(t *testing.T) {
+	ctx := context.Background()
+	const appName, userID, sessionID = "testApp", "testUser", "testSession"
+
+	// The agent waits for the response of a long-running tool.
+	waiting := must(agent.New(agent.Config{
+		Name: "waiting",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				events := ctx.Session().Events()
+				last := events.At(events.Len() - 1)
+				ev := session.NewEvent(ctx.InvocationID())
+				if resp := utils.FunctionResponses(last.Content); len(resp) > 0 {
+					ev.Content = genai.NewContentFromText(fmt.Sprintf("approved: %v", resp[0].Response["approved"]), genai.RoleModel)
+				} else {
+					ev.Content = genai.NewContentFromFunctionCall("approve", nil, genai.RoleModel)
+					ev.Content.Parts[0].FunctionCall.ID = "call1"
+					ev.LongRunningToolIDs = []string{"call1"}
+				}
+				yield(ev, nil)
+			}
+		},
+	}))
+	done := must(agent.New(agent.Config{
+		Name: "done",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Content = genai.NewContentFromText("done", genai.RoleModel)
+				yield(ev, nil)
+			}
+		},
+	}))
+	root := must(sequentialagent.New(sequentialagent.Config{
+		AgentConfig: agent.Config{Name: "root", SubAgents: []agent.Agent{waiting, done}},
+	}))
+
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(Config{AppName: appName, Agent: root, SessionService: sessionService, Resumable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var invocationID string
+	var got []string
+	for ev, err := range r.Run(ctx, userID, sessionID, genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		invocationID = ev.InvocationID
+		got = append(got, resumeEventSummary(ev))
+	}
+	want := []string{
+		"root: checkpoint map[current_sub_agent:waiting times_looped:0]",
+		"waiting: call approve",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+	}
+
+	response := &genai.Content{
+		Role: genai.RoleUser,
+		Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+			ID:       "call1",
+			Name:     "approve",
+			Response: map[string]any{"approved": true},
+		}}},
+	}
+	got = nil
+	for ev, err := range r.Resume(ctx, userID, sessionID, invocationID, response, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+		got = append(got, resumeEventSummary(ev))
+	}
+	want = []string{
+		"waiting: approved: true",
+		"root: checkpoint map[current_sub_agent:done times_looped:0]",
+		"done: done",
+		"root: end",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Resume() events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_Resume_NotResumable(t *testing.T) {
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          must(agent.New(agent.Config{Name: "test_agent"})),
+		SessionService: session.InMemoryService(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Resume(context.Background(), "testUser", "testSession", "invocation", nil, agent.RunConfig{}) {
+		if err == nil {
+			t.Error("Resume() succeeded, want error")
+		}
+	}
+}
+
+func resumeEventSummary(ev *session.Event) string {
+	switch {
+	case ev.Actions.EndOfAgent:
+		return ev.Author + ": end"
+	case ev.Actions.AgentState != nil:
+		return fmt.Sprintf("%s: checkpoint %v", ev.Author, ev.Actions.AgentState)
+	case len(utils.FunctionCalls(ev.Content)) > 0:
+		return ev.Author + ": call " + utils.FunctionCalls(ev.Content)[0].Name
+	default:
+		return ev.Author + ": " + ev.Content.Parts[0].Text
+	}
+}
+
+func TestRunner_Budget(t *testing.T) {
+	ctx := context.Background()
+	const appName, userID = "testApp", "testUser"
+
+	// Each model call uses 100 tokens, 60 input and 40 output.
+	turn := fakellm.Turn{Responses: []*model.LLMResponse{{
+		Content:       genai.NewContentFromText("again", genai.RoleModel),
+		UsageMetadata: &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 60, CandidatesTokenCount: 40, TotalTokenCount: 100},
+	}}}
+	tests := []struct {
+		name       string
+		budget     agent.Budget
+		turns      int
+		wantTokens int64
+		// wantTokensOver is set when the request tokens are estimated.
+		wantTokensOver int64
+	}{
+		{
+			name:       "exceeded by the response",
+			budget:     agent.Budget{MaxTokens: 250},
+			turns:      3,
+			wantTokens: 300,
+		},
+		{
+			name:           "exceeded by the request",
+			budget:         agent.Budget{MaxTokens: 200},
+			turns:          2,
+			wantTokensOver: 200,
+		},
+		{
+			name: "cost exceeded",
+			budget: agent.Budget{
+				MaxCost: 0.05,
+				Prices:  map[string]agent.TokenPrice{"fakellm": {Input: 100, Output: 500}},
+			},
+			turns:      2,
+			wantTokens: 200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			turns := make([]fakellm.Turn, tt.turns)
+			for i := range turns {
+				turns[i] = turn
+			}
+			worker := must(llmagent.New(llmagent.Config{Name: "worker", Model: fakellm.New(t, turns...)}))
+			loop := must(loopagent.New(loopagent.Config{
+				AgentConfig: agent.Config{Name: "loop", SubAgents: []agent.Agent{worker}},
+			}))
+
+			sessionService := session.InMemoryService()
+			resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := New(Config{AppName: appName, Agent: loop, SessionService: sessionService})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var events []*session.Event
+			for ev, err := range r.Run(ctx, userID, resp.Session.ID(), genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{Budget: &tt.budget}) {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				events = append(events, ev)
+			}
+
+			if got := len(events); got != tt.turns+1 {
+				t.Fatalf("Run() yielded %d events, want %d", got, tt.turns+1)
+			}
+			last := events[len(events)-1]
+			if last.ErrorCode != agent.ErrorCodeBudgetExceeded || last.Author != "worker" {
+				t.Errorf("Run() last event = %s: %s %s, want worker: %s", last.Author, last.ErrorCode, last.ErrorMessage, agent.ErrorCodeBudgetExceeded)
+			}
+			usage, _ := last.CustomMetadata["budget"].(map[string]any)
+			gotTokens, _ := usage["tokens"].(int64)
+			if tt.wantTokensOver > 0 {
+				if gotTokens <= tt.wantTokensOver {
+					t.Errorf("Run() budget event tokens = %d, want over %d", gotTokens, tt.wantTokensOver)
+				}
+			} else if gotTokens != tt.wantTokens {
+				t.Errorf("Run() budget event tokens = %d, want %d", gotTokens, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestRunner_Limits(t *testing.T) {
+	ctx := context.Background()
+	const appName, userID = "testApp", "testUser"
+
+	type echoArgs struct{}
+	echo, err := functiontool.New(functiontool.Config{Name: "echo", Description: "echoes"}, func(tool.Context, echoArgs) (map[string]any, error) {
+		return map[string]any{"ok": true}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	times := func(n int, turn fakellm.Turn) []fakellm.Turn {
+		turns := make([]fakellm.Turn, n)
+		for i := range turns {
+			turns[i] = turn
+		}
+		return turns
+	}
+
+	tests := []struct {
+		name  string
+		agent func(t *testing.T) agent.Agent
+		cfg   agent.RunConfig
+		want  []string
+	}{
+		{
+			name: "max llm calls in a loop",
+			agent: func(t *testing.T) agent.Agent {
+				worker := must(llmagent.New(llmagent.Config{Name: "worker", Model: fakellm.New(t, times(3, fakellm.Turn{Responses: fakellm.Text("again")})...)}))
+				return must(loopagent.New(loopagent.Config{AgentConfig: agent.Config{Name: "loop", SubAgents: []agent.Agent{worker}}}))
+			},
+			cfg: agent.RunConfig{MaxLLMCalls: 3},
+			want: []string{
+				"worker: again",
+				"worker: again",
+				"worker: again",
+				"worker: " + agent.ErrorCodeMaxLLMCallsExceeded,
+			},
+		},
+		{
+			name: "max tool iterations",
+			agent: func(t *testing.T) agent.Agent {
+				return must(llmagent.New(llmagent.Config{
+					Name:  "worker",
+					Model: fakellm.New(t, times(3, fakellm.Turn{Responses: fakellm.FunctionCall("echo", nil)})...),
+					Tools: []tool.Tool{echo},
+				}))
+			},
+			cfg: agent.RunConfig{MaxToolIterations: 2},
+			want: []string{
+				"worker: call echo",
+				"worker: response echo",
+				"worker: call echo",
+				"worker: response echo",
+				"worker: call echo",
+				"worker: " + agent.ErrorCodeMaxToolIterationsExceeded,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionService := session.InMemoryService()
+			resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := New(Config{AppName: appName, Agent: tt.agent(t), SessionService: sessionService})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for ev, err := range r.Run(ctx, userID, resp.Session.ID(), genai.NewContentFromText("go", genai.RoleUser), tt.cfg) {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				got = append(got, limitEventSummary(ev))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func limitEventSummary(ev *session.Event) string {
+	if ev.ErrorCode != "" {
+		return ev.Author + ": " + ev.ErrorCode
+	}
+	part := ev.Content.Parts[0]
+	switch {
+	case part.FunctionCall != nil:
+		return ev.Author + ": call " + part.FunctionCall.Name
+	case part.FunctionResponse != nil:
+		return ev.Author + ": response " + part.FunctionResponse.Name
+	}
+	return ev.Author + ": " + part.Text
+}
+
+func TestRunner_AutoIngestMemory(t *testing.T) {
+	ctx := context.Background()
+	const appName, userID = "testApp", "testUser"
+
+	a := must(llmagent.New(llmagent.Config{
+		Name:  "bot",
+		Model: fakellm.New(t, fakellm.Turn{Responses: fakellm.Text("Zurich is lovely")}),
+	}))
+	sessionService := session.InMemoryService()
+	memoryService := memory.InMemoryService()
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(Config{
+		AppName:          appName,
+		Agent:            a,
+		SessionService:   sessionService,
+		MemoryService:    memoryService,
+		AutoIngestMemory: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, err := range r.Run(ctx, userID, resp.Session.ID(), genai.NewContentFromText("I live in Zurich", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	got, err := memoryService.Search(ctx, &memory.SearchRequest{AppName: appName, UserID: userID, Query: "zurich"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, m := range got.Memories {
+		texts = append(texts, m.Content.Parts[0].Text)
+	}
+	slices.Sort(texts)
+	if diff := cmp.Diff([]string{"I live in Zurich", "Zurich is lovely"}, texts); diff != "" {
+		t.Errorf("memory after Run() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := New(Config{AppName: appName, Agent: a, SessionService: sessionService, AutoIngestMemory: true}); err == nil {
+		t.Error("New() with AutoIngestMemory and no memory service succeeded, want error")
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/eval"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+)
+
+// EvalAPIController is the controller for the Eval API.
+type EvalAPIController struct {
+	setManager     eval.SetManager
+	resultsManager eval.SetResultsManager
+	sessionService session.Service
+	agentLoader    agent.Loader
+	judgeModel     model.LLM
+}
+
+// NewEvalAPIController creates a new EvalAPIController. The eval endpoints
+// respond with 501 Not Implemented if the managers are nil. The judge model
+// is only required by the LLM-as-judge metrics.
+func NewEvalAPIController(setManager eval.SetManager, resultsManager eval.SetResultsManager, sessionService session.Service, agentLoader agent.Loader, judgeModel model.LLM) *EvalAPIController {
+	return &EvalAPIController{
+		setManager:     setManager,
+		resultsManager: resultsManager,
+		sessionService: sessionService,
+		agentLoader:    agentLoader,
+		judgeModel:     judgeModel,
+	}
+}
+
+var errEvalNotConfigured = newStatusError(errors.New("eval is not configured"), http.StatusNotImplemented)
+
+// evalStatusError maps ErrNotFound to 404 and other errors to the code.
+func evalStatusError(err error, code int) error {
+	if errors.Is(err, eval.ErrNotFound) {
+		return newStatusError(err, http.StatusNotFound)
+	}
+	return newStatusError(err, code)
+}
+
+// ListEvalSetsHandler lists the IDs of the eval sets of an app.
+func (c *EvalAPIController) ListEvalSetsHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	ids, err := c.setManager.List(req.Context(), mux.Vars(req)["app_name"])
+	if err != nil {
+		return evalStatusError(err, http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(ids, http.StatusOK, rw)
+	return nil
+}
+
+// CreateEvalSetHandler creates an empty eval set.
+func (c *EvalAPIController) CreateEvalSetHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	set, err := c.setManager.Create(req.Context(), vars["app_name"], vars["eval_set_id"])
+	if err != nil {
+		return newStatusError(fmt.Errorf("failed to create eval set: %w", err), http.StatusBadRequest)
+	}
+	EncodeJSONResponse(set, http.StatusOK, rw)
+	return nil
+}
+
+// AddSessionToEvalSetHandler adds the invocations of a session as a new eval
+// case of an eval set.
+func (c *EvalAPIController) AddSessionToEvalSetHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	appName := vars["app_name"]
+	var addReq models.AddSessionToEvalSetRequest
+	if err := json.NewDecoder(req.Body).Decode(&addReq); err != nil {
+		return newStatusError(fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
+	}
+	resp, err := c.sessionService.Get(req.Context(), &session.GetRequest{
+		AppName:   appName,
+		UserID:    addReq.UserID,
+		SessionID: addReq.SessionID,
+	})
+	if err != nil {
+		return newStatusError(fmt.Errorf("failed to get session: %w", err), http.StatusNotFound)
+	}
+	var events []*session.Event
+	for ev := range resp.Session.Events().All() {
+		events = append(events, ev)
+	}
+	state := make(map[string]any)
+	for k, v := range resp.Session.State().All() {
+		state[k] = v
+	}
+	evalCase := &eval.Case{
+		EvalID:       addReq.EvalID,
+		Conversation: eval.InvocationsFromEvents(events),
+		SessionInput: &eval.SessionInput{
+			AppName: appName,
+			UserID:  addReq.UserID,
+			State:   state,
+		},
+		CreationTimestamp: float64(time.Now().UnixMicro()) / 1e6,
+	}
+	if err := c.setManager.AddCase(req.Context(), appName, vars["eval_set_id"], evalCase); err != nil {
+		return evalStatusError(fmt.Errorf("failed to add eval case: %w", err), http.StatusBadRequest)
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+	return nil
+}
+
+// ListEvalsHandler lists the IDs of the eval cases of an eval set.
+func (c *EvalAPIController) ListEvalsHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	set, err := c.setManager.Get(req.Context(), vars["app_name"], vars["eval_set_id"])
+	if err != nil {
+		return evalStatusError(err, http.StatusInternalServerError)
+	}
+	ids := []string{}
+	for _, c := range set.EvalCases {
+		ids = append(ids, c.EvalID)
+	}
+	EncodeJSONResponse(ids, http.StatusOK, rw)
+	return nil
+}
+
+// GetEvalHandler returns an eval case.
+func (c *EvalAPIController) GetEvalHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	set, err := c.setManager.Get(req.Context(), vars["app_name"], vars["eval_set_id"])
+	if err != nil {
+		return evalStatusError(err, http.StatusInternalServerError)
+	}
+	evalCase := set.Case(vars["eval_case_id"])
+	if evalCase == nil {
+		return newStatusError(fmt.Errorf("eval case %q not found", vars["eval_case_id"]), http.StatusNotFound)
+	}
+	EncodeJSONResponse(evalCase, http.StatusOK, rw)
+	return nil
+}
+
+// UpdateEvalHandler replaces an eval case.
+func (c *EvalAPIController) UpdateEvalHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	evalCase := &eval.Case{}
+	if err := json.NewDecoder(req.Body).Decode(evalCase); err != nil {
+		return newStatusError(fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
+	}
+	if evalCase.EvalID != "" && evalCase.EvalID != vars["eval_case_id"] {
+		return newStatusError(fmt.Errorf("eval case ID %q does not match the path", evalCase.EvalID), http.StatusBadRequest)
+	}
+	evalCase.EvalID = vars["eval_case_id"]
+	if err := c.setManager.UpdateCase(req.Context(), vars["app_name"], vars["eval_set_id"], evalCase); err != nil {
+		return evalStatusError(fmt.Errorf("failed to update eval case: %w", err), http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+	return nil
+}
+
+// DeleteEvalHandler deletes an eval case.
+func (c *EvalAPIController) DeleteEvalHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	if err := c.setManager.DeleteCase(req.Context(), vars["app_name"], vars["eval_set_id"], vars["eval_case_id"]); err != nil {
+		return evalStatusError(fmt.Errorf("failed to delete eval case: %w", err), http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+	return nil
+}
+
+// RunEvalHandler runs the eval cases of an eval set, saves the eval set
+// result and returns the eval case results.
+func (c *EvalAPIController) RunEvalHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.setManager == nil || c.resultsManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	appName := vars["app_name"]
+	var runReq models.RunEvalRequest
+	if req.ContentLength > 0 {
+		if err := json.NewDecoder(req.Body).Decode(&runReq); err != nil {
+			return newStatusError(fmt.Errorf("failed to decode request: %w", err), http.StatusBadRequest)
+		}
+	}
+	set, err := c.setManager.Get(req.Context(), appName, vars["eval_set_id"])
+	if err != nil {
+		return evalStatusError(err, http.StatusInternalServerError)
+	}
+	curAgent, err := c.agentLoader.LoadAgent(appName)
+	if err != nil {
+		return newStatusError(fmt.Errorf("failed to load agent: %w", err), http.StatusInternalServerError)
+	}
+	result, err := eval.Run(req.Context(), eval.RunConfig{
+		AppName:        appName,
+		Agent:          curAgent,
+		Set:            set,
+		EvalIDs:        runReq.EvalIDs,
+		Metrics:        runReq.EvalMetrics,
+		JudgeModel:     c.judgeModel,
+		SessionService: c.sessionService,
+	})
+	if err != nil {
+		return evalStatusError(fmt.Errorf("failed to run eval: %w", err), http.StatusInternalServerError)
+	}
+	if err := c.resultsManager.Save(req.Context(), appName, result); err != nil {
+		return newStatusError(fmt.Errorf("failed to save eval result: %w", err), http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(result.EvalCaseResults, http.StatusOK, rw)
+	return nil
+}
+
+// ListEvalResultsHandler lists the IDs of the eval set results of an app.
+func (c *EvalAPIController) ListEvalResultsHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.resultsManager == nil {
+		return errEvalNotConfigured
+	}
+	ids, err := c.resultsManager.List(req.Context(), mux.Vars(req)["app_name"])
+	if err != nil {
+		return evalStatusError(err, http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(ids, http.StatusOK, rw)
+	return nil
+}
+
+// GetEvalResultHandler returns an eval set result.
+func (c *EvalAPIController) GetEvalResultHandler(rw http.ResponseWriter, req *http.Request) error {
+	if c.resultsManager == nil {
+		return errEvalNotConfigured
+	}
+	vars := mux.Vars(req)
+	result, err := c.resultsManager.Get(req.Context(), vars["app_name"], vars["eval_result_id"])
+	if err != nil {
+		return evalStatusError(err, http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(result, http.StatusOK, rw)
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/eval"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/session"
+)
+
+func newEvalRouter(c *controllers.EvalAPIController) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/apps/{app_name}/eval_sets", controllers.NewErrorHandler(c.ListEvalSetsHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/apps/{app_name}/eval_sets/{eval_set_id}", controllers.NewErrorHandler(c.CreateEvalSetHandler)).Methods(http.MethodPost)
+	router.HandleFunc("/apps/{app_name}/eval_sets/{eval_set_id}/add_session", controllers.NewErrorHandler(c.AddSessionToEvalSetHandler)).Methods(http.MethodPost)
+	router.HandleFunc("/apps/{app_name}/eval_sets/{eval_set_id}/evals", controllers.NewErrorHandler(c.ListEvalsHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}", controllers.NewErrorHandler(c.GetEvalHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}", controllers.NewErrorHandler(c.DeleteEvalHandler)).Methods(http.MethodDelete)
+	router.HandleFunc("/apps/{app_name}/eval_sets/{eval_set_id}/run_eval", controllers.NewErrorHandler(c.RunEvalHandler)).Methods(http.MethodPost)
+	router.HandleFunc("/apps/{app_name}/eval_results", controllers.NewErrorHandler(c.ListEvalResultsHandler)).Methods(http.MethodGet)
+	router.HandleFunc("/apps/{app_name}/eval_results/{eval_result_id}", controllers.NewErrorHandler(c.GetEvalResultHandler)).Methods(http.MethodGet)
+	return router
+}
+
+func serve(t *testing.T, router http.Handler, method, path string, body any, wantStatus int, resp any) {
+	t.Helper()
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &reqBody)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != wantStatus {
+		t.Fatalf("%s %s status = %d, want %d: %s", method, path, rr.Code, wantStatus, rr.Body.String())
+	}
+	if resp != nil {
+		if err := json.NewDecoder(rr.Body).Decode(resp); err != nil {
+			t.Fatalf("%s %s failed to decode response: %v", method, path, err)
+		}
+	}
+}
+
+func TestEvalAPIController(t *testing.T) {
+	ctx := context.Background()
+	appName, userID := "test_agent", "testUser"
+
+	testAgent, err := agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Author = "test_agent"
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hello there", genai.RoleModel)}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService := session.InMemoryService()
+	created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userEvent := session.NewEvent("inv1")
+	userEvent.Author = "user"
+	userEvent.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hi", genai.RoleUser)}
+	agentEvent := session.NewEvent("inv1")
+	agentEvent.Author = "test_agent"
+	agentEvent.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hello there", genai.RoleModel)}
+	for _, ev := range []*session.Event{userEvent, agentEvent} {
+		if err := sessionService.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	router := newEvalRouter(controllers.NewEvalAPIController(
+		eval.LocalSetManager(dir), eval.LocalSetResultsManager(dir), sessionService, agent.NewSingleLoader(testAgent), nil))
+
+	serve(t, router, http.MethodPost, "/apps/test_agent/eval_sets/set_1", nil, http.StatusOK, nil)
+	serve(t, router, http.MethodPost, "/apps/test_agent/eval_sets/set_1", nil, http.StatusBadRequest, nil)
+	var setIDs []string
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_sets", nil, http.StatusOK, &setIDs)
+	if diff := cmp.Diff([]string{"set_1"}, setIDs); diff != "" {
+		t.Errorf("ListEvalSets mismatch (-want +got):\n%s", diff)
+	}
+
+	addReq := map[string]string{"evalId": "case_1", "sessionId": "s1", "userId": userID}
+	serve(t, router, http.MethodPost, "/apps/test_agent/eval_sets/set_1/add_session", addReq, http.StatusOK, nil)
+	serve(t, router, http.MethodPost, "/apps/test_agent/eval_sets/set_2/add_session", addReq, http.StatusNotFound, nil)
+
+	var evalIDs []string
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_sets/set_1/evals", nil, http.StatusOK, &evalIDs)
+	if diff := cmp.Diff([]string{"case_1"}, evalIDs); diff != "" {
+		t.Errorf("ListEvals mismatch (-want +got):\n%s", diff)
+	}
+	var evalCase eval.Case
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_sets/set_1/evals/case_1", nil, http.StatusOK, &evalCase)
+	if len(evalCase.Conversation) != 1 || evalCase.Conversation[0].FinalResponse.Parts[0].Text != "hello there" {
+		t.Errorf("GetEval conversation = %+v, want the invocation of the session", evalCase.Conversation)
+	}
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_sets/set_1/evals/missing", nil, http.StatusNotFound, nil)
+
+	runReq := map[string]any{"evalMetrics": []map[string]any{{"metricName": eval.MetricResponseMatchScore, "threshold": 0.8}}}
+	var caseResults []*eval.CaseResult
+	serve(t, router, http.MethodPost, "/apps/test_agent/eval_sets/set_1/run_eval", runReq, http.StatusOK, &caseResults)
+	if len(caseResults) != 1 || caseResults[0].FinalEvalStatus != eval.StatusPassed {
+		t.Fatalf("RunEval = %+v, want a passed eval case", caseResults)
+	}
+
+	var resultIDs []string
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_results", nil, http.StatusOK, &resultIDs)
+	if len(resultIDs) != 1 {
+		t.Fatalf("ListEvalResults = %v, want one result", resultIDs)
+	}
+	var result eval.SetResult
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_results/"+resultIDs[0], nil, http.StatusOK, &result)
+	if result.EvalSetID != "set_1" || len(result.EvalCaseResults) != 1 {
+		t.Errorf("GetEvalResult = %+v, want the result of set_1", result)
+	}
+
+	serve(t, router, http.MethodDelete, "/apps/test_agent/eval_sets/set_1/evals/case_1", nil, http.StatusOK, nil)
+	serve(t, router, http.MethodDelete, "/apps/test_agent/eval_sets/set_1/evals/case_1", nil, http.StatusNotFound, nil)
+}
+
+func TestEvalAPIController_NotConfigured(t *testing.T) {
+	router := newEvalRouter(controllers.NewEvalAPIController(nil, nil, session.InMemoryService(), nil, nil))
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_sets", nil, http.StatusNotImplemented, nil)
+	serve(t, router, http.MethodGet, "/apps/test_agent/eval_results", nil, http.StatusNotImplemented, nil)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+// maxCloseReasonLen is the maximum length of the reason of a WebSocket close
+// message, the control frames are limited to 125 bytes.
+const maxCloseReasonLen = 123
+
+var liveUpgrader = websocket.Upgrader{}
+
+// RunLiveHandler runs the agent in live bidirectional streaming mode over a
+// WebSocket connection. The session is identified by the app_name, user_id
+// and session_id query parameters, the optional modalities parameters set
+// the response modalities of the model.
+//
+// The client sends [agent.LiveRequest] JSON messages and receives the
+// events as JSON messages. The run ends when the client sends a close
+// request or disconnects.
+func (c *RuntimeAPIController) RunLiveHandler(rw http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	appName, userID, sessionID := query.Get("app_name"), query.Get("user_id"), query.Get("session_id")
+	if appName == "" || userID == "" || sessionID == "" {
+		return newStatusError(fmt.Errorf("app_name, user_id and session_id are required"), http.StatusBadRequest)
+	}
+	if err := c.validateSessionExists(req.Context(), appName, userID, sessionID); err != nil {
+		return err
+	}
+	r, rCfg, err := c.getRunner(models.RunAgentRequest{AppName: appName})
+	if err != nil {
+		return err
+	}
+	for _, modality := range query["modalities"] {
+		rCfg.ResponseModalities = append(rCfg.ResponseModalities, genai.Modality(modality))
+	}
+
+	conn, err := liveUpgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		// The upgrader already replied with an HTTP error.
+		return nil
+	}
+	defer conn.Close()
+
+	queue := agent.NewLiveRequestQueue()
+	go func() {
+		// Forward the client requests until it disconnects.
+		defer queue.Close()
+		for {
+			var liveReq agent.LiveRequest
+			if err := conn.ReadJSON(&liveReq); err != nil {
+				return
+			}
+			queue.Send(&liveReq)
+			if liveReq.Close {
+				return
+			}
+		}
+	}()
+
+	for event, err := range r.RunLive(req.Context(), userID, sessionID, queue, *rCfg) {
+		if err != nil {
+			reason := fmt.Sprintf("failed to run agent: %v", err)
+			if len(reason) > maxCloseReasonLen {
+				reason = reason[:maxCloseReasonLen]
+			}
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, reason))
+			return nil
+		}
+		if err := conn.WriteJSON(models.FromSessionEvent(*event)); err != nil {
+			// The client is gone.
+			return nil
+		}
+	}
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+// MemoryAPIController is the controller for the Memory API.
+type MemoryAPIController struct {
+	memoryService memory.Service
+}
+
+// NewMemoryAPIController creates a controller managing the entries of the
+// memory service. The handlers respond with 501 if the service does not
+// implement [memory.Manager].
+func NewMemoryAPIController(memoryService memory.Service) *MemoryAPIController {
+	return &MemoryAPIController{memoryService: memoryService}
+}
+
+// ListMemoriesHandler lists the memory entries of a user.
+func (c *MemoryAPIController) ListMemoriesHandler(rw http.ResponseWriter, req *http.Request) {
+	manager, ok := c.manager(rw)
+	if !ok {
+		return
+	}
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := manager.List(req.Context(), &memory.ListRequest{
+		AppName: sessionID.AppName,
+		UserID:  sessionID.UserID,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	memories := []models.Memory{}
+	for _, entry := range resp.Memories {
+		memories = append(memories, models.FromMemoryEntry(entry))
+	}
+	EncodeJSONResponse(memories, http.StatusOK, rw)
+}
+
+// DeleteMemoryHandler deletes a memory entry of a user.
+func (c *MemoryAPIController) DeleteMemoryHandler(rw http.ResponseWriter, req *http.Request) {
+	manager, ok := c.manager(rw)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	memoryID := vars["memory_id"]
+	if memoryID == "" {
+		http.Error(rw, "memory_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	err = manager.Delete(req.Context(), &memory.DeleteRequest{
+		AppName: sessionID.AppName,
+		UserID:  sessionID.UserID,
+		ID:      memoryID,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), memoryErrorStatus(err))
+		return
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+}
+
+// UpdateMemoryHandler replaces the content of a memory entry of a user.
+func (c *MemoryAPIController) UpdateMemoryHandler(rw http.ResponseWriter, req *http.Request) {
+	manager, ok := c.manager(rw)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	memoryID := vars["memory_id"]
+	if memoryID == "" {
+		http.Error(rw, "memory_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	var updateReq models.UpdateMemoryRequest
+	if err := json.NewDecoder(req.Body).Decode(&updateReq); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if updateReq.Content == nil {
+		http.Error(rw, "content is required", http.StatusBadRequest)
+		return
+	}
+	err = manager.Update(req.Context(), &memory.UpdateRequest{
+		AppName: sessionID.AppName,
+		UserID:  sessionID.UserID,
+		ID:      memoryID,
+		Content: updateReq.Content,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), memoryErrorStatus(err))
+		return
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+}
+
+// DeleteSessionMemoriesHandler deletes the memory entries added from a session.
+func (c *MemoryAPIController) DeleteSessionMemoriesHandler(rw http.ResponseWriter, req *http.Request) {
+	manager, ok := c.manager(rw)
+	if !ok {
+		return
+	}
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	err = manager.DeleteSession(req.Context(), &memory.DeleteSessionRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+}
+
+// manager returns the memory manager, or writes a 501 response if the memory
+// service does not support managing its entries.
+func (c *MemoryAPIController) manager(rw http.ResponseWriter) (memory.Manager, bool) {
+	manager, ok := c.memoryService.(memory.Manager)
+	if !ok {
+		http.Error(rw, "memory service does not support managing entries", http.StatusNotImplemented)
+		return nil, false
+	}
+	return manager, true
+}
+
+func memoryErrorStatus(err error) int {
+	if errors.Is(err, memory.ErrEntryNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
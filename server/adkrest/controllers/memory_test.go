@@ -0,0 +1,211 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+)
+
+func TestMemoryAPIController(t *testing.T) {
+	at := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+	tea := models.Memory{ID: memory.EntryID("sess1", "event"), Content: genai.NewContentFromText("I like tea", genai.RoleUser), Author: "user", Timestamp: at.Unix()}
+	zurich := models.Memory{ID: memory.EntryID("sess2", "event"), Content: genai.NewContentFromText("I live in Zurich", genai.RoleUser), Author: "user", Timestamp: at.Add(time.Hour).Unix()}
+	coffee := genai.NewContentFromText("I like coffee", genai.RoleUser)
+
+	tests := []struct {
+		name       string
+		handler    func(*controllers.MemoryAPIController) http.HandlerFunc
+		method     string
+		vars       map[string]string
+		body       string
+		wantStatus int
+		wantBody   string
+		// wantMemories are the memories of the user after the request.
+		wantMemories []models.Memory
+	}{
+		{
+			name:         "list",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.ListMemoriesHandler },
+			method:       http.MethodGet,
+			vars:         map[string]string{"app_name": "app", "user_id": "user"},
+			wantStatus:   http.StatusOK,
+			wantMemories: []models.Memory{tea, zurich},
+		},
+		{
+			name:       "list without user",
+			handler:    func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.ListMemoriesHandler },
+			method:     http.MethodGet,
+			vars:       map[string]string{"app_name": "app"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "user_id parameter is required",
+		},
+		{
+			name:         "delete",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.DeleteMemoryHandler },
+			method:       http.MethodDelete,
+			vars:         map[string]string{"app_name": "app", "user_id": "user", "memory_id": tea.ID},
+			wantStatus:   http.StatusOK,
+			wantMemories: []models.Memory{zurich},
+		},
+		{
+			name:         "delete not found",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.DeleteMemoryHandler },
+			method:       http.MethodDelete,
+			vars:         map[string]string{"app_name": "app", "user_id": "user", "memory_id": "3"},
+			wantStatus:   http.StatusNotFound,
+			wantBody:     "memory entry not found",
+			wantMemories: []models.Memory{tea, zurich},
+		},
+		{
+			name:         "delete session",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.DeleteSessionMemoriesHandler },
+			method:       http.MethodDelete,
+			vars:         map[string]string{"app_name": "app", "user_id": "user", "session_id": "sess1"},
+			wantStatus:   http.StatusOK,
+			wantMemories: []models.Memory{zurich},
+		},
+		{
+			name:         "update",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.UpdateMemoryHandler },
+			method:       http.MethodPatch,
+			vars:         map[string]string{"app_name": "app", "user_id": "user", "memory_id": tea.ID},
+			body:         `{"content": {"role": "user", "parts": [{"text": "I like coffee"}]}}`,
+			wantStatus:   http.StatusOK,
+			wantMemories: []models.Memory{{ID: tea.ID, Content: coffee, Author: "user", Timestamp: at.Unix()}, zurich},
+		},
+		{
+			name:         "update without content",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.UpdateMemoryHandler },
+			method:       http.MethodPatch,
+			vars:         map[string]string{"app_name": "app", "user_id": "user", "memory_id": tea.ID},
+			body:         `{}`,
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     "content is required",
+			wantMemories: []models.Memory{tea, zurich},
+		},
+		{
+			name:         "update not found",
+			handler:      func(c *controllers.MemoryAPIController) http.HandlerFunc { return c.UpdateMemoryHandler },
+			method:       http.MethodPatch,
+			vars:         map[string]string{"app_name": "app", "user_id": "other", "memory_id": tea.ID},
+			body:         `{"content": {"role": "user", "parts": [{"text": "I like coffee"}]}}`,
+			wantStatus:   http.StatusNotFound,
+			wantBody:     "memory entry not found",
+			wantMemories: []models.Memory{tea, zurich},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memoryService := memory.InMemoryService()
+			for _, sess := range []session.Session{
+				&memorySession{id: "sess1", text: "I like tea", at: at},
+				&memorySession{id: "sess2", text: "I live in Zurich", at: at.Add(time.Hour)},
+			} {
+				if err := memoryService.AddSession(t.Context(), sess); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := controllers.NewMemoryAPIController(memoryService)
+
+			req := httptest.NewRequest(tt.method, "/memories", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, tt.vars)
+			rr := httptest.NewRecorder()
+			tt.handler(c)(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned status %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("handler returned body %q, want containing %q", rr.Body.String(), tt.wantBody)
+			}
+			if tt.wantMemories == nil {
+				return
+			}
+
+			rr = httptest.NewRecorder()
+			req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/memories", nil), map[string]string{"app_name": "app", "user_id": "user"})
+			c.ListMemoriesHandler(rr, req)
+			var got []models.Memory
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode memories: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantMemories, got); diff != "" {
+				t.Errorf("memories mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMemoryAPIController_NotManager(t *testing.T) {
+	for _, service := range []memory.Service{nil, searchOnlyService{}} {
+		c := controllers.NewMemoryAPIController(service)
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/memories", nil), map[string]string{"app_name": "app", "user_id": "user"})
+		rr := httptest.NewRecorder()
+		c.ListMemoriesHandler(rr, req)
+		if rr.Code != http.StatusNotImplemented {
+			t.Errorf("ListMemoriesHandler() with %T returned status %d, want %d", service, rr.Code, http.StatusNotImplemented)
+		}
+	}
+}
+
+type searchOnlyService struct{}
+
+func (searchOnlyService) AddSession(context.Context, session.Session) error { return nil }
+func (searchOnlyService) Search(context.Context, *memory.SearchRequest) (*memory.SearchResponse, error) {
+	return &memory.SearchResponse{}, nil
+}
+
+// memorySession is a session of the user "user" with a single text event.
+type memorySession struct {
+	id, text string
+	at       time.Time
+}
+
+func (s *memorySession) ID() string             { return s.id }
+func (s *memorySession) AppName() string        { return "app" }
+func (s *memorySession) UserID() string         { return "user" }
+func (s *memorySession) Events() session.Events { return s }
+func (s *memorySession) All() iter.Seq[*session.Event] {
+	return slices.Values([]*session.Event{s.At(0)})
+}
+func (s *memorySession) Len() int { return 1 }
+func (s *memorySession) At(i int) *session.Event {
+	return &session.Event{
+		ID:          "event",
+		Author:      "user",
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(s.text, genai.RoleUser)},
+		Timestamp:   s.at,
+	}
+}
+func (s *memorySession) State() session.State      { panic("not implemented") }
+func (s *memorySession) LastUpdateTime() time.Time { panic("not implemented") }
@@ -23,7 +23,10 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/compaction"
 	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/server/adkrest/internal/models"
 	"google.golang.org/adk/session"
@@ -31,17 +34,59 @@ import (
 
 // RuntimeAPIController is the controller for the Runtime API.
 type RuntimeAPIController struct {
-	sseTimeout      time.Duration
-	sessionService  session.Service
-	memoryService   memory.Service
-	artifactService artifact.Service
-	agentLoader     agent.Loader
-	pluginConfig    runner.PluginConfig
+	sseTimeout         time.Duration
+	sessionService     session.Service
+	memoryService      memory.Service
+	artifactService    artifact.Service
+	credentialService  auth.CredentialService
+	agentLoader        agent.Loader
+	pluginConfig       runner.PluginConfig
+	compaction         *compaction.Config
+	contextCacheConfig *model.ContextCacheConfig
+	resumable          bool
+	retryPolicy        *model.RetryPolicy
+	rateLimiter        *model.RateLimiter
+	autoIngestMemory   bool
 }
 
 // NewRuntimeAPIController creates the controller for the Runtime API.
 func NewRuntimeAPIController(sessionService session.Service, memoryService memory.Service, agentLoader agent.Loader, artifactService artifact.Service, sseTimeout time.Duration, pluginConfig runner.PluginConfig) *RuntimeAPIController {
-	return &RuntimeAPIController{sessionService: sessionService, memoryService: memoryService, agentLoader: agentLoader, artifactService: artifactService, sseTimeout: sseTimeout, pluginConfig: pluginConfig}
+	return NewRuntimeAPIControllerFromConfig(RuntimeAPIConfig{SessionService: sessionService, MemoryService: memoryService, AgentLoader: agentLoader, ArtifactService: artifactService, SSETimeout: sseTimeout, PluginConfig: pluginConfig})
+}
+
+// RuntimeAPIConfig is used to create the controller for the Runtime API.
+type RuntimeAPIConfig struct {
+	SessionService session.Service
+	AgentLoader    agent.Loader
+	// SSETimeout is the write deadline of the streamed responses.
+	SSETimeout time.Duration
+
+	// optional
+	MemoryService memory.Service
+	// optional
+	ArtifactService artifact.Service
+	// optional, stores the credentials obtained by tools.
+	CredentialService auth.CredentialService
+	// optional
+	PluginConfig runner.PluginConfig
+	// optional, compacts the session events at the end of the invocations.
+	Compaction *compaction.Config
+	// optional, caches the stable prefix of the LLM requests.
+	ContextCacheConfig *model.ContextCacheConfig
+	// optional, records agent state checkpoints in the session events.
+	Resumable bool
+	// optional, retries the failed model calls of the LLM agents.
+	RetryPolicy *model.RetryPolicy
+	// optional, limits the rate of the model calls of the LLM agents.
+	RateLimiter *model.RateLimiter
+	// optional, adds the sessions to the MemoryService at the end of each
+	// invocation.
+	AutoIngestMemory bool
+}
+
+// NewRuntimeAPIControllerFromConfig creates the controller for the Runtime API from the config.
+func NewRuntimeAPIControllerFromConfig(cfg RuntimeAPIConfig) *RuntimeAPIController {
+	return &RuntimeAPIController{sessionService: cfg.SessionService, memoryService: cfg.MemoryService, agentLoader: cfg.AgentLoader, artifactService: cfg.ArtifactService, credentialService: cfg.CredentialService, sseTimeout: cfg.SSETimeout, pluginConfig: cfg.PluginConfig, compaction: cfg.Compaction, contextCacheConfig: cfg.ContextCacheConfig, resumable: cfg.Resumable, retryPolicy: cfg.RetryPolicy, rateLimiter: cfg.RateLimiter, autoIngestMemory: cfg.AutoIngestMemory}
 }
 
 // RunAgent executes a non-streaming agent run for a given session and message.
@@ -178,12 +223,19 @@ func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Ru
 	}
 
 	r, err := runner.New(runner.Config{
-		AppName:         req.AppName,
-		Agent:           curAgent,
-		SessionService:  c.sessionService,
-		MemoryService:   c.memoryService,
-		ArtifactService: c.artifactService,
-		PluginConfig:    c.pluginConfig,
+		AppName:            req.AppName,
+		Agent:              curAgent,
+		SessionService:     c.sessionService,
+		MemoryService:      c.memoryService,
+		ArtifactService:    c.artifactService,
+		CredentialService:  c.credentialService,
+		PluginConfig:       c.pluginConfig,
+		Compaction:         c.compaction,
+		ContextCacheConfig: c.contextCacheConfig,
+		Resumable:          c.resumable,
+		RetryPolicy:        c.retryPolicy,
+		RateLimiter:        c.rateLimiter,
+		AutoIngestMemory:   c.autoIngestMemory,
 	},
 	)
 	if err != nil {
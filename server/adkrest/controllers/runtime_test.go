@@ -15,11 +15,23 @@
 package controllers
 
 import (
+	"bytes"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/runner"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
 )
 
 func TestNewRuntimeAPIController_PluginsAssignment(t *testing.T) {
@@ -76,3 +88,55 @@ func TestNewRuntimeAPIController_PluginsAssignment(t *testing.T) {
 		})
 	}
 }
+
+func TestRunHandler_AutoIngestMemory(t *testing.T) {
+	ctx := t.Context()
+	appName, userID, sessionID := "test_agent", "testUser", "testSession"
+
+	testAgent, err := agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Author = "test_agent"
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hello", genai.RoleModel)}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID}); err != nil {
+		t.Fatal(err)
+	}
+	memoryService := memory.InMemoryService()
+	controller := NewRuntimeAPIControllerFromConfig(RuntimeAPIConfig{
+		SessionService:   sessionService,
+		AgentLoader:      agent.NewSingleLoader(testAgent),
+		SSETimeout:       10 * time.Second,
+		MemoryService:    memoryService,
+		AutoIngestMemory: true,
+	})
+
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName: appName, UserId: userID, SessionId: sessionID,
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw := httptest.NewRecorder()
+	if err := controller.RunHandler(rw, httptest.NewRequest(http.MethodPost, "/run", bytes.NewReader(body))); err != nil {
+		t.Fatalf("RunHandler() error = %v", err)
+	}
+
+	resp, err := memoryService.Search(ctx, &memory.SearchRequest{AppName: appName, UserID: userID, Query: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Memories) == 0 {
+		t.Errorf("Search() found no memories, want the ingested session")
+	}
+}
@@ -39,11 +39,26 @@ func NewHandler(config *launcher.Config, sseWriteTimeout time.Duration) http.Han
 	// where the ADK REST API will be served.
 	setupRouter(router,
 		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(config.SessionService)),
-		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(config.SessionService, config.MemoryService, config.AgentLoader, config.ArtifactService, sseWriteTimeout, config.PluginConfig)),
+		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIControllerFromConfig(controllers.RuntimeAPIConfig{
+			SessionService:     config.SessionService,
+			AgentLoader:        config.AgentLoader,
+			SSETimeout:         sseWriteTimeout,
+			MemoryService:      config.MemoryService,
+			ArtifactService:    config.ArtifactService,
+			CredentialService:  config.CredentialService,
+			PluginConfig:       config.PluginConfig,
+			Compaction:         config.Compaction,
+			ContextCacheConfig: config.ContextCacheConfig,
+			Resumable:          config.Resumable,
+			RetryPolicy:        config.RetryPolicy,
+			RateLimiter:        config.RateLimiter,
+			AutoIngestMemory:   config.AutoIngestMemory,
+		})),
 		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(config.AgentLoader)),
 		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(config.SessionService, config.AgentLoader, adkExporter)),
 		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(config.ArtifactService)),
-		&routers.EvalAPIRouter{},
+		routers.NewMemoryAPIRouter(controllers.NewMemoryAPIController(config.MemoryService)),
+		routers.NewEvalAPIRouter(controllers.NewEvalAPIController(config.EvalSetManager, config.EvalSetResultsManager, config.SessionService, config.AgentLoader, config.EvalJudgeModel)),
 	)
 	return router
 }
@@ -0,0 +1,33 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"google.golang.org/adk/eval"
+)
+
+// AddSessionToEvalSetRequest adds a session as an eval case to an eval set.
+type AddSessionToEvalSetRequest struct {
+	EvalID    string `json:"evalId"`
+	SessionID string `json:"sessionId"`
+	UserID    string `json:"userId"`
+}
+
+// RunEvalRequest runs the eval cases of an eval set.
+type RunEvalRequest struct {
+	// EvalIDs are the eval cases to run, all the cases run if empty.
+	EvalIDs     []string      `json:"evalIds"`
+	EvalMetrics []eval.Metric `json:"evalMetrics"`
+}
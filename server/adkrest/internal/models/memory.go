@@ -0,0 +1,44 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+)
+
+// Memory represents a memory entry of a user.
+type Memory struct {
+	ID        string         `json:"id"`
+	Content   *genai.Content `json:"content"`
+	Author    string         `json:"author"`
+	Timestamp int64          `json:"timestamp"`
+}
+
+// UpdateMemoryRequest is the body of a memory update.
+type UpdateMemoryRequest struct {
+	Content *genai.Content `json:"content"`
+}
+
+// FromMemoryEntry maps memory.Entry to Memory data struct
+func FromMemoryEntry(entry memory.Entry) Memory {
+	return Memory{
+		ID:        entry.ID,
+		Content:   entry.Content,
+		Author:    entry.Author,
+		Timestamp: entry.Timestamp.Unix(),
+	}
+}
@@ -21,28 +21,77 @@ import (
 )
 
 // EvalAPIRouter defines the routes for the Eval API.
-type EvalAPIRouter struct{}
+type EvalAPIRouter struct {
+	evalController *controllers.EvalAPIController
+}
+
+// NewEvalAPIRouter creates a new EvalAPIRouter.
+func NewEvalAPIRouter(controller *controllers.EvalAPIController) *EvalAPIRouter {
+	return &EvalAPIRouter{evalController: controller}
+}
 
-// Routes returns the routes for the Apps API.
+// Routes returns the routes for the Eval API.
 func (r *EvalAPIRouter) Routes() Routes {
 	return Routes{
 		Route{
 			Name:        "ListEvalSets",
 			Methods:     []string{http.MethodGet},
 			Pattern:     "/apps/{app_name}/eval_sets",
-			HandlerFunc: controllers.Unimplemented,
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.ListEvalSetsHandler),
 		},
 		Route{
-			Name:        "ListEvalSets",
+			Name:        "CreateEvalSet",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.CreateEvalSetHandler),
+		},
+		Route{
+			Name:        "AddSessionToEvalSet",
 			Methods:     []string{http.MethodPost, http.MethodOptions},
-			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_name}",
-			HandlerFunc: controllers.Unimplemented,
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}/add_session",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.AddSessionToEvalSetHandler),
+		},
+		Route{
+			Name:        "ListEvals",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}/evals",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.ListEvalsHandler),
+		},
+		Route{
+			Name:        "GetEval",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.GetEvalHandler),
+		},
+		Route{
+			Name:        "UpdateEval",
+			Methods:     []string{http.MethodPut, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.UpdateEvalHandler),
+		},
+		Route{
+			Name:        "DeleteEval",
+			Methods:     []string{http.MethodDelete, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}/evals/{eval_case_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.DeleteEvalHandler),
+		},
+		Route{
+			Name:        "RunEval",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/eval_sets/{eval_set_id}/run_eval",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.RunEvalHandler),
 		},
 		Route{
 			Name:        "ListEvalResults",
 			Methods:     []string{http.MethodGet},
 			Pattern:     "/apps/{app_name}/eval_results",
-			HandlerFunc: controllers.Unimplemented,
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.ListEvalResultsHandler),
+		},
+		Route{
+			Name:        "GetEvalResult",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/eval_results/{eval_result_id}",
+			HandlerFunc: controllers.NewErrorHandler(r.evalController.GetEvalResultHandler),
 		},
 	}
 }
@@ -88,6 +88,7 @@ type storageEvent struct {
 	CustomMetadata    dynamicJSON
 	UsageMetadata     dynamicJSON
 	CitationMetadata  dynamicJSON
+	CacheMetadata     dynamicJSON
 
 	Partial      *bool
 	TurnComplete *bool
@@ -183,6 +184,12 @@ func createStorageEvent(session session.Session, event *session.Event) (*storage
 			return nil, fmt.Errorf("failed to marshal citation metadata: %w", err)
 		}
 	}
+	if event.CacheMetadata != nil {
+		storageEv.CacheMetadata, err = json.Marshal(event.CacheMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cache metadata: %w", err)
+		}
+	}
 
 	return storageEv, nil
 }
@@ -242,6 +249,13 @@ func createEventFromStorageEvent(se *storageEvent) (*session.Event, error) {
 		}
 	}
 
+	var cacheMetadata *model.CacheMetadata
+	if len(se.CacheMetadata) > 0 {
+		if err := json.Unmarshal(se.CacheMetadata, &cacheMetadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cache metadata: %w", err)
+		}
+	}
+
 	// --- Handle JSON-encoded *string field ---
 	var toolIDs []string
 	if se.LongRunningToolIDsJSON != nil {
@@ -274,6 +288,7 @@ func createEventFromStorageEvent(se *storageEvent) (*session.Event, error) {
 			CustomMetadata:    customMetadata,
 			UsageMetadata:     usageMetadata,
 			CitationMetadata:  citationMetadata,
+			CacheMetadata:     cacheMetadata,
 			ErrorCode:         errorCode,
 			ErrorMessage:      errorMessage,
 			Partial:           partial,
@@ -20,7 +20,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/genai"
 
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool/toolconfirmation"
 )
@@ -150,6 +152,10 @@ type EventActions struct {
 
 	RequestedToolConfirmations map[string]toolconfirmation.ToolConfirmation
 
+	// RequestedAuthConfigs maps the function call IDs of the tools which
+	// requested end user credentials to the requested auth configs.
+	RequestedAuthConfigs map[string]auth.AuthConfig
+
 	// If true, it won't call model to summarize function response.
 	// Only valid for function response event.
 	SkipSummarization bool
@@ -157,6 +163,29 @@ type EventActions struct {
 	TransferToAgent string
 	// The agent is escalating to a higher level agent.
 	Escalate bool
+	// Compaction is set on the events summarizing a range of older events.
+	// The summary replaces these events in the LLM requests.
+	Compaction *EventCompaction
+	// AgentState is a checkpoint of the state of the author agent, e.g. the
+	// active sub-agent of a workflow agent. It is recorded by the resumable
+	// invocations to resume the agent where it stopped.
+	AgentState map[string]any
+	// EndOfAgent is set by the resumable invocations when the author agent
+	// has finished its run.
+	EndOfAgent bool
+}
+
+// EventCompaction is the summary of a range of session events.
+type EventCompaction struct {
+	// StartTime and EndTime are the timestamps of the first and last
+	// compacted events.
+	StartTime time.Time
+	EndTime   time.Time
+	// EventIDs are the IDs of the compacted events, including the events
+	// compacted by the previous compaction it supersedes, if any.
+	EventIDs []string
+	// CompactedContent is the summary of the compacted events.
+	CompactedContent *genai.Content
 }
 
 // Prefixes for defining session's state scopes
@@ -16,7 +16,9 @@ package vertexai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
 	"strconv"
 	"strings"
@@ -335,6 +337,9 @@ func (c *vertexAiClient) listSessionEvents(ctx context.Context, appName, session
 			event.GroundingMetadata = createGroundingMetadata(rpcResp.EventMetadata.GroundingMetadata)
 			if rpcResp.EventMetadata.CustomMetadata != nil {
 				event.CustomMetadata = rpcResp.EventMetadata.CustomMetadata.AsMap()
+				if err := popReservedMetadata(event); err != nil {
+					return nil, fmt.Errorf("error fetching session events: %w", err)
+				}
 			}
 		}
 		events = append(events, event)
@@ -533,8 +538,12 @@ func createAiplatformpbMetadata(event *session.Event) (*aiplatformpb.EventMetada
 		LongRunningToolIds: event.LongRunningToolIDs,
 		Branch:             event.Branch,
 	}
-	if event.CustomMetadata != nil {
-		customMetadata, err := structpb.NewStruct(event.CustomMetadata)
+	customMetadataMap, err := withReservedMetadata(event)
+	if err != nil {
+		return nil, err
+	}
+	if customMetadataMap != nil {
+		customMetadata, err := structpb.NewStruct(customMetadataMap)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert event customMetadata to structpb: %w", err)
 		}
@@ -774,3 +783,90 @@ func derefString(s *string) string {
 	}
 	return *s
 }
+
+// Reserved custom metadata keys holding the event fields the Vertex AI
+// events have no field for.
+const (
+	cacheMetadataKey = "_adk_cache_metadata"
+	compactionKey    = "_adk_compaction"
+	agentStateKey    = "_adk_agent_state"
+	endOfAgentKey    = "_adk_end_of_agent"
+)
+
+// withReservedMetadata returns the custom metadata of the event, with the
+// event fields Vertex AI has no field for stored under reserved keys.
+func withReservedMetadata(event *session.Event) (map[string]any, error) {
+	reserved := map[string]any{}
+	if event.CacheMetadata != nil {
+		reserved[cacheMetadataKey] = event.CacheMetadata
+	}
+	if event.Actions.Compaction != nil {
+		reserved[compactionKey] = event.Actions.Compaction
+	}
+	if event.Actions.AgentState != nil {
+		reserved[agentStateKey] = event.Actions.AgentState
+	}
+	if event.Actions.EndOfAgent {
+		reserved[endOfAgentKey] = true
+	}
+	if len(reserved) == 0 {
+		return event.CustomMetadata, nil
+	}
+	m := make(map[string]any, len(event.CustomMetadata)+len(reserved))
+	maps.Copy(m, event.CustomMetadata)
+	for key, field := range reserved {
+		v, err := toJSONValue(field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert event %s: %w", key, err)
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// popReservedMetadata moves the reserved keys of the custom metadata of the
+// event back to the event fields.
+func popReservedMetadata(event *session.Event) error {
+	fields := map[string]any{
+		cacheMetadataKey: &event.CacheMetadata,
+		compactionKey:    &event.Actions.Compaction,
+		agentStateKey:    &event.Actions.AgentState,
+		endOfAgentKey:    &event.Actions.EndOfAgent,
+	}
+	for key, field := range fields {
+		v, ok := event.CustomMetadata[key]
+		if !ok {
+			continue
+		}
+		delete(event.CustomMetadata, key)
+		if err := fromJSONValue(v, field); err != nil {
+			return fmt.Errorf("failed to convert event %s: %w", key, err)
+		}
+	}
+	if len(event.CustomMetadata) == 0 {
+		event.CustomMetadata = nil
+	}
+	return nil
+}
+
+// toJSONValue converts v to its JSON representation of maps and slices,
+// as accepted by structpb.
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func fromJSONValue(v, out any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
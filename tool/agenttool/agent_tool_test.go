@@ -15,6 +15,7 @@
 package agenttool_test
 
 import (
+	"fmt"
 	"log"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 	"google.golang.org/adk/internal/testutil"
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/fakellm"
 	"google.golang.org/adk/model/gemini"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
@@ -309,6 +311,52 @@ func TestAgentTool_Run_SkipSummarization(t *testing.T) {
 	}
 }
 
+func TestAgentTool_Run_CountsAgainstParentLimits(t *testing.T) {
+	child, err := llmagent.New(llmagent.Config{
+		Name:  "child",
+		Model: fakellm.New(t, fakellm.Turn{Responses: fakellm.Text("done")}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := llmagent.New(llmagent.Config{
+		Name:  "parent",
+		Model: fakellm.New(t, fakellm.Turn{Responses: fakellm.FunctionCall("child", map[string]any{"request": "go"})}),
+		Tools: []tool.Tool{agenttool.New(child, nil)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The model call of the child is the second call of the invocation,
+	// the parent cannot call the model again.
+	var got []string
+	runner := testutil.NewTestAgentRunner(t, parent)
+	for ev, err := range runner.RunContentWithConfig(t, "session", genai.NewContentFromText("go", genai.RoleUser), agent.RunConfig{MaxLLMCalls: 2}) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if ev.ErrorCode != "" {
+			got = append(got, ev.Author+": "+ev.ErrorCode)
+			continue
+		}
+		switch part := ev.Content.Parts[0]; {
+		case part.FunctionCall != nil:
+			got = append(got, ev.Author+": call "+part.FunctionCall.Name)
+		case part.FunctionResponse != nil:
+			got = append(got, ev.Author+": "+fmt.Sprint(part.FunctionResponse.Response))
+		}
+	}
+	want := []string{
+		"parent: call child",
+		"parent: map[result:done]",
+		"parent: " + agent.ErrorCodeMaxLLMCallsExceeded,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Run() events mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func createAgent(t *testing.T, inputSchema, outputSchema *genai.Schema) agent.Agent {
 	t.Helper()
 
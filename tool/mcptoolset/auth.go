@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"google.golang.org/adk/auth"
+)
+
+// withAuthTransport returns a copy of the transport whose HTTP client
+// authenticates the requests made for the tool calls, see withCredential.
+func withAuthTransport(transport mcp.Transport, scheme *auth.AuthScheme) (mcp.Transport, error) {
+	switch t := transport.(type) {
+	case *mcp.StreamableClientTransport:
+		authTransport := *t
+		authTransport.HTTPClient = authHTTPClient(t.HTTPClient, scheme)
+		return &authTransport, nil
+	case *mcp.SSEClientTransport:
+		authTransport := *t
+		authTransport.HTTPClient = authHTTPClient(t.HTTPClient, scheme)
+		return &authTransport, nil
+	default:
+		return nil, fmt.Errorf("auth is only supported with HTTP transports, got %T", transport)
+	}
+}
+
+func authHTTPClient(client *http.Client, scheme *auth.AuthScheme) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	authClient := *client
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	authClient.Transport = &authRoundTripper{base: base, scheme: scheme}
+	return &authClient
+}
+
+// authRoundTripper applies the credential carried by the request context.
+type authRoundTripper struct {
+	base   http.RoundTripper
+	scheme *auth.AuthScheme
+}
+
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	cred, ok := req.Context().Value(credentialCtxKey).(*auth.AuthCredential)
+	if !ok {
+		return rt.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	if err := auth.ApplyCredential(req, rt.scheme, cred); err != nil {
+		return nil, fmt.Errorf("failed to apply credential: %w", err)
+	}
+	return rt.base.RoundTrip(req)
+}
+
+// withCredential returns a context carrying the credential of the tool call
+// to the HTTP requests made by the MCP session.
+func withCredential(ctx context.Context, cred *auth.AuthCredential) context.Context {
+	return context.WithValue(ctx, credentialCtxKey, cred)
+}
+
+type ctxKey int
+
+const credentialCtxKey ctxKey = 0
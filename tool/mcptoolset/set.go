@@ -16,11 +16,14 @@
 package mcptoolset
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
 	"google.golang.org/adk/tool"
 )
 
@@ -47,11 +50,20 @@ import (
 //		},
 //	})
 func New(cfg Config) (tool.Toolset, error) {
+	transport := cfg.Transport
+	if cfg.AuthConfig != nil {
+		var err error
+		transport, err = withAuthTransport(transport, cfg.AuthConfig.AuthScheme)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &set{
-		mcpClient:                   newConnectionRefresher(cfg.Client, cfg.Transport),
+		mcpClient:                   newConnectionRefresher(cfg.Client, transport),
 		toolFilter:                  cfg.ToolFilter,
 		requireConfirmation:         cfg.RequireConfirmation,
 		requireConfirmationProvider: cfg.RequireConfirmationProvider,
+		authConfig:                  cfg.AuthConfig,
 	}, nil
 }
 
@@ -83,6 +95,18 @@ type Config struct {
 	// func(name string, toolInput any) bool
 	// Returning true means confirmation is required.
 	RequireConfirmationProvider ConfirmationProvider
+
+	// AuthConfig describes the credential used to connect to the MCP server.
+	// The credential is added to the HTTP requests initializing the session,
+	// listing and calling the tools, so it requires a
+	// StreamableClientTransport or SSEClientTransport.
+	//
+	// If the credential needs the end user's consent (OAuth2 authorization
+	// code flow), it is requested from the client on the first tool call,
+	// see tool.Context.RequestCredential, and stored in the runner's
+	// auth.CredentialService for later invocations. Until then, the tools
+	// are listed without credential.
+	AuthConfig *auth.AuthConfig
 }
 
 type set struct {
@@ -90,6 +114,7 @@ type set struct {
 	toolFilter                  tool.Predicate
 	requireConfirmation         bool
 	requireConfirmationProvider ConfirmationProvider
+	authConfig                  *auth.AuthConfig
 }
 
 func (*set) Name() string {
@@ -106,14 +131,24 @@ func (*set) IsLongRunning() bool {
 
 // Tools fetch MCP tools from the server, convert to adk tool.Tool and filter by name.
 func (s *set) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
-	mcpTools, err := s.mcpClient.ListTools(ctx)
+	var listCtx context.Context = ctx
+	if s.authConfig != nil {
+		cred, err := toolutils.LoadCredential(ctx, s.authConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential to list MCP tools: %w", err)
+		}
+		if cred != nil {
+			listCtx = withCredential(ctx, cred)
+		}
+	}
+	mcpTools, err := s.mcpClient.ListTools(listCtx)
 	if err != nil {
 		return nil, err
 	}
 
 	var adkTools []tool.Tool
 	for _, mcpTool := range mcpTools {
-		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider)
+		t, err := convertTool(mcpTool, s.mcpClient, s.requireConfirmation, s.requireConfirmationProvider, s.authConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert MCP tool %q to adk tool: %w", mcpTool.Name, err)
 		}
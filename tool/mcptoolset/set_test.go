@@ -21,8 +21,11 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -31,6 +34,7 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/auth"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/httprr"
 	"google.golang.org/adk/internal/testutil"
@@ -790,3 +794,95 @@ func TestNewToolSet_RequireConfirmationProvider_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestMCPToolSetAuth(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test_server", Version: "v1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: "returns weather in the given city"}, weatherFunc)
+	mcpHandler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	var mu sync.Mutex
+	var apiKeys []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		apiKeys = append(apiKeys, r.Header.Get("X-Api-Key"))
+		mu.Unlock()
+		mcpHandler.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	apiKeyConfig := &auth.AuthConfig{
+		AuthScheme:        &auth.AuthScheme{Type: auth.SchemeTypeAPIKey, Name: "X-Api-Key", In: "header"},
+		RawAuthCredential: &auth.AuthCredential{AuthType: auth.CredentialTypeAPIKey, APIKey: "secret"},
+	}
+	oauth2Config := &auth.AuthConfig{
+		AuthScheme: &auth.AuthScheme{
+			Type: auth.SchemeTypeOAuth2,
+			Flows: &auth.OAuthFlows{AuthorizationCode: &auth.OAuthFlow{
+				AuthorizationURL: "https://example.com/authorize",
+				TokenURL:         "https://example.com/token",
+			}},
+		},
+		RawAuthCredential: &auth.AuthCredential{
+			AuthType: auth.CredentialTypeOAuth2,
+			OAuth2:   &auth.OAuth2Auth{ClientID: "client-id", ClientSecret: "client-secret"},
+		},
+	}
+
+	runTool := func(t *testing.T, authConfig *auth.AuthConfig) (map[string]any, tool.Context, error) {
+		t.Helper()
+		ts, err := mcptoolset.New(mcptoolset.Config{
+			Transport:  &mcp.StreamableClientTransport{Endpoint: httpServer.URL},
+			AuthConfig: authConfig,
+		})
+		if err != nil {
+			t.Fatalf("mcptoolset.New() failed: %v", err)
+		}
+		invCtx := icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{})
+		tools, err := ts.Tools(icontext.NewReadonlyContext(invCtx))
+		if err != nil {
+			t.Fatalf("Tools() failed: %v", err)
+		}
+		toolCtx := toolinternal.NewToolContext(invCtx, "fn1", nil, nil)
+		result, err := tools[0].(toolinternal.FunctionTool).Run(toolCtx, map[string]any{"city": "Paris"})
+		return result, toolCtx, err
+	}
+
+	t.Run("api key", func(t *testing.T) {
+		mu.Lock()
+		apiKeys = nil
+		mu.Unlock()
+
+		if _, _, err := runTool(t, apiKeyConfig); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(apiKeys) == 0 || slices.ContainsFunc(apiKeys, func(key string) bool { return key != "secret" }) {
+			t.Errorf("MCP server received API keys %q, want the session init, tool listing and tool call to be authenticated", apiKeys)
+		}
+	})
+
+	t.Run("oauth2 requests credential", func(t *testing.T) {
+		_, toolCtx, err := runTool(t, oauth2Config)
+		if err == nil {
+			t.Fatal("Run() succeeded, want error for pending authorization")
+		}
+		requested, ok := toolCtx.Actions().RequestedAuthConfigs["fn1"]
+		if !ok {
+			t.Fatalf("RequestedAuthConfigs = %v, want a request for the function call", toolCtx.Actions().RequestedAuthConfigs)
+		}
+		if requested.ExchangedAuthCredential == nil || requested.ExchangedAuthCredential.OAuth2.AuthURI == "" {
+			t.Errorf("requested auth config has no AuthURI: %+v", requested)
+		}
+		if !toolCtx.Actions().SkipSummarization {
+			t.Error("SkipSummarization = false, want true")
+		}
+	})
+
+	t.Run("unsupported transport", func(t *testing.T) {
+		clientTransport, _ := mcp.NewInMemoryTransports()
+		if _, err := mcptoolset.New(mcptoolset.Config{Transport: clientTransport, AuthConfig: apiKeyConfig}); err == nil {
+			t.Error("mcptoolset.New() succeeded, want error for a transport without HTTP client")
+		}
+	})
+}
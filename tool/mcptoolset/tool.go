@@ -15,6 +15,7 @@
 package mcptoolset
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -22,13 +23,14 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/internal/toolinternal/toolutils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 )
 
-func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider ConfirmationProvider) (tool.Tool, error) {
+func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requireConfirmationProvider ConfirmationProvider, authConfig *auth.AuthConfig) (tool.Tool, error) {
 	mcp := &mcpTool{
 		name:        t.Name,
 		description: t.Description,
@@ -39,6 +41,7 @@ func convertTool(t *mcp.Tool, client MCPClient, requireConfirmation bool, requir
 		mcpClient:                   client,
 		requireConfirmation:         requireConfirmation,
 		requireConfirmationProvider: requireConfirmationProvider,
+		authConfig:                  authConfig,
 	}
 
 	// Since t.InputSchema and t.OutputSchema are pointers (*jsonschema.Schema) and the destination ResponseJsonSchema
@@ -65,6 +68,8 @@ type mcpTool struct {
 	requireConfirmation bool
 
 	requireConfirmationProvider ConfirmationProvider
+
+	authConfig *auth.AuthConfig
 }
 
 // Name implements the tool.Tool.
@@ -116,8 +121,19 @@ func (t *mcpTool) Run(ctx tool.Context, args any) (map[string]any, error) {
 		}
 	}
 
-	// TODO: add auth
-	res, err := t.mcpClient.CallTool(ctx, &mcp.CallToolParams{
+	var callCtx context.Context = ctx
+	if t.authConfig != nil {
+		cred, err := toolutils.ResolveCredential(ctx, t.authConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential for MCP tool %q: %w", t.name, err)
+		}
+		if cred == nil {
+			return nil, fmt.Errorf("error tool %q requires user authorization, please authorize the access", t.Name())
+		}
+		callCtx = withCredential(ctx, cred)
+	}
+
+	res, err := t.mcpClient.CallTool(callCtx, &mcp.CallToolParams{
 		Name:      t.name,
 		Arguments: args,
 	})
@@ -21,6 +21,7 @@ import (
 	"context"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool/toolconfirmation"
@@ -87,6 +88,33 @@ type Context interface {
 	//   - error: If there was a failure in initiating the confirmation process itself (e.g., invalid
 	//     arguments, issue with the event system). The request to ask the user has not been sent.
 	RequestConfirmation(hint string, payload any) error
+
+	// RequestCredential asks the client for the end user credential described
+	// by the auth config, e.g. to go through the OAuth2 authorization code flow.
+	//
+	// This results in the ADK emitting a FunctionCall named "adk_request_credential"
+	// (see auth.FunctionCallName) to the client. Once the client posts back the
+	// credential, the tool is called again and can read it with AuthResponse.
+	// The tool should return a result telling the model the authorization is
+	// pending after calling this method.
+	RequestCredential(cfg *auth.AuthConfig) error
+
+	// AuthResponse returns the credential posted back by the client in reply to
+	// RequestCredential for the auth config, or nil if there is none. OAuth2
+	// authorization codes are already exchanged for tokens.
+	//
+	// The credential is only available in the tool call resumed after the
+	// client's response. Use SaveCredential to keep it for later invocations.
+	AuthResponse(cfg *auth.AuthConfig) *auth.AuthCredential
+
+	// LoadCredential returns the credential stored for the auth config and
+	// the current user in the runner's auth.CredentialService. It returns nil
+	// if no credential was stored or no credential service is configured.
+	LoadCredential(ctx context.Context, cfg *auth.AuthConfig) (*auth.AuthCredential, error)
+
+	// SaveCredential stores the ExchangedAuthCredential of the auth config for
+	// the current user in the runner's auth.CredentialService.
+	SaveCredential(ctx context.Context, cfg *auth.AuthConfig) error
 }
 
 // Toolset is an interface for a collection of tools. It allows grouping